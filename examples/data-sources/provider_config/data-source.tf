@@ -0,0 +1,5 @@
+data "supabase-vault_provider_config" "current" {}
+
+output "vault_database" {
+  value = "${data.supabase-vault_provider_config.current.user}@${data.supabase-vault_provider_config.current.host}:${data.supabase-vault_provider_config.current.port}/${data.supabase-vault_provider_config.current.database}"
+}
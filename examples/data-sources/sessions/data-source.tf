@@ -0,0 +1,9 @@
+# Connections the provider holds open right now
+data "supabase-vault_sessions" "provider" {}
+
+output "provider_connections" {
+  value = {
+    total               = data.supabase-vault_sessions.provider.total
+    idle_in_transaction = data.supabase-vault_sessions.provider.idle_in_transaction
+  }
+}
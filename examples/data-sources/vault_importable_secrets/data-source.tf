@@ -0,0 +1,9 @@
+# Bring every existing secret under Terraform (Terraform 1.7 or later)
+data "supabase-vault_importable_secrets" "all" {}
+
+import {
+  for_each = data.supabase-vault_importable_secrets.all.import_ids
+
+  to = supabase-vault_secret.imported[each.key]
+  id = each.value
+}
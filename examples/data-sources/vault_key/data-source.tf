@@ -0,0 +1,9 @@
+data "supabase-vault_key" "app" {
+  name = "app-secrets"
+}
+
+resource "supabase-vault_secret" "api_key" {
+  name   = "api_key"
+  value  = var.api_key
+  key_id = data.supabase-vault_key.app.id
+}
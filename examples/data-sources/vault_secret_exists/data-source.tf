@@ -0,0 +1,11 @@
+# Create the secret only when it doesn't exist yet
+data "supabase-vault_secret_exists" "api_key" {
+  name = "api_key"
+}
+
+resource "supabase-vault_secret" "api_key" {
+  count = data.supabase-vault_secret_exists.api_key.exists ? 0 : 1
+
+  name  = "api_key"
+  value = var.api_key
+}
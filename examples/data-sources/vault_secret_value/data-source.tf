@@ -0,0 +1,5 @@
+# Fall back to a placeholder in environments where the secret hasn't been created
+data "supabase-vault_secret_value" "feature_flag_key" {
+  name    = "feature_flag_key"
+  default = "disabled"
+}
@@ -0,0 +1,9 @@
+data "supabase-vault_secrets" "all" {}
+
+output "secret_names" {
+  value = [for secret in data.supabase-vault_secrets.all.secrets : secret.name]
+}
+
+output "secret_inventory" {
+  value = jsondecode(data.supabase-vault_secrets.all.json)
+}
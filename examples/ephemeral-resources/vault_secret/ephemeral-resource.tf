@@ -0,0 +1,10 @@
+ephemeral "supabase-vault_secret" "upstream_token" {
+  name = "upstream_token"
+}
+
+# Copy the value into another secret without it ever touching plan or state
+resource "supabase-vault_secret" "service_token" {
+  name             = "service_token"
+  value_wo         = ephemeral.supabase-vault_secret.upstream_token.value
+  value_wo_version = 1
+}
@@ -0,0 +1,4 @@
+resource "supabase-vault_secret" "webhook_signing_key" {
+  name  = "webhook-signing-key"
+  value = provider::supabase-vault::decode_secret(var.webhook_signing_key_hex, "hex")
+}
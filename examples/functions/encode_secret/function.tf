@@ -0,0 +1,4 @@
+resource "supabase-vault_secret" "service_account" {
+  name  = "service-account-key"
+  value = provider::supabase-vault::encode_secret(file("${path.module}/service-account.json"), "base64")
+}
@@ -0,0 +1,5 @@
+resource "supabase-vault_secret" "stripe_key" {
+  # Stored as "prod_stripe_key"
+  name  = provider::supabase-vault::secret_name("prod", "stripe", "key")
+  value = var.stripe_key
+}
@@ -0,0 +1,18 @@
+provider "supabase-vault" {
+  host          = "db.example.supabase.co"
+  password      = var.postgres_password
+  session_label = "terraform-platform"
+}
+
+# Terminate sessions left open by earlier, aborted runs on every apply
+resource "supabase-vault_session_cleanup" "stale" {
+  min_idle = "15m"
+
+  triggers = {
+    run = timestamp()
+  }
+}
+
+output "terminated_sessions" {
+  value = supabase-vault_session_cleanup.stale.terminated
+}
@@ -0,0 +1,15 @@
+resource "supabase-vault_bulk_secrets" "app" {
+  description = "Application credentials"
+
+  secrets = {
+    stripe_key   = var.stripe_key
+    sendgrid_key = var.sendgrid_key
+    sentry_dsn   = var.sentry_dsn
+  }
+}
+
+# Load the secrets from a JSON or YAML manifest instead
+resource "supabase-vault_bulk_secrets" "manifest" {
+  description  = "Secrets from the manifest"
+  secrets_file = "${path.module}/secrets.yaml"
+}
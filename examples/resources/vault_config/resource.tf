@@ -0,0 +1,6 @@
+# Requires owning the database or superuser
+resource "supabase-vault_config" "this" {
+  settings = {
+    "pgsodium.enable_event_trigger" = "off"
+  }
+}
@@ -0,0 +1,15 @@
+data "supabase-vault_key" "current" {
+  name = "app-secrets-2025"
+}
+
+resource "supabase-vault_key_rotation" "app" {
+  name            = "app-secrets-2026"
+  previous_key_id = data.supabase-vault_key.current.id
+}
+
+# Secrets pointed at the new key are re-encrypted under it
+resource "supabase-vault_secret" "api_key" {
+  name   = "api_key"
+  value  = var.api_key
+  key_id = supabase-vault_key_rotation.app.id
+}
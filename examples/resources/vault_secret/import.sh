@@ -1 +1,12 @@
-terraform import supabase-vault_secret.api_key "api_key"
\ No newline at end of file
+# Import by secret name
+terraform import supabase-vault_secret.api_key "api_key"
+
+# Import by secret UUID
+terraform import supabase-vault_secret.api_key "6f1c1c0e-2b5a-4f8e-9d3c-1a2b3c4d5e6f"
+
+# Import by name, asserting the secret is encrypted with the given key
+terraform import supabase-vault_secret.api_key "api_key|4a1d1e2f-3b4c-5d6e-7f80-91a2b3c4d5e6"
+
+# Import by name along with the decrypted value, e.g. for development
+# environments. This stores the value in plaintext in the Terraform state.
+terraform import supabase-vault_secret.api_key "api_key!withvalue"
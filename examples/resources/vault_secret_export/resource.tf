@@ -0,0 +1,5 @@
+# Keep a committed manifest of the secrets this provider manages. Values are
+# never written.
+resource "supabase-vault_secret_export" "manifest" {
+  path = "${path.module}/secrets.manifest.json"
+}
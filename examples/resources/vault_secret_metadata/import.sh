@@ -0,0 +1,5 @@
+# Import by secret name
+terraform import supabase-vault_secret_metadata.api_key "api_key"
+
+# Import by secret UUID
+terraform import supabase-vault_secret_metadata.api_key "6f1c1c0e-2b5a-4f8e-9d3c-1a2b3c4d5e6f"
@@ -0,0 +1,6 @@
+# The value of "api_key" is rotated by another process; Terraform only owns
+# its name and description
+resource "supabase-vault_secret_metadata" "api_key" {
+  name        = "api_key"
+  description = "Payments API key, rotated by the key service"
+}
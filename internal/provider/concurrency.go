@@ -0,0 +1,41 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// newOperationLimit returns the semaphore bounding concurrent operations to
+// limit, or nil for no limit.
+func newOperationLimit(limit int64) chan struct{} {
+	if limit <= 0 {
+		return nil
+	}
+
+	return make(chan struct{}, limit)
+}
+
+// beginOperation waits for one of the max_concurrent_operations slots and
+// returns the function releasing it. When ctx ends first, an error is added to
+// diags and ok is false.
+func (d *ProviderData) beginOperation(ctx context.Context, diags *diag.Diagnostics) (release func(), ok bool) {
+	if d.operations == nil {
+		return func() {}, true
+	}
+
+	select {
+	case d.operations <- struct{}{}:
+		return func() { <-d.operations }, true
+	case <-ctx.Done():
+		diags.AddError(
+			"Operation canceled",
+			fmt.Sprintf("Canceled while waiting for one of the %d max_concurrent_operations slots: %s", cap(d.operations), ctx.Err()),
+		)
+		return nil, false
+	}
+}
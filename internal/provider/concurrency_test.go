@@ -0,0 +1,48 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+func TestBeginOperation(t *testing.T) {
+	t.Run("unlimited", func(t *testing.T) {
+		d := &ProviderData{operations: newOperationLimit(0)}
+
+		var diags diag.Diagnostics
+		for i := 0; i < 3; i++ {
+			if _, ok := d.beginOperation(context.Background(), &diags); !ok {
+				t.Fatalf("unexpected error: %v", diags)
+			}
+		}
+	})
+
+	t.Run("limited", func(t *testing.T) {
+		d := &ProviderData{operations: newOperationLimit(1)}
+
+		var diags diag.Diagnostics
+		release, ok := d.beginOperation(context.Background(), &diags)
+		if !ok {
+			t.Fatalf("unexpected error: %v", diags)
+		}
+
+		// The only slot is taken, so waiting ends with the context
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if _, ok := d.beginOperation(ctx, &diags); ok || !diags.HasError() {
+			t.Fatal("expected an error while the slot is taken")
+		}
+
+		release()
+
+		diags = nil
+		if _, ok := d.beginOperation(context.Background(), &diags); !ok {
+			t.Fatalf("unexpected error after releasing the slot: %v", diags)
+		}
+	})
+}
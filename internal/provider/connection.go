@@ -0,0 +1,408 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// connectionParamKeyPattern matches libpq/PostgreSQL parameter names.
+var connectionParamKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_.]*$`)
+
+// databaseNamePattern matches database names that are safe to place in a
+// connection string without quoting or escaping.
+var databaseNamePattern = regexp.MustCompile(`^[A-Za-z0-9_$.-]+$`)
+
+// maxIdentifierLength is the longest identifier PostgreSQL accepts (NAMEDATALEN - 1).
+const maxIdentifierLength = 63
+
+// reservedConnectionParams are configured through dedicated provider
+// attributes and can't be overridden through connection_params.
+var reservedConnectionParams = map[string]bool{
+	"host":     true,
+	"hostaddr": true,
+	"port":     true,
+	"dbname":   true,
+	"database": true,
+	"user":     true,
+	"password": true,
+	"passfile": true,
+	"service":  true,
+}
+
+// sanitizeConnectionParams validates free-form connection parameters and
+// returns them as query values for the connection string.
+func sanitizeConnectionParams(params map[string]string) (url.Values, error) {
+	values := url.Values{}
+
+	// Sort keys so errors are reported deterministically
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := params[key]
+
+		if !connectionParamKeyPattern.MatchString(key) {
+			return nil, fmt.Errorf("invalid parameter name %q: names must be lowercase letters, digits, underscores or dots", key)
+		}
+		if reservedConnectionParams[key] {
+			return nil, fmt.Errorf("parameter %q must be set through its provider attribute instead", key)
+		}
+		if strings.ContainsRune(value, 0) {
+			return nil, fmt.Errorf("value of parameter %q contains a NUL character", key)
+		}
+
+		values.Set(key, value)
+	}
+
+	return values, nil
+}
+
+// mergeConnectionQuery parses query, a connection string query such as
+// "connect_timeout=5&target_session_attrs=read-write", and merges its
+// parameters into params. A parameter may only be set once across both.
+func mergeConnectionQuery(query string, params map[string]string) (map[string]string, error) {
+	values, err := url.ParseQuery(strings.TrimPrefix(query, "?"))
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse connection query: %w", err)
+	}
+
+	merged := make(map[string]string, len(params)+len(values))
+	for key, value := range params {
+		merged[key] = value
+	}
+
+	for key, value := range values {
+		if len(value) > 1 {
+			return nil, fmt.Errorf("parameter %q is set more than once", key)
+		}
+		if _, ok := params[key]; ok {
+			return nil, fmt.Errorf("parameter %q is set in both connection_query and connection_params", key)
+		}
+
+		merged[key] = value[0]
+	}
+
+	return merged, nil
+}
+
+// validateDatabaseName returns an error if name isn't a database name the
+// provider can safely put in a connection string.
+func validateDatabaseName(name string) error {
+	if name == "" {
+		return fmt.Errorf("database name must not be empty")
+	}
+	if len(name) > maxIdentifierLength {
+		return fmt.Errorf("database name %q is longer than %d bytes", name, maxIdentifierLength)
+	}
+	if !databaseNamePattern.MatchString(name) {
+		return fmt.Errorf("database name %q contains invalid characters: only letters, digits, '_', '$', '.' and '-' are allowed", name)
+	}
+
+	return nil
+}
+
+// Where a connection setting came from when it isn't an environment
+// variable, logged by Configure.
+const (
+	settingSourceConfig   = "config"
+	settingSourceService  = "service"
+	settingSourceEndpoint = "endpoint"
+	settingSourceDefault  = "default"
+)
+
+// libpqSetting resolves a connection setting like libpq: the configured
+// value, then the environment variable env, then def. It also returns where
+// the value came from.
+func libpqSetting(configured types.String, env, def string) (string, string) {
+	if !configured.IsNull() {
+		return configured.ValueString(), settingSourceConfig
+	}
+
+	if value := os.Getenv(env); value != "" {
+		return value, env
+	}
+
+	return def, settingSourceDefault
+}
+
+// parseEnvPort parses the port given by the PGPORT environment variable.
+func parseEnvPort(value string) (int64, error) {
+	port, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || port < 1 || port > 65535 {
+		return 0, fmt.Errorf("PGPORT must be a port number between 1 and 65535, got: %q", value)
+	}
+
+	return port, nil
+}
+
+// parseConnectJitter parses connect_jitter, which must not be negative.
+func parseConnectJitter(value string) (time.Duration, error) {
+	jitter, err := time.ParseDuration(value)
+	if err != nil || jitter < 0 {
+		return 0, fmt.Errorf("expected a duration of zero or more such as \"5s\", got: %q", value)
+	}
+
+	return jitter, nil
+}
+
+// connectJitterDelay returns a random delay below jitter, spread evenly so
+// that concurrent runs connect at different times.
+func connectJitterDelay(jitter time.Duration) time.Duration {
+	if jitter <= 0 {
+		return 0
+	}
+
+	return rand.N(jitter)
+}
+
+// parseHost normalizes the host attribute, which may carry a scheme, a port
+// and a database, e.g. postgres://db.example.supabase.co:5432/postgres. A
+// port or database found in host overrides the given defaults.
+func parseHost(host string, port int64, database string) (string, int64, string) {
+	// Strip protocol prefix from host if present (e.g., https:// or http://)
+	host = strings.TrimPrefix(host, "https://")
+	host = strings.TrimPrefix(host, "http://")
+	host = strings.TrimPrefix(host, "postgres://")
+	host = strings.TrimPrefix(host, "postgresql://")
+	// Remove trailing slash if present
+	host = strings.TrimSuffix(host, "/")
+
+	// Parse host to extract just the hostname (in case port/database are included)
+	// Handle formats like: hostname, hostname:port, hostname:port/database
+	hostname := host
+	parsedPort := port
+	parsedDatabase := database
+
+	// Check if host contains port (format: hostname:port or hostname:port/database)
+	if strings.Contains(host, ":") {
+		parts := strings.SplitN(host, ":", 2)
+		hostname = parts[0]
+		remaining := parts[1]
+
+		// Check if remaining part contains database (format: port/database)
+		if strings.Contains(remaining, "/") {
+			dbParts := strings.SplitN(remaining, "/", 2)
+			if portStr := dbParts[0]; portStr != "" {
+				// Port is already in host, use it
+				if parsedPortInt, err := strconv.ParseInt(portStr, 10, 64); err == nil {
+					parsedPort = parsedPortInt
+				}
+			}
+			if dbName := dbParts[1]; dbName != "" {
+				// Database is already in host, use it
+				parsedDatabase = dbName
+			}
+		} else {
+			// Only port, no database
+			if portStr := remaining; portStr != "" {
+				if parsedPortInt, err := strconv.ParseInt(portStr, 10, 64); err == nil {
+					parsedPort = parsedPortInt
+				}
+			}
+		}
+	} else if strings.Contains(host, "/") {
+		// Host contains database but no port (format: hostname/database)
+		parts := strings.SplitN(host, "/", 2)
+		hostname = parts[0]
+		if dbName := parts[1]; dbName != "" {
+			parsedDatabase = dbName
+		}
+	}
+
+	return hostname, parsedPort, parsedDatabase
+}
+
+// validateEndpoint checks that endpoint is a host:port pair and returns its port.
+func validateEndpoint(endpoint string) (int64, error) {
+	host, portStr, err := net.SplitHostPort(endpoint)
+	if err != nil {
+		return 0, fmt.Errorf("endpoint %q must be in host:port form: %s", endpoint, err)
+	}
+	if host == "" {
+		return 0, fmt.Errorf("endpoint %q is missing a host", endpoint)
+	}
+
+	port, err := strconv.ParseInt(portStr, 10, 64)
+	if err != nil || port < 1 || port > 65535 {
+		return 0, fmt.Errorf("endpoint %q has an invalid port %q", endpoint, portStr)
+	}
+
+	return port, nil
+}
+
+// sslModes are the sslmode values libpq accepts.
+var sslModes = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+
+// validateSSLMode returns an error if mode isn't an sslmode libpq accepts.
+func validateSSLMode(mode string) error {
+	for _, valid := range sslModes {
+		if mode == valid {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("sslmode %q is not supported, expected one of: %s", mode, strings.Join(sslModes, ", "))
+}
+
+// channelBindings are the channel_binding values libpq accepts.
+var channelBindings = []string{"disable", "prefer", "require"}
+
+// validateChannelBinding returns an error if binding isn't a channel_binding
+// libpq accepts.
+func validateChannelBinding(binding string) error {
+	for _, valid := range channelBindings {
+		if binding == valid {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("channel_binding %q is not supported, expected one of: %s", binding, strings.Join(channelBindings, ", "))
+}
+
+// sslNegotiations are the sslnegotiation values libpq accepts.
+var sslNegotiations = []string{"postgres", "direct"}
+
+// validateSSLNegotiation returns an error if negotiation isn't an
+// sslnegotiation libpq accepts.
+func validateSSLNegotiation(negotiation string) error {
+	for _, valid := range sslNegotiations {
+		if negotiation == valid {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("ssl_negotiation %q is not supported, expected one of: %s", negotiation, strings.Join(sslNegotiations, ", "))
+}
+
+// queryExecModes are the query_exec_mode values, in the order pgx documents
+// the matching QueryExecMode constants.
+var queryExecModes = []string{"cache_statement", "cache_describe", "describe_exec", "exec", "simple"}
+
+// parseQueryExecMode returns the pgx QueryExecMode named by mode.
+func parseQueryExecMode(mode string) (pgx.QueryExecMode, error) {
+	switch mode {
+	case "cache_statement":
+		return pgx.QueryExecModeCacheStatement, nil
+	case "cache_describe":
+		return pgx.QueryExecModeCacheDescribe, nil
+	case "describe_exec":
+		return pgx.QueryExecModeDescribeExec, nil
+	case "exec":
+		return pgx.QueryExecModeExec, nil
+	case "simple":
+		return pgx.QueryExecModeSimpleProtocol, nil
+	}
+
+	return 0, fmt.Errorf("query_exec_mode %q is not supported, expected one of: %s", mode, strings.Join(queryExecModes, ", "))
+}
+
+// supabasePoolerHostSuffix ends the hostnames of Supabase's shared pooler,
+// e.g. aws-0-us-east-1.pooler.supabase.com.
+const supabasePoolerHostSuffix = ".pooler.supabase.com"
+
+// supabaseSessionPoolerPort is the port the shared pooler serves session
+// mode on, which supports prepared statements like a direct connection.
+const supabaseSessionPoolerPort = 5432
+
+// Pooling modes detectPoolerMode recognizes.
+const (
+	poolerModeTransaction = "transaction"
+	poolerModeSession     = "session"
+)
+
+// detectPoolerMode guesses the Supabase pooling mode a connection to host
+// and port goes through: transaction mode on port 6543 or an unrecognized
+// port of the shared pooler, session mode on its port 5432, and an empty
+// string when the host isn't a pooler at all.
+func detectPoolerMode(host string, port uint16) string {
+	poolerHost := strings.HasSuffix(strings.ToLower(strings.TrimSuffix(host, ".")), supabasePoolerHostSuffix)
+
+	switch {
+	case port == supabasePoolerPort:
+		return poolerModeTransaction
+	case poolerHost && port == supabaseSessionPoolerPort:
+		return poolerModeSession
+	case poolerHost:
+		return poolerModeTransaction
+	}
+
+	return ""
+}
+
+// parseKeepAliveDuration parses a TCP keepalive duration, which must be
+// positive.
+func parseKeepAliveDuration(value string) (time.Duration, error) {
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		return 0, fmt.Errorf("expected a positive duration such as \"30s\", got: %q", value)
+	}
+
+	return duration, nil
+}
+
+// keepAliveDialFunc returns a dial function that enables TCP keepalive
+// with the given idle time before the first probe and interval between
+// probes. A zero idle or interval uses Go's default of 15 seconds. The
+// connect timeout is kept from the parsed connection configuration.
+func keepAliveDialFunc(connectTimeout, idle, interval time.Duration) pgconn.DialFunc {
+	dialer := &net.Dialer{
+		Timeout: connectTimeout,
+		KeepAliveConfig: net.KeepAliveConfig{
+			Enable:   true,
+			Idle:     idle,
+			Interval: interval,
+		},
+	}
+
+	return dialer.DialContext
+}
+
+// isLocalHost reports whether host is the local machine, including a Unix
+// socket directory.
+func isLocalHost(host string) bool {
+	if host == "localhost" || strings.HasPrefix(host, "/") {
+		return true
+	}
+
+	ip := net.ParseIP(host)
+
+	return ip != nil && ip.IsLoopback()
+}
+
+// mayConnectWithoutTLS reports whether config can end up on an unencrypted
+// connection to a remote host: with sslmode disable, or allow and prefer
+// (libpq's default), which fall back to plaintext.
+func mayConnectWithoutTLS(config *pgconn.Config) bool {
+	if isLocalHost(config.Host) {
+		return false
+	}
+
+	if config.TLSConfig == nil {
+		return true
+	}
+	for _, fallback := range config.Fallbacks {
+		if fallback.TLSConfig == nil {
+			return true
+		}
+	}
+
+	return false
+}
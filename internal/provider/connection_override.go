@@ -0,0 +1,271 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// overridePoolMaxConns caps the connections of a pool opened for a
+// connection override. Few resources share one, so it is kept small.
+const overridePoolMaxConns = 2
+
+// overridePoolMaxConnIdleTime closes override connections soon after the
+// resources using them are done.
+const overridePoolMaxConnIdleTime = 30 * time.Second
+
+// ConnectionOverrideModel describes a resource-level connection override.
+type ConnectionOverrideModel struct {
+	Host     types.String `tfsdk:"host"`
+	Port     types.Int64  `tfsdk:"port"`
+	Database types.String `tfsdk:"database"`
+	User     types.String `tfsdk:"user"`
+	Password types.String `tfsdk:"password"`
+}
+
+// connectionOverrideAttrTypes are the attribute types of the connection
+// override object.
+var connectionOverrideAttrTypes = map[string]attr.Type{
+	"host":     types.StringType,
+	"port":     types.Int64Type,
+	"database": types.StringType,
+	"user":     types.StringType,
+	"password": types.StringType,
+}
+
+// connectionOverrideSchema returns the schema of the connection override
+// attribute. Moving a secret to another database replaces it, while a new
+// user or password only changes how the same database is reached.
+func connectionOverrideSchema() schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		MarkdownDescription: "Connect to a different database than the provider's for this resource. Attributes that aren't set are inherited from the provider. " +
+			"A dedicated, small connection pool is opened for every distinct override and shared by the resources using it. " +
+			"Changing `host`, `port` or `database` recreates the secret in the new database.",
+		Optional: true,
+		Attributes: map[string]schema.Attribute{
+			"host": schema.StringAttribute{
+				MarkdownDescription: "PostgreSQL host name",
+				Optional:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"port": schema.Int64Attribute{
+				MarkdownDescription: "PostgreSQL port number",
+				Optional:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"database": schema.StringAttribute{
+				MarkdownDescription: "PostgreSQL database name",
+				Optional:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"user": schema.StringAttribute{
+				MarkdownDescription: "PostgreSQL user",
+				Optional:            true,
+			},
+			"password": schema.StringAttribute{
+				MarkdownDescription: "PostgreSQL password",
+				Optional:            true,
+				Sensitive:           true,
+			},
+		},
+	}
+}
+
+// validateConnectionOverride adds an attribute error to diags for every
+// known override value that can't be used to connect.
+func validateConnectionOverride(ctx context.Context, override types.Object, diags *diag.Diagnostics) {
+	if override.IsNull() || override.IsUnknown() {
+		return
+	}
+
+	var data ConnectionOverrideModel
+	diags.Append(override.As(ctx, &data, basetypes.ObjectAsOptions{})...)
+
+	if diags.HasError() {
+		return
+	}
+
+	if data.Host.IsNull() && data.Port.IsNull() && data.Database.IsNull() && data.User.IsNull() && data.Password.IsNull() {
+		diags.AddAttributeError(
+			path.Root("connection"),
+			"Empty connection override",
+			"Set at least one attribute in connection, or remove it to use the provider's connection.",
+		)
+	}
+
+	if isKnown(data.Host) && strings.TrimSpace(data.Host.ValueString()) == "" {
+		diags.AddAttributeError(
+			path.Root("connection").AtName("host"),
+			"Invalid host",
+			"host must not be empty.",
+		)
+	}
+
+	if !data.Port.IsNull() && !data.Port.IsUnknown() {
+		if port := data.Port.ValueInt64(); port < 1 || port > 65535 {
+			diags.AddAttributeError(
+				path.Root("connection").AtName("port"),
+				"Invalid port",
+				fmt.Sprintf("port must be between 1 and 65535, got: %d", port),
+			)
+		}
+	}
+
+	if isKnown(data.Database) {
+		if err := validateDatabaseName(data.Database.ValueString()); err != nil {
+			diags.AddAttributeError(
+				path.Root("connection").AtName("database"),
+				"Invalid database name",
+				err.Error(),
+			)
+		}
+	}
+}
+
+// key identifies the pool an override connects through.
+func (m ConnectionOverrideModel) key() string {
+	password := sha256.Sum256([]byte(m.Password.ValueString()))
+
+	return strings.Join([]string{
+		m.Host.ValueString(),
+		m.Port.String(),
+		m.Database.ValueString(),
+		m.User.ValueString(),
+		hex.EncodeToString(password[:]),
+	}, "\x00")
+}
+
+// forConnection returns the provider data to use for a resource with the
+// given connection override: d itself without one, otherwise provider data
+// backed by a dedicated pool that is opened on first use and then cached.
+func (d *ProviderData) forConnection(ctx context.Context, override types.Object) (*ProviderData, error) {
+	if override.IsNull() || override.IsUnknown() {
+		return d, nil
+	}
+
+	if d.managementAPI != nil {
+		return nil, errNoDirectConnection
+	}
+
+	var data ConnectionOverrideModel
+	if diags := override.As(ctx, &data, basetypes.ObjectAsOptions{}); diags.HasError() {
+		return nil, fmt.Errorf("reading connection override: %v", diags)
+	}
+
+	key := data.key()
+
+	d.overridesMu.Lock()
+	defer d.overridesMu.Unlock()
+
+	if cached, ok := d.overrides[key]; ok {
+		return cached, nil
+	}
+
+	poolConfig := d.poolConfig.Copy()
+	poolConfig.MaxConns = overridePoolMaxConns
+	poolConfig.MaxConnIdleTime = overridePoolMaxConnIdleTime
+	// The override's sessions report the provider's session label, so they
+	// are tracked as the provider's own
+	poolConfig.AfterConnect = chainAfterConnect(sessionSQLAfterConnect(d.SessionSQL), d.trackConnection)
+	poolConfig.BeforeClose = d.untrackConnection
+
+	connConfig := poolConfig.ConnConfig
+	if !data.Host.IsNull() {
+		connConfig.Host = data.Host.ValueString()
+		connConfig.Fallbacks = nil
+		if connConfig.TLSConfig != nil {
+			connConfig.TLSConfig = connConfig.TLSConfig.Clone()
+			connConfig.TLSConfig.ServerName = connConfig.Host
+		}
+	}
+	if !data.Port.IsNull() {
+		connConfig.Port = uint16(data.Port.ValueInt64())
+	}
+	if (!data.Host.IsNull() || !data.Port.IsNull()) && !d.queryExecModeSet &&
+		detectPoolerMode(connConfig.Host, connConfig.Port) == poolerModeTransaction {
+		connConfig.DefaultQueryExecMode = pgx.QueryExecModeSimpleProtocol
+	}
+	if !data.Database.IsNull() {
+		connConfig.Database = data.Database.ValueString()
+	}
+	if !data.User.IsNull() {
+		connConfig.User = data.User.ValueString()
+	}
+	if !data.Password.IsNull() {
+		connConfig.Password = data.Password.ValueString()
+	}
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
+	if err != nil {
+		return nil, fmt.Errorf("creating connection pool for %s:%d/%s: %w", connConfig.Host, connConfig.Port, connConfig.Database, err)
+	}
+
+	overrideData := &ProviderData{
+		Pool:    pool,
+		Version: d.Version,
+
+		AcquireTimeout:        d.AcquireTimeout,
+		ReadOnly:              d.ReadOnly,
+		AutoReconnect:         d.AutoReconnect,
+		ErrorOnMissing:        d.ErrorOnMissing,
+		UseTransactions:       d.UseTransactions,
+		StrictKeyIDRead:       d.StrictKeyIDRead,
+		SkipPing:              d.SkipPing,
+		AllowInvalidUTF8Names: d.AllowInvalidUTF8Names,
+		EnvironmentNaming:     d.EnvironmentNaming,
+		AllowedNamePatterns:   d.AllowedNamePatterns,
+		SessionSQL:            d.SessionSQL,
+		CorrelationID:         d.CorrelationID,
+		DefaultDescription:    d.DefaultDescription,
+		FooterSeparator:       d.FooterSeparator,
+		ShowFooterOnRead:      d.ShowFooterOnRead,
+		SuppressStateWarnings: d.SuppressStateWarnings,
+		QueryComments:         d.QueryComments,
+		MaxValueBytes:         d.MaxValueBytes,
+		AllowEmptyValue:       d.AllowEmptyValue,
+		TimestampLocation:     d.TimestampLocation,
+	}
+	overrideData.poolConfig = poolConfig
+	// The limit applies to the provider as a whole, whichever pool is used
+	overrideData.operations = d.operations
+	overrideData.queryExecModeSet = d.queryExecModeSet
+	overrideData.simpleProtocol.Store(d.simpleProtocol.Load())
+
+	if d.overrides == nil {
+		d.overrides = map[string]*ProviderData{}
+	}
+	d.overrides[key] = overrideData
+
+	tflog.Debug(ctx, "Opened a connection pool for a resource connection override", map[string]interface{}{
+		"host":     connConfig.Host,
+		"port":     connConfig.Port,
+		"database": connConfig.Database,
+	})
+
+	return overrideData, nil
+}
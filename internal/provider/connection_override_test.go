@@ -0,0 +1,44 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestConnectionOverrideKey(t *testing.T) {
+	base := ConnectionOverrideModel{
+		Host:     types.StringValue("db.other.supabase.co"),
+		Port:     types.Int64Null(),
+		Database: types.StringValue("postgres"),
+		User:     types.StringNull(),
+		Password: types.StringValue("first"),
+	}
+
+	rotated := base
+	rotated.Password = types.StringValue("second")
+
+	if base.key() == rotated.key() {
+		t.Error("expected overrides with different passwords to use different pools")
+	}
+	if strings.Contains(base.key(), "first") {
+		t.Error("expected the pool key not to contain the password")
+	}
+}
+
+func TestForConnectionWithoutOverride(t *testing.T) {
+	data := &ProviderData{}
+
+	providerData, err := data.forConnection(context.Background(), types.ObjectNull(connectionOverrideAttrTypes))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if providerData != data {
+		t.Error("expected the provider's own connection without an override")
+	}
+}
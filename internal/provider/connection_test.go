@@ -0,0 +1,486 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestSanitizeConnectionParams(t *testing.T) {
+	testCases := map[string]struct {
+		params    map[string]string
+		expected  string
+		expectErr bool
+	}{
+		"empty": {
+			params:   map[string]string{},
+			expected: "",
+		},
+		"escaped values": {
+			params: map[string]string{
+				"target_session_attrs": "read-write",
+				"options":              "-c search_path=vault",
+			},
+			expected: "options=-c+search_path%3Dvault&target_session_attrs=read-write",
+		},
+		"invalid key": {
+			params:    map[string]string{"bad key&x=1": "value"},
+			expectErr: true,
+		},
+		"reserved key": {
+			params:    map[string]string{"password": "override"},
+			expectErr: true,
+		},
+		"reserved service": {
+			params:    map[string]string{"service": "supabase"},
+			expectErr: true,
+		},
+		"nul in value": {
+			params:    map[string]string{"options": "a\x00b"},
+			expectErr: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			values, err := sanitizeConnectionParams(testCase.params)
+
+			if testCase.expectErr {
+				if err == nil {
+					t.Fatal("expected error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if encoded := values.Encode(); encoded != testCase.expected {
+				t.Errorf("expected %q, got %q", testCase.expected, encoded)
+			}
+		})
+	}
+}
+
+func TestMergeConnectionQuery(t *testing.T) {
+	testCases := map[string]struct {
+		query     string
+		params    map[string]string
+		expected  map[string]string
+		expectErr bool
+	}{
+		"empty": {
+			query:    "",
+			expected: map[string]string{},
+		},
+		"decoded values": {
+			query: "connect_timeout=5&options=-c%20search_path%3Dvault",
+			expected: map[string]string{
+				"connect_timeout": "5",
+				"options":         "-c search_path=vault",
+			},
+		},
+		"leading question mark": {
+			query:    "?target_session_attrs=read-write",
+			expected: map[string]string{"target_session_attrs": "read-write"},
+		},
+		"merged with params": {
+			query:  "connect_timeout=5",
+			params: map[string]string{"target_session_attrs": "read-write"},
+			expected: map[string]string{
+				"connect_timeout":      "5",
+				"target_session_attrs": "read-write",
+			},
+		},
+		"set in both": {
+			query:     "connect_timeout=5",
+			params:    map[string]string{"connect_timeout": "10"},
+			expectErr: true,
+		},
+		"repeated parameter": {
+			query:     "connect_timeout=5&connect_timeout=10",
+			expectErr: true,
+		},
+		"invalid escape": {
+			query:     "options=%zz",
+			expectErr: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			merged, err := mergeConnectionQuery(testCase.query, testCase.params)
+
+			if testCase.expectErr {
+				if err == nil {
+					t.Fatal("expected error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if !reflect.DeepEqual(merged, testCase.expected) {
+				t.Errorf("expected %v, got %v", testCase.expected, merged)
+			}
+		})
+	}
+}
+
+func TestValidateDatabaseName(t *testing.T) {
+	testCases := map[string]struct {
+		name      string
+		expectErr bool
+	}{
+		"default":        {name: "postgres"},
+		"mixed":          {name: "app_db-2.test$"},
+		"empty":          {name: "", expectErr: true},
+		"query string":   {name: "postgres?sslmode=disable", expectErr: true},
+		"path separator": {name: "postgres/other", expectErr: true},
+		"whitespace":     {name: "my db", expectErr: true},
+		"quote":          {name: `pg"db`, expectErr: true},
+		"too long":       {name: strings.Repeat("a", 64), expectErr: true},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			err := validateDatabaseName(testCase.name)
+
+			if testCase.expectErr && err == nil {
+				t.Errorf("expected error for %q, got none", testCase.name)
+			}
+			if !testCase.expectErr && err != nil {
+				t.Errorf("unexpected error for %q: %s", testCase.name, err)
+			}
+		})
+	}
+}
+
+func TestParseHost(t *testing.T) {
+	testCases := map[string]struct {
+		host             string
+		expectedHostname string
+		expectedPort     int64
+		expectedDatabase string
+	}{
+		"hostname": {
+			host:             "db.example.supabase.co",
+			expectedHostname: "db.example.supabase.co",
+			expectedPort:     5432,
+			expectedDatabase: "postgres",
+		},
+		"scheme and trailing slash": {
+			host:             "https://db.example.supabase.co/",
+			expectedHostname: "db.example.supabase.co",
+			expectedPort:     5432,
+			expectedDatabase: "postgres",
+		},
+		"port": {
+			host:             "db.example.supabase.co:6543",
+			expectedHostname: "db.example.supabase.co",
+			expectedPort:     6543,
+			expectedDatabase: "postgres",
+		},
+		"port and database": {
+			host:             "postgresql://db.example.supabase.co:6543/app",
+			expectedHostname: "db.example.supabase.co",
+			expectedPort:     6543,
+			expectedDatabase: "app",
+		},
+		"database only": {
+			host:             "db.example.supabase.co/app",
+			expectedHostname: "db.example.supabase.co",
+			expectedPort:     5432,
+			expectedDatabase: "app",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			hostname, port, database := parseHost(testCase.host, 5432, "postgres")
+
+			if hostname != testCase.expectedHostname {
+				t.Errorf("expected hostname %q, got %q", testCase.expectedHostname, hostname)
+			}
+			if port != testCase.expectedPort {
+				t.Errorf("expected port %d, got %d", testCase.expectedPort, port)
+			}
+			if database != testCase.expectedDatabase {
+				t.Errorf("expected database %q, got %q", testCase.expectedDatabase, database)
+			}
+		})
+	}
+}
+
+func TestLibpqSetting(t *testing.T) {
+	testCases := map[string]struct {
+		configured     types.String
+		env            string
+		expected       string
+		expectedSource string
+	}{
+		"configured":            {configured: types.StringValue("vault_admin"), env: "ci_runner", expected: "vault_admin", expectedSource: settingSourceConfig},
+		"environment":           {configured: types.StringNull(), env: "ci_runner", expected: "ci_runner", expectedSource: "PGUSER"},
+		"default":               {configured: types.StringNull(), expected: "postgres", expectedSource: settingSourceDefault},
+		"configured over empty": {configured: types.StringValue(""), expected: "", expectedSource: settingSourceConfig},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("PGUSER", testCase.env)
+
+			value, source := libpqSetting(testCase.configured, "PGUSER", "postgres")
+
+			if value != testCase.expected {
+				t.Errorf("expected %q, got %q", testCase.expected, value)
+			}
+			if source != testCase.expectedSource {
+				t.Errorf("expected source %q, got %q", testCase.expectedSource, source)
+			}
+		})
+	}
+}
+
+func TestParseEnvPort(t *testing.T) {
+	testCases := map[string]struct {
+		value     string
+		expected  int64
+		expectErr bool
+	}{
+		"port":         {value: "6543", expected: 6543},
+		"not a number": {value: "postgres", expectErr: true},
+		"zero":         {value: "0", expectErr: true},
+		"out of range": {value: "70000", expectErr: true},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			port, err := parseEnvPort(testCase.value)
+
+			if testCase.expectErr {
+				if err == nil {
+					t.Fatalf("expected error for %q, got none", testCase.value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error for %q: %s", testCase.value, err)
+			}
+			if port != testCase.expected {
+				t.Errorf("expected port %d, got %d", testCase.expected, port)
+			}
+		})
+	}
+}
+
+func TestValidateEndpoint(t *testing.T) {
+	testCases := map[string]struct {
+		endpoint     string
+		expectedPort int64
+		expectErr    bool
+	}{
+		"hostname":     {endpoint: "replica.example.supabase.co:5432", expectedPort: 5432},
+		"ipv6":         {endpoint: "[2001:db8::1]:6543", expectedPort: 6543},
+		"missing port": {endpoint: "replica.example.supabase.co", expectErr: true},
+		"missing host": {endpoint: ":5432", expectErr: true},
+		"bad port":     {endpoint: "replica.example.supabase.co:http", expectErr: true},
+		"out of range": {endpoint: "replica.example.supabase.co:70000", expectErr: true},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			port, err := validateEndpoint(testCase.endpoint)
+
+			if testCase.expectErr {
+				if err == nil {
+					t.Fatalf("expected error for %q, got none", testCase.endpoint)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error for %q: %s", testCase.endpoint, err)
+			}
+			if port != testCase.expectedPort {
+				t.Errorf("expected port %d, got %d", testCase.expectedPort, port)
+			}
+		})
+	}
+}
+
+func TestParseConnectJitter(t *testing.T) {
+	testCases := map[string]struct {
+		value     string
+		expected  time.Duration
+		expectErr bool
+	}{
+		"seconds":  {value: "5s", expected: 5 * time.Second},
+		"zero":     {value: "0s", expected: 0},
+		"negative": {value: "-5s", expectErr: true},
+		"no unit":  {value: "5", expectErr: true},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			jitter, err := parseConnectJitter(testCase.value)
+
+			if testCase.expectErr {
+				if err == nil {
+					t.Fatalf("expected error for %q, got none", testCase.value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error for %q: %s", testCase.value, err)
+			}
+			if jitter != testCase.expected {
+				t.Errorf("expected %s, got %s", testCase.expected, jitter)
+			}
+		})
+	}
+}
+
+func TestConnectJitterDelay(t *testing.T) {
+	if delay := connectJitterDelay(0); delay != 0 {
+		t.Errorf("expected no delay without jitter, got %s", delay)
+	}
+
+	for range 100 {
+		if delay := connectJitterDelay(time.Second); delay < 0 || delay >= time.Second {
+			t.Fatalf("expected a delay below 1s, got %s", delay)
+		}
+	}
+}
+
+func TestParseKeepAliveDuration(t *testing.T) {
+	testCases := map[string]struct {
+		value     string
+		expected  time.Duration
+		expectErr bool
+	}{
+		"seconds":  {value: "30s", expected: 30 * time.Second},
+		"minutes":  {value: "2m", expected: 2 * time.Minute},
+		"zero":     {value: "0s", expectErr: true},
+		"negative": {value: "-5s", expectErr: true},
+		"no unit":  {value: "30", expectErr: true},
+		"empty":    {value: "", expectErr: true},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			duration, err := parseKeepAliveDuration(testCase.value)
+
+			if testCase.expectErr {
+				if err == nil {
+					t.Fatalf("expected error for %q, got none", testCase.value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error for %q: %s", testCase.value, err)
+			}
+			if duration != testCase.expected {
+				t.Errorf("expected %s, got %s", testCase.expected, duration)
+			}
+		})
+	}
+}
+
+func TestParseQueryExecMode(t *testing.T) {
+	testCases := map[string]struct {
+		mode      string
+		expected  pgx.QueryExecMode
+		expectErr bool
+	}{
+		"cache_statement": {mode: "cache_statement", expected: pgx.QueryExecModeCacheStatement},
+		"cache_describe":  {mode: "cache_describe", expected: pgx.QueryExecModeCacheDescribe},
+		"describe_exec":   {mode: "describe_exec", expected: pgx.QueryExecModeDescribeExec},
+		"exec":            {mode: "exec", expected: pgx.QueryExecModeExec},
+		"simple":          {mode: "simple", expected: pgx.QueryExecModeSimpleProtocol},
+		"pgx spelling":    {mode: "simple_protocol", expectErr: true},
+		"empty":           {mode: "", expectErr: true},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			mode, err := parseQueryExecMode(testCase.mode)
+
+			if testCase.expectErr {
+				if err == nil {
+					t.Fatalf("expected error for %q, got none", testCase.mode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error for %q: %s", testCase.mode, err)
+			}
+			if mode != testCase.expected {
+				t.Errorf("expected %s, got %s", testCase.expected, mode)
+			}
+		})
+	}
+}
+
+func TestDetectPoolerMode(t *testing.T) {
+	testCases := map[string]struct {
+		host     string
+		port     uint16
+		expected string
+	}{
+		"transaction port":          {host: "db.abcdefghijklmnop.supabase.co", port: 6543, expected: poolerModeTransaction},
+		"shared pooler session":     {host: "aws-0-us-east-1.pooler.supabase.com", port: 5432, expected: poolerModeSession},
+		"shared pooler transaction": {host: "aws-0-us-east-1.pooler.supabase.com", port: 6543, expected: poolerModeTransaction},
+		"shared pooler other port":  {host: "aws-0-us-east-1.pooler.supabase.com", port: 6544, expected: poolerModeTransaction},
+		"uppercase fqdn":            {host: "AWS-0-US-EAST-1.POOLER.SUPABASE.COM.", port: 5432, expected: poolerModeSession},
+		"direct connection":         {host: "db.abcdefghijklmnop.supabase.co", port: 5432},
+		"lookalike host":            {host: "pooler.supabase.com.example.org", port: 5432},
+		"localhost":                 {host: "localhost", port: 54322},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if mode := detectPoolerMode(testCase.host, testCase.port); mode != testCase.expected {
+				t.Errorf("expected %q, got %q", testCase.expected, mode)
+			}
+		})
+	}
+}
+
+func TestMayConnectWithoutTLS(t *testing.T) {
+	t.Setenv("PGSSLMODE", "")
+
+	testCases := map[string]struct {
+		connString string
+		expected   bool
+	}{
+		"default":        {connString: "postgres://postgres@db.example.supabase.co/postgres", expected: true},
+		"disable":        {connString: "postgres://postgres@db.example.supabase.co/postgres?sslmode=disable", expected: true},
+		"prefer":         {connString: "postgres://postgres@db.example.supabase.co/postgres?sslmode=prefer", expected: true},
+		"require":        {connString: "postgres://postgres@db.example.supabase.co/postgres?sslmode=require"},
+		"verify-full":    {connString: "postgres://postgres@db.example.supabase.co/postgres?sslmode=verify-full"},
+		"localhost":      {connString: "postgres://postgres@localhost/postgres?sslmode=disable"},
+		"loopback":       {connString: "postgres://postgres@127.0.0.1:54322/postgres"},
+		"ipv6 loopback":  {connString: "postgres://postgres@[::1]/postgres"},
+		"unix socket":    {connString: "postgres:///postgres?host=/var/run/postgresql"},
+		"remote address": {connString: "postgres://postgres@10.0.0.5/postgres?sslmode=disable", expected: true},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			config, err := pgconn.ParseConfig(testCase.connString)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if result := mayConnectWithoutTLS(config); result != testCase.expected {
+				t.Errorf("expected %t, got %t", testCase.expected, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// contentHashLabel is the label content-addressed secrets store the SHA-256
+// hex digest of their value under.
+const contentHashLabel = "content_sha256"
+
+// withContentHash returns a copy of labels with the content hash of value
+// added.
+func withContentHash(labels map[string]string, value string) map[string]string {
+	result := make(map[string]string, len(labels)+1)
+	for key, label := range labels {
+		result[key] = label
+	}
+	result[contentHashLabel] = hashSecretValue(value)
+
+	return result
+}
+
+// contentHashFragment returns the text the labels block of a secret storing
+// a value with the given hash contains, as appendLabels encodes it.
+func contentHashFragment(hash string) string {
+	// Marshalling a map of strings can't fail
+	encoded, _ := json.Marshal(map[string]string{contentHashLabel: hash})
+
+	// Strip the braces, as other labels may surround the pair
+	return string(encoded[1 : len(encoded)-1])
+}
+
+// lookupContentAddressedSecret returns the id and stored name of the oldest
+// secret labelled with the content hash of value, if any.
+func (r *VaultSecretResource) lookupContentAddressedSecret(ctx context.Context, value string) (string, string, bool, error) {
+	query := `
+		SELECT id, name
+		FROM vault.secrets
+		WHERE strpos(description, $1) > 0
+		ORDER BY created_at
+		LIMIT 1
+	`
+
+	var id, name string
+	err := r.providerData.queryRow(ctx, query, contentHashFragment(hashSecretValue(value))).Scan(&id, &name)
+	if err == pgx.ErrNoRows {
+		return "", "", false, nil
+	}
+	if err != nil {
+		return "", "", false, err
+	}
+
+	return id, name, true, nil
+}
@@ -0,0 +1,25 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestContentHashFragment(t *testing.T) {
+	labels := map[string]string{"owner": "platform", "zone": "eu"}
+
+	description := appendLabels("API key", withContentHash(labels, "secret"))
+	if !strings.Contains(description, contentHashFragment(hashSecretValue("secret"))) {
+		t.Errorf("expected %q to contain the content hash fragment", description)
+	}
+	if strings.Contains(description, contentHashFragment(hashSecretValue("other"))) {
+		t.Errorf("expected %q not to match another value's hash", description)
+	}
+
+	if _, ok := labels[contentHashLabel]; ok {
+		t.Error("expected the configured labels to be left unchanged")
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// correlationIDPattern matches correlation IDs that are safe to embed in a
+// SQL comment and in application_name.
+var correlationIDPattern = regexp.MustCompile(`^[A-Za-z0-9_.:-]+$`)
+
+// maxCorrelationIDLength is the longest correlation ID the provider accepts.
+const maxCorrelationIDLength = 64
+
+// correlationApplicationNamePrefix prefixes the correlation ID in
+// application_name when no other application name is configured.
+const correlationApplicationNamePrefix = "tf-supabase-vault/"
+
+// validateCorrelationID returns an error if id can't be used as a
+// correlation ID.
+func validateCorrelationID(id string) error {
+	if id == "" {
+		return fmt.Errorf("correlation ID must not be empty")
+	}
+	if len(id) > maxCorrelationIDLength {
+		return fmt.Errorf("correlation ID %q is longer than %d bytes", id, maxCorrelationIDLength)
+	}
+	if !correlationIDPattern.MatchString(id) {
+		return fmt.Errorf("correlation ID %q contains invalid characters: only letters, digits, '_', '.', ':' and '-' are allowed", id)
+	}
+
+	return nil
+}
+
+// newCorrelationID generates a random correlation ID.
+func newCorrelationID() (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", fmt.Errorf("generating correlation ID: %w", err)
+	}
+
+	return hex.EncodeToString(id), nil
+}
+
+// logContext returns ctx with the correlation ID attached to every log
+// entry written through it.
+func (d *ProviderData) logContext(ctx context.Context) context.Context {
+	if d == nil || d.CorrelationID == "" {
+		return ctx
+	}
+
+	return tflog.SetField(ctx, "correlation_id", d.CorrelationID)
+}
+
+// queryTagKey is the context key of the operation tag set by tagQueries.
+type queryTagKey struct{}
+
+// tagQueries returns ctx tagging every statement run through it with the
+// resource operation it belongs to, when query_comments is enabled. Statement
+// text ends up in pg_stat_statements and logs readable by anyone with access
+// to them, so nothing identifying the secret is included: an unsalted hash
+// of a guessable name would reveal it.
+func (d *ProviderData) tagQueries(ctx context.Context, operation string) context.Context {
+	if d == nil || !d.QueryComments {
+		return ctx
+	}
+
+	return context.WithValue(ctx, queryTagKey{}, "terraform "+operation)
+}
+
+// annotate prefixes sql with a comment carrying the correlation ID, so the
+// statement can be matched to this run in the database's logs, and with the
+// operation tag set on ctx by tagQueries, if any.
+func (d *ProviderData) annotate(ctx context.Context, sql string) string {
+	if tag, ok := ctx.Value(queryTagKey{}).(string); ok {
+		sql = fmt.Sprintf("/* %s */ %s", tag, sql)
+	}
+
+	if d.CorrelationID == "" {
+		return sql
+	}
+
+	return fmt.Sprintf("/* correlation_id=%s */ %s", d.CorrelationID, sql)
+}
+
+// annotatedTx annotates every statement run in a transaction.
+type annotatedTx struct {
+	pgx.Tx
+	data *ProviderData
+}
+
+func (tx annotatedTx) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	return tx.Tx.Exec(ctx, tx.data.annotate(ctx, sql), args...)
+}
+
+func (tx annotatedTx) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	return tx.Tx.Query(ctx, tx.data.annotate(ctx, sql), args...)
+}
+
+func (tx annotatedTx) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return tx.Tx.QueryRow(ctx, tx.data.annotate(ctx, sql), args...)
+}
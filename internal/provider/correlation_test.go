@@ -0,0 +1,85 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestValidateCorrelationID(t *testing.T) {
+	testCases := map[string]struct {
+		id        string
+		expectErr bool
+	}{
+		"generated":       {id: "3f2a9c0e4b6d8f1a2c3e5f7a9b0d1e2f"},
+		"ci run":          {id: "github:run-1234.5"},
+		"empty":           {id: "", expectErr: true},
+		"too long":        {id: strings.Repeat("a", maxCorrelationIDLength+1), expectErr: true},
+		"comment closer":  {id: "run*/", expectErr: true},
+		"whitespace":      {id: "run 1234", expectErr: true},
+		"quote":           {id: "run'1234", expectErr: true},
+		"newline":         {id: "run\n1234", expectErr: true},
+		"comment opener":  {id: "/*run", expectErr: true},
+		"unicode letters": {id: "läuft", expectErr: true},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			err := validateCorrelationID(testCase.id)
+
+			if testCase.expectErr && err == nil {
+				t.Fatalf("expected error for %q, got none", testCase.id)
+			}
+			if !testCase.expectErr && err != nil {
+				t.Fatalf("unexpected error for %q: %s", testCase.id, err)
+			}
+		})
+	}
+}
+
+func TestNewCorrelationID(t *testing.T) {
+	id, err := newCorrelationID()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if err := validateCorrelationID(id); err != nil {
+		t.Errorf("generated correlation ID is invalid: %s", err)
+	}
+	if len(correlationApplicationNamePrefix+id) > maxIdentifierLength {
+		t.Errorf("generated correlation ID %q would be truncated in application_name", id)
+	}
+}
+
+func TestAnnotate(t *testing.T) {
+	ctx := context.Background()
+	query := "SELECT 1"
+
+	if annotated := (&ProviderData{}).annotate(ctx, query); annotated != query {
+		t.Errorf("expected query without correlation ID to be unchanged, got %q", annotated)
+	}
+
+	annotated := (&ProviderData{CorrelationID: "run-1234"}).annotate(ctx, query)
+	if expected := "/* correlation_id=run-1234 */ SELECT 1"; annotated != expected {
+		t.Errorf("expected %q, got %q", expected, annotated)
+	}
+}
+
+func TestTagQueries(t *testing.T) {
+	query := "SELECT 1"
+
+	// Tags are only added with query_comments enabled
+	d := &ProviderData{CorrelationID: "run-1234"}
+	if annotated := d.annotate(d.tagQueries(context.Background(), "supabase-vault_secret create"), query); annotated != "/* correlation_id=run-1234 */ SELECT 1" {
+		t.Errorf("expected no operation tag, got %q", annotated)
+	}
+
+	d.QueryComments = true
+	annotated := d.annotate(d.tagQueries(context.Background(), "supabase-vault_secret create"), query)
+	if expected := "/* correlation_id=run-1234 */ /* terraform supabase-vault_secret create */ SELECT 1"; annotated != expected {
+		t.Errorf("expected %q, got %q", expected, annotated)
+	}
+}
@@ -0,0 +1,335 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// acquireError is returned when a connection can't be obtained from the pool.
+type acquireError struct {
+	timeout time.Duration
+	err     error
+}
+
+func (e *acquireError) Error() string {
+	if e.timeout > 0 {
+		return fmt.Sprintf("couldn't acquire a database connection from the pool within %s: %s", e.timeout, e.err)
+	}
+
+	return fmt.Sprintf("couldn't acquire a database connection from the pool: %s", e.err)
+}
+
+func (e *acquireError) Unwrap() error {
+	return e.err
+}
+
+// acquire obtains a connection from the pool. When an acquire timeout is
+// configured it bounds only the wait for a connection, not the statement run
+// on it afterwards.
+func (d *ProviderData) acquire(ctx context.Context) (*pgxpool.Conn, error) {
+	acquireCtx := ctx
+	if d.AcquireTimeout > 0 {
+		var cancel context.CancelFunc
+		acquireCtx, cancel = context.WithTimeout(ctx, d.AcquireTimeout)
+		defer cancel()
+	}
+
+	pool := d.pool()
+	if pool == nil {
+		return nil, errNoDirectConnection
+	}
+
+	conn, err := pool.Acquire(acquireCtx)
+	if err != nil {
+		return nil, &acquireError{timeout: d.AcquireTimeout, err: err}
+	}
+
+	return conn, nil
+}
+
+// queryRow acquires a connection and runs a query expected to return at most
+// one row. The query runs, and the connection is released, when the row is
+// scanned.
+func (d *ProviderData) queryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	// A lost connection takes the operation's transaction with it, so
+	// there is nothing to reconnect for
+	if tx, ok := txFromContext(ctx); ok {
+		return tx.QueryRow(ctx, sql, d.queryArgs(d.simpleProtocol.Load(), args)...)
+	}
+
+	if !d.AutoReconnect {
+		return d.queryRowOnce(ctx, sql, args...)
+	}
+
+	return reconnectingRow{data: d, ctx: ctx, sql: sql, args: args}
+}
+
+func (d *ProviderData) queryRowOnce(ctx context.Context, sql string, args ...any) pgx.Row {
+	conn, err := d.acquire(ctx)
+	if err != nil {
+		return errRow{err: err}
+	}
+
+	return &connRow{data: d, ctx: ctx, conn: conn, sql: d.annotate(ctx, sql), args: args}
+}
+
+// exec acquires a connection and executes a statement that returns no rows.
+func (d *ProviderData) exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	if tx, ok := txFromContext(ctx); ok {
+		return tx.Exec(ctx, sql, d.queryArgs(d.simpleProtocol.Load(), args)...)
+	}
+
+	var tag pgconn.CommandTag
+	err := d.reconnecting(ctx, func() error {
+		var err error
+		tag, err = d.execOnce(ctx, sql, args...)
+		return err
+	})
+
+	return tag, err
+}
+
+func (d *ProviderData) execOnce(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	conn, err := d.acquire(ctx)
+	if err != nil {
+		return pgconn.CommandTag{}, err
+	}
+	defer conn.Release()
+
+	sql = d.annotate(ctx, sql)
+	simple := d.simpleProtocol.Load()
+	tag, err := conn.Exec(ctx, sql, d.queryArgs(simple, args)...)
+
+	if d.fallBackToSimpleProtocol(ctx, simple, err) {
+		tag, err = conn.Exec(ctx, sql, d.queryArgs(true, args)...)
+	}
+
+	return tag, err
+}
+
+// withTx runs fn in a transaction on a pooled connection, committing if fn
+// succeeds and rolling back otherwise.
+func (d *ProviderData) withTx(ctx context.Context, fn func(tx pgx.Tx) error) error {
+	if tx, ok := txFromContext(ctx); ok {
+		return d.withSavepoint(ctx, tx, false, fn)
+	}
+
+	return d.reconnecting(ctx, func() error {
+		return d.withTxOnce(ctx, fn)
+	})
+}
+
+func (d *ProviderData) withTxOnce(ctx context.Context, fn func(tx pgx.Tx) error) error {
+	conn, err := d.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+
+	if err := fn(annotatedTx{Tx: tx, data: d}); err != nil {
+		// The original error is more useful than any rollback failure
+		_ = tx.Rollback(ctx)
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+
+	return nil
+}
+
+// collectRows acquires a connection, runs a query and collects every row it
+// returns with fn.
+func collectRows[T any](ctx context.Context, d *ProviderData, fn pgx.RowToFunc[T], sql string, args ...any) ([]T, error) {
+	if tx, ok := txFromContext(ctx); ok {
+		rows, err := tx.Query(ctx, sql, d.queryArgs(d.simpleProtocol.Load(), args)...)
+		if err != nil {
+			return nil, err
+		}
+
+		return pgx.CollectRows(rows, fn)
+	}
+
+	var result []T
+	err := d.reconnecting(ctx, func() error {
+		var err error
+		result, err = collectRowsOnce(ctx, d, fn, sql, args...)
+		return err
+	})
+
+	return result, err
+}
+
+func collectRowsOnce[T any](ctx context.Context, d *ProviderData, fn pgx.RowToFunc[T], sql string, args ...any) ([]T, error) {
+	conn, err := d.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Release()
+
+	sql = d.annotate(ctx, sql)
+	collect := func(simple bool) ([]T, error) {
+		rows, err := conn.Query(ctx, sql, d.queryArgs(simple, args)...)
+		if err != nil {
+			return nil, err
+		}
+
+		return pgx.CollectRows(rows, fn)
+	}
+
+	simple := d.simpleProtocol.Load()
+	result, err := collect(simple)
+
+	if d.fallBackToSimpleProtocol(ctx, simple, err) {
+		result, err = collect(true)
+	}
+
+	return result, err
+}
+
+// retryNoRows calls fn up to attempts times while it fails with
+// pgx.ErrNoRows, waiting backoff before the first retry and doubling it after
+// each. A row written moments ago may not be visible yet on a lagging read
+// replica. Any other error, or cancellation of ctx, ends the retries.
+func retryNoRows(ctx context.Context, attempts int, backoff time.Duration, fn func() error) error {
+	err := fn()
+
+	for attempt := 1; attempt < attempts && err == pgx.ErrNoRows; attempt++ {
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		err = fn()
+	}
+
+	return err
+}
+
+// queryArgs prefixes args with the simple protocol exec mode when needed.
+func (d *ProviderData) queryArgs(simple bool, args []any) []any {
+	if !simple {
+		return args
+	}
+
+	return append([]any{pgx.QueryExecModeSimpleProtocol}, args...)
+}
+
+// fallBackToSimpleProtocol reports whether a statement that failed with err
+// should be retried using the simple protocol. Transaction-mode poolers such
+// as Supavisor on port 6543 don't support prepared statements across
+// transactions; the first such failure switches every later query over.
+func (d *ProviderData) fallBackToSimpleProtocol(ctx context.Context, simple bool, err error) bool {
+	if simple || !isPreparedStatementError(err) {
+		return false
+	}
+
+	if d.simpleProtocol.CompareAndSwap(false, true) {
+		tflog.Warn(ctx, "Prepared statements are not supported by the connection pooler, switching to the simple query protocol", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+
+	return true
+}
+
+// withRollback runs fn in a transaction that is always rolled back, to find
+// out whether statements would succeed without keeping their effects.
+func (d *ProviderData) withRollback(ctx context.Context, fn func(tx pgx.Tx) error) error {
+	if tx, ok := txFromContext(ctx); ok {
+		return d.withSavepoint(ctx, tx, true, fn)
+	}
+
+	return d.reconnecting(ctx, func() error {
+		return d.withRollbackOnce(ctx, fn)
+	})
+}
+
+func (d *ProviderData) withRollbackOnce(ctx context.Context, fn func(tx pgx.Tx) error) error {
+	conn, err := d.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	return fn(annotatedTx{Tx: tx, data: d})
+}
+
+// isPreparedStatementError reports whether err is one a transaction-mode
+// pooler produces when prepared statements leak between server connections.
+func isPreparedStatementError(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+
+	// 42P05: duplicate_prepared_statement, 26000: invalid_sql_statement_name
+	return pgErr.Code == "42P05" || pgErr.Code == "26000"
+}
+
+// isInsufficientPrivilegeError reports whether err is PostgreSQL refusing an
+// operation the connecting role lacks the privileges for.
+func isInsufficientPrivilegeError(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+
+	// 42501: insufficient_privilege
+	return pgErr.Code == "42501"
+}
+
+// connRow releases its pooled connection after the row has been scanned.
+type connRow struct {
+	data *ProviderData
+	ctx  context.Context
+	conn *pgxpool.Conn
+	sql  string
+	args []any
+}
+
+func (r *connRow) Scan(dest ...any) error {
+	defer r.conn.Release()
+
+	simple := r.data.simpleProtocol.Load()
+	err := r.conn.QueryRow(r.ctx, r.sql, r.data.queryArgs(simple, r.args)...).Scan(dest...)
+
+	if r.data.fallBackToSimpleProtocol(r.ctx, simple, err) {
+		err = r.conn.QueryRow(r.ctx, r.sql, r.data.queryArgs(true, r.args)...).Scan(dest...)
+	}
+
+	return err
+}
+
+// errRow is a row that always fails to scan with err.
+type errRow struct {
+	err error
+}
+
+func (r errRow) Scan(dest ...any) error {
+	return r.err
+}
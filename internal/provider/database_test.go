@@ -0,0 +1,104 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestFallBackToSimpleProtocol(t *testing.T) {
+	ctx := context.Background()
+	duplicate := fmt.Errorf("wrapped: %w", &pgconn.PgError{Code: "42P05", Message: `prepared statement "stmtcache_1" already exists`})
+
+	data := &ProviderData{}
+
+	if data.fallBackToSimpleProtocol(ctx, false, errors.New("connection refused")) {
+		t.Fatal("expected unrelated errors not to trigger a retry")
+	}
+	if data.fallBackToSimpleProtocol(ctx, false, &pgconn.PgError{Code: "42501"}) {
+		t.Fatal("expected permission errors not to trigger a retry")
+	}
+	if !data.fallBackToSimpleProtocol(ctx, false, duplicate) {
+		t.Fatal("expected a duplicate prepared statement error to trigger a retry")
+	}
+	if !data.simpleProtocol.Load() {
+		t.Fatal("expected the simple protocol decision to be cached")
+	}
+	if data.fallBackToSimpleProtocol(ctx, true, duplicate) {
+		t.Fatal("expected no retry when the failing attempt already used the simple protocol")
+	}
+
+	args := data.queryArgs(true, []any{"a"})
+	if len(args) != 2 || args[0] != pgx.QueryExecModeSimpleProtocol {
+		t.Errorf("expected simple protocol exec mode to be prepended, got: %v", args)
+	}
+}
+
+func TestRetryNoRows(t *testing.T) {
+	boom := errors.New("boom")
+
+	testCases := map[string]struct {
+		errs          []error
+		expected      error
+		expectedCalls int
+	}{
+		"success": {
+			errs:          []error{nil},
+			expectedCalls: 1,
+		},
+		"row appears": {
+			errs:          []error{pgx.ErrNoRows, pgx.ErrNoRows, nil},
+			expectedCalls: 3,
+		},
+		"row never appears": {
+			errs:          []error{pgx.ErrNoRows, pgx.ErrNoRows, pgx.ErrNoRows, pgx.ErrNoRows},
+			expected:      pgx.ErrNoRows,
+			expectedCalls: 3,
+		},
+		"other error": {
+			errs:          []error{boom, nil},
+			expected:      boom,
+			expectedCalls: 1,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			calls := 0
+			err := retryNoRows(context.Background(), 3, time.Millisecond, func() error {
+				calls++
+				return testCase.errs[calls-1]
+			})
+
+			if err != testCase.expected {
+				t.Errorf("expected error %v, got: %v", testCase.expected, err)
+			}
+			if calls != testCase.expectedCalls {
+				t.Errorf("expected %d calls, got: %d", testCase.expectedCalls, calls)
+			}
+		})
+	}
+}
+
+func TestRetryNoRowsCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	calls := 0
+	err := retryNoRows(ctx, 3, time.Hour, func() error {
+		calls++
+		return pgx.ErrNoRows
+	})
+
+	if err != pgx.ErrNoRows || calls != 1 {
+		t.Errorf("expected a single attempt once canceled, got %d calls and error: %v", calls, err)
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &DecodeSecretFunction{}
+
+func NewDecodeSecretFunction() function.Function {
+	return &DecodeSecretFunction{}
+}
+
+// DecodeSecretFunction decodes a string without touching the database.
+type DecodeSecretFunction struct{}
+
+func (f *DecodeSecretFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "decode_secret"
+}
+
+func (f *DecodeSecretFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Decode a value",
+		MarkdownDescription: "Decodes a `base64`, `base64url` or `hex` encoded value. Base64 input may omit its padding, and the decoded value must be valid UTF-8. No database connection is used.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "value",
+				MarkdownDescription: "Value to decode",
+			},
+			function.StringParameter{
+				Name:                "encoding",
+				MarkdownDescription: "Encoding of the value, one of: " + strings.Join(valueEncodings, ", "),
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *DecodeSecretFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var value, encoding string
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &value, &encoding))
+	if resp.Error != nil {
+		return
+	}
+
+	decoded, err := decodeValue(value, encoding)
+	if err != nil {
+		resp.Error = function.NewFuncError(err.Error())
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, decoded))
+}
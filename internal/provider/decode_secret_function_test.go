@@ -0,0 +1,50 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"os"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestAccDecodeSecretFunction(t *testing.T) {
+	// Skip if TF_ACC is not set
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests skipped unless env 'TF_ACC' set")
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			// Provider functions need Terraform 1.8
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: `
+output "decoded" {
+  value = provider::supabase-vault::decode_secret("czNjcjN0", "base64")
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("decoded", knownvalue.StringExact("s3cr3t")),
+				},
+			},
+			{
+				Config: `
+output "decoded" {
+  value = provider::supabase-vault::decode_secret("czNjcjN0", "base32")
+}
+`,
+				ExpectError: regexp.MustCompile(`unsupported encoding "base32"`),
+			},
+		},
+	})
+}
@@ -0,0 +1,53 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// descriptionChecksum returns the SHA-256 hex digest of the user-facing
+// (footer-stripped) description, null when there is no description.
+func descriptionChecksum(description types.String) types.String {
+	if description.IsNull() || description.IsUnknown() {
+		return types.StringNull()
+	}
+
+	sum := sha256.Sum256([]byte(description.ValueString()))
+
+	return types.StringValue(hex.EncodeToString(sum[:]))
+}
+
+// descriptionChecksumPlanModifier plans description_checksum from the planned
+// description, so it only shows a change when the description does.
+type descriptionChecksumPlanModifier struct{}
+
+func (m descriptionChecksumPlanModifier) Description(ctx context.Context) string {
+	return "Plans the checksum of the planned description."
+}
+
+func (m descriptionChecksumPlanModifier) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (m descriptionChecksumPlanModifier) PlanModifyString(ctx context.Context, req planmodifier.StringRequest, resp *planmodifier.StringResponse) {
+	// Nothing to plan when the resource is being destroyed
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan VaultSecretModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() || plan.Description.IsUnknown() {
+		return
+	}
+
+	resp.PlanValue = descriptionChecksum(plan.Description)
+}
@@ -0,0 +1,30 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestDescriptionChecksum(t *testing.T) {
+	if checksum := descriptionChecksum(types.StringNull()); !checksum.IsNull() {
+		t.Errorf("expected null checksum for null description, got %s", checksum)
+	}
+
+	// echo -n "Test secret description" | sha256sum
+	expected := "8e73807adf849aad1a89d431bb00682c9dca9cc0316e1031641a66126b33416c"
+	if checksum := descriptionChecksum(types.StringValue("Test secret description")); checksum.ValueString() != expected {
+		t.Errorf("expected %s, got %s", expected, checksum.ValueString())
+	}
+
+	multiline := types.StringValue("line one\nline two\n")
+	if !descriptionChecksum(multiline).Equal(descriptionChecksum(types.StringValue("line one\nline two\n"))) {
+		t.Error("expected identical descriptions to share a checksum")
+	}
+	if descriptionChecksum(multiline).Equal(descriptionChecksum(types.StringValue("line one\nline two"))) {
+		t.Error("expected trailing whitespace changes to alter the checksum")
+	}
+}
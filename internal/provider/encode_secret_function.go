@@ -0,0 +1,60 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &EncodeSecretFunction{}
+
+func NewEncodeSecretFunction() function.Function {
+	return &EncodeSecretFunction{}
+}
+
+// EncodeSecretFunction encodes a string without touching the database.
+type EncodeSecretFunction struct{}
+
+func (f *EncodeSecretFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "encode_secret"
+}
+
+func (f *EncodeSecretFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Encode a value",
+		MarkdownDescription: "Encodes a value with `base64`, `base64url` or `hex`, for example before storing it in a secret. No database connection is used.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "value",
+				MarkdownDescription: "Value to encode",
+			},
+			function.StringParameter{
+				Name:                "encoding",
+				MarkdownDescription: "Encoding to use, one of: " + strings.Join(valueEncodings, ", "),
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *EncodeSecretFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var value, encoding string
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &value, &encoding))
+	if resp.Error != nil {
+		return
+	}
+
+	encoded, err := encodeValue(value, encoding)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(1, err.Error())
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, encoded))
+}
@@ -0,0 +1,79 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// environmentLabel is the label a secret's environment is stored under.
+const environmentLabel = "environment"
+
+// Values of environment_naming.
+const (
+	// environmentNamingPrefix stores secrets as <environment>_<name>, so the
+	// same name can be used in every environment.
+	environmentNamingPrefix = "prefix"
+
+	// environmentNamingLabel only labels secrets with their environment.
+	environmentNamingLabel = "label"
+)
+
+var environmentNamings = []string{environmentNamingPrefix, environmentNamingLabel}
+
+// environmentPattern matches an environment. Underscores are left out so the
+// prefix always ends at the first one.
+var environmentPattern = regexp.MustCompile(`^[A-Za-z0-9-]{1,32}$`)
+
+// validateEnvironment returns an error if environment can't be stored.
+func validateEnvironment(environment string) error {
+	if !environmentPattern.MatchString(environment) {
+		return fmt.Errorf("environment %q must be 1 to 32 letters, digits or '-'", environment)
+	}
+
+	return nil
+}
+
+// validateEnvironmentNaming returns an error if naming isn't an
+// environment_naming value.
+func validateEnvironmentNaming(naming string) error {
+	for _, valid := range environmentNamings {
+		if naming == valid {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unsupported environment_naming %q, expected one of: %s", naming, strings.Join(environmentNamings, ", "))
+}
+
+// prefixesEnvironment reports whether secrets with an environment are stored
+// under a prefixed name.
+func (d *ProviderData) prefixesEnvironment() bool {
+	return d.EnvironmentNaming == "" || d.EnvironmentNaming == environmentNamingPrefix
+}
+
+// environmentName returns the name to store a secret named name in
+// environment under.
+func (d *ProviderData) environmentName(name string, environment types.String) string {
+	if environment.IsNull() || environment.IsUnknown() || !d.prefixesEnvironment() {
+		return name
+	}
+
+	return environment.ValueString() + secretNameSeparator + name
+}
+
+// configuredName reverses environmentName for a stored name. A name that
+// lacks the prefix, e.g. because it was renamed outside Terraform, is
+// returned as is.
+func (d *ProviderData) configuredName(stored string, environment types.String) string {
+	if environment.IsNull() || environment.IsUnknown() || !d.prefixesEnvironment() {
+		return stored
+	}
+
+	return strings.TrimPrefix(stored, environment.ValueString()+secretNameSeparator)
+}
@@ -0,0 +1,56 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestValidateEnvironment(t *testing.T) {
+	for _, environment := range []string{"prod", "staging", "eu-west-1", "QA"} {
+		if err := validateEnvironment(environment); err != nil {
+			t.Errorf("unexpected error for %q: %s", environment, err)
+		}
+	}
+
+	for _, environment := range []string{"", "prod_eu", "prod env", "a-very-long-environment-name-over-32"} {
+		if err := validateEnvironment(environment); err == nil {
+			t.Errorf("expected an error for %q", environment)
+		}
+	}
+}
+
+func TestEnvironmentName(t *testing.T) {
+	testCases := map[string]struct {
+		naming      string
+		environment types.String
+		stored      string
+	}{
+		"no environment": {environment: types.StringNull(), stored: "api_key"},
+		"default naming": {environment: types.StringValue("prod"), stored: "prod_api_key"},
+		"prefix naming":  {naming: environmentNamingPrefix, environment: types.StringValue("prod"), stored: "prod_api_key"},
+		"label naming":   {naming: environmentNamingLabel, environment: types.StringValue("prod"), stored: "api_key"},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			d := &ProviderData{EnvironmentNaming: testCase.naming}
+
+			if stored := d.environmentName("api_key", testCase.environment); stored != testCase.stored {
+				t.Errorf("expected %q, got %q", testCase.stored, stored)
+			}
+			if configured := d.configuredName(testCase.stored, testCase.environment); configured != "api_key" {
+				t.Errorf("expected %q to read back as api_key, got %q", testCase.stored, configured)
+			}
+		})
+	}
+
+	// A secret renamed outside Terraform keeps its name
+	d := &ProviderData{}
+	if configured := d.configuredName("api_key", types.StringValue("prod")); configured != "api_key" {
+		t.Errorf("expected a name without the prefix unchanged, got %q", configured)
+	}
+}
@@ -0,0 +1,39 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// expiryLabel is the label expires_at is stored under.
+const expiryLabel = "expires_at"
+
+// parseExpiry parses an expires_at value, an RFC 3339 timestamp.
+func parseExpiry(expiresAt string) (time.Time, error) {
+	expiry, err := time.Parse(time.RFC3339, expiresAt)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is not an RFC 3339 timestamp, e.g. 2030-01-31T00:00:00Z", expiresAt)
+	}
+
+	return expiry, nil
+}
+
+// expiryStatus returns is_expired for expiresAt at now: null without an
+// expiry, or when the stored one was edited into something unparseable.
+func expiryStatus(expiresAt types.String, now time.Time) types.Bool {
+	if expiresAt.IsNull() || expiresAt.IsUnknown() {
+		return types.BoolNull()
+	}
+
+	expiry, err := parseExpiry(expiresAt.ValueString())
+	if err != nil {
+		return types.BoolNull()
+	}
+
+	return types.BoolValue(!now.Before(expiry))
+}
@@ -0,0 +1,39 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestExpiryStatus(t *testing.T) {
+	now := time.Date(2030, 1, 31, 12, 0, 0, 0, time.UTC)
+
+	testCases := map[string]struct {
+		expiresAt types.String
+		expected  types.Bool
+	}{
+		"no expiry":       {expiresAt: types.StringNull(), expected: types.BoolNull()},
+		"future":          {expiresAt: types.StringValue("2030-02-01T00:00:00Z"), expected: types.BoolValue(false)},
+		"past":            {expiresAt: types.StringValue("2030-01-31T00:00:00Z"), expected: types.BoolValue(true)},
+		"exactly now":     {expiresAt: types.StringValue("2030-01-31T12:00:00Z"), expected: types.BoolValue(true)},
+		"time zone":       {expiresAt: types.StringValue("2030-01-31T13:30:00+02:00"), expected: types.BoolValue(true)},
+		"edited label":    {expiresAt: types.StringValue("next week"), expected: types.BoolNull()},
+		"unknown expiry":  {expiresAt: types.StringUnknown(), expected: types.BoolNull()},
+		"fractional time": {expiresAt: types.StringValue("2030-01-31T12:00:00.5Z"), expected: types.BoolValue(false)},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			status := expiryStatus(testCase.expiresAt, now)
+
+			if !status.Equal(testCase.expected) {
+				t.Errorf("expected %s, got %s", testCase.expected, status)
+			}
+		})
+	}
+}
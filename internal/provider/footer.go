@@ -0,0 +1,183 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// managedByFooterMarker follows the footer separator in the footer appended
+// to every managed secret's description. It is followed by the provider
+// version that wrote it.
+const managedByFooterMarker = "Managed by terraform-provider-supabase-vault v"
+
+// defaultFooterSeparator separates the managed-by footer from the rest of
+// the description unless footer_separator is configured.
+const defaultFooterSeparator = "\n\n---\n"
+
+// validateFooterSeparator returns an error if separator can't delimit the
+// managed-by footer.
+func validateFooterSeparator(separator string) error {
+	if strings.Trim(separator, "\n") == "" {
+		return fmt.Errorf("footer_separator must contain more than newlines")
+	}
+	if strings.ContainsRune(separator, 0) {
+		return fmt.Errorf("footer_separator must not contain NUL bytes")
+	}
+	if strings.Contains(separator, managedByFooterMarker) || strings.Contains(separator, labelsMarker) {
+		return fmt.Errorf("footer_separator must not contain the managed-by footer or labels marker")
+	}
+
+	return nil
+}
+
+// appendManagedByFooter appends a footer to the description indicating the
+// secret is managed by Terraform, delimited by separator.
+func appendManagedByFooter(description string, separator string, version string) string {
+	footer := separator + managedByFooterMarker + version
+
+	// A lone footer isn't separated from anything
+	if description == "" {
+		return strings.TrimLeft(footer, "\n")
+	}
+
+	return description + footer
+}
+
+// validateDescription returns an error if description ends with the
+// managed-by footer, which the provider adds itself, or contains the labels
+// marker, since Read would take everything from the marker on for labels.
+// The footer text is allowed anywhere else: only a footer ending the stored
+// description is stripped, so a description mentioning it, such as one of an
+// imported secret, reads back unchanged.
+func validateDescription(description string) error {
+	if stripManagedByFooter(description, defaultFooterSeparator) != description {
+		return fmt.Errorf("description must not end with the managed-by footer %q, which the provider adds itself", managedByFooterMarker)
+	}
+	if strings.Contains(description, labelsMarker) {
+		return fmt.Errorf("description must not contain %q, which the provider uses to store labels", labelsMarker)
+	}
+
+	return nil
+}
+
+// stripManagedByFooter removes the footer added by appendManagedByFooter
+// with separator, whichever provider version wrote it. This is the whole
+// trimming contract:
+//
+//   - Only a footer ending the description is removed: the separator, the
+//     marker and a version without whitespace, with nothing after it.
+//   - Only that last footer is removed, once, so descriptions that mention
+//     the footer text elsewhere, including secrets imported from other
+//     tools, read back exactly as stored.
+//   - A description that is nothing but a footer, which secrets created
+//     without a description hold, reads back as empty.
+//   - Footers written with the default separator are removed too, so
+//     changing footer_separator doesn't turn existing footers into drift.
+//
+// Descriptions without a footer are returned unchanged.
+func stripManagedByFooter(description string, separator string) string {
+	text, _, _ := splitManagedByFooter(description, separator)
+	return text
+}
+
+// splitManagedByFooter splits description into the text before the
+// managed-by footer and the provider version the footer names, following
+// the contract of stripManagedByFooter. ok is false when description has no
+// footer, in which case it is returned whole.
+func splitManagedByFooter(description string, separator string) (text string, version string, ok bool) {
+	separators := []string{separator}
+	if separator != defaultFooterSeparator {
+		separators = append(separators, defaultFooterSeparator)
+	}
+
+	for _, separator := range separators {
+		// A secret created without a description holds only the footer
+		if version, ok := strings.CutPrefix(description, strings.TrimLeft(separator, "\n")+managedByFooterMarker); ok && isFooterVersion(version) {
+			return "", version, true
+		}
+
+		index := strings.LastIndex(description, separator+managedByFooterMarker)
+		if index < 0 {
+			continue
+		}
+		if version := description[index+len(separator)+len(managedByFooterMarker):]; isFooterVersion(version) {
+			return description[:index], version, true
+		}
+	}
+
+	return description, "", false
+}
+
+// managedByVersion returns the provider version named in the managed-by
+// footer of a stored description, null when it has none.
+func (d *ProviderData) managedByVersion(stored string) types.String {
+	if _, version, ok := splitManagedByFooter(stored, d.footerSeparator()); ok {
+		return types.StringValue(version)
+	}
+
+	return types.StringNull()
+}
+
+// isFooterVersion reports whether version, the text following the
+// managed-by footer marker, can be the version the provider wrote there
+// rather than the marker text being part of a longer description.
+func isFooterVersion(version string) bool {
+	return version != "" && strings.IndexFunc(version, unicode.IsSpace) < 0
+}
+
+// footerSeparator returns the configured footer_separator, or the default
+// when none is configured.
+func (d *ProviderData) footerSeparator() string {
+	if d.FooterSeparator == "" {
+		return defaultFooterSeparator
+	}
+
+	return d.FooterSeparator
+}
+
+// storedDescription returns the description to store for a secret
+// configured with description and labels: the provider's default
+// description when it is null, followed by the labels block and the
+// managed-by footer. A secret with nothing to describe stores an empty
+// description rather than a lone footer, so clearing a description leaves
+// none behind. The empty string, unlike NULL, makes vault.update_secret()
+// replace the stored description.
+func (d *ProviderData) storedDescription(description types.String, labels map[string]string) string {
+	text := d.DefaultDescription
+	if !description.IsNull() {
+		text = description.ValueString()
+	}
+
+	text = appendLabels(text, labels)
+	if text == "" {
+		return ""
+	}
+
+	return appendManagedByFooter(text, d.footerSeparator(), d.Version)
+}
+
+// configuredDescription maps a stored description back to the description
+// and labels in the configuration by stripping the managed-by footer and
+// the labels block. The provider's default description maps back to null
+// when prior, the description in state, is null, so secrets relying on the
+// default don't show drift. With show_footer_on_read the stored description
+// is returned as is, though its labels are still parsed.
+func (d *ProviderData) configuredDescription(stored string, prior types.String) (types.String, map[string]string) {
+	description, labels := splitLabels(stripManagedByFooter(stored, d.footerSeparator()))
+
+	if d.ShowFooterOnRead {
+		return types.StringValue(stored), labels
+	}
+
+	if description == "" || (prior.IsNull() && description == d.DefaultDescription) {
+		return types.StringNull(), labels
+	}
+
+	return types.StringValue(description), labels
+}
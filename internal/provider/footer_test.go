@@ -0,0 +1,287 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestStripManagedByFooter(t *testing.T) {
+	testCases := map[string]struct {
+		stored   string
+		expected string
+	}{
+		"current version": {
+			stored:   appendManagedByFooter("API key", defaultFooterSeparator, "1.2.0"),
+			expected: "API key",
+		},
+		"older version": {
+			stored:   "API key\n\n---\nManaged by terraform-provider-supabase-vault v1.0.0",
+			expected: "API key",
+		},
+		"dev build": {
+			stored:   "API key\n\n---\nManaged by terraform-provider-supabase-vault vdev",
+			expected: "API key",
+		},
+		"footer only": {
+			stored:   appendManagedByFooter("", defaultFooterSeparator, "0.9.0"),
+			expected: "",
+		},
+		"multiline description": {
+			stored:   "Line one\n\nLine two\n\n---\nManaged by terraform-provider-supabase-vault v0.1.0",
+			expected: "Line one\n\nLine two",
+		},
+		"no footer": {
+			stored:   "Created outside Terraform",
+			expected: "Created outside Terraform",
+		},
+		"markdown rule without footer": {
+			stored:   "Intro\n\n---\nNot managed by anything",
+			expected: "Intro\n\n---\nNot managed by anything",
+		},
+		"footer text mid-string": {
+			stored:   "Rotated by hand\n\n---\nManaged by terraform-provider-supabase-vault v1.0.0 until 2024, now by ops",
+			expected: "Rotated by hand\n\n---\nManaged by terraform-provider-supabase-vault v1.0.0 until 2024, now by ops",
+		},
+		"text after footer": {
+			stored:   "API key\n\n---\nManaged by terraform-provider-supabase-vault v1.0.0\nEdited in the dashboard",
+			expected: "API key\n\n---\nManaged by terraform-provider-supabase-vault v1.0.0\nEdited in the dashboard",
+		},
+		"footer text mid-string and footer": {
+			stored:   appendManagedByFooter("Was: Managed by terraform-provider-supabase-vault v0.1.0 elsewhere", defaultFooterSeparator, "1.2.0"),
+			expected: "Was: Managed by terraform-provider-supabase-vault v0.1.0 elsewhere",
+		},
+		"footer text before footer": {
+			stored:   appendManagedByFooter("Imported\n\n---\nManaged by terraform-provider-supabase-vault v0.1.0", defaultFooterSeparator, "1.2.0"),
+			expected: "Imported\n\n---\nManaged by terraform-provider-supabase-vault v0.1.0",
+		},
+		"marker without version": {
+			stored:   "API key\n\n---\nManaged by terraform-provider-supabase-vault v",
+			expected: "API key\n\n---\nManaged by terraform-provider-supabase-vault v",
+		},
+		"footer only with trailing text": {
+			stored:   "---\nManaged by terraform-provider-supabase-vault v1.0.0 and more",
+			expected: "---\nManaged by terraform-provider-supabase-vault v1.0.0 and more",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if stripped := stripManagedByFooter(testCase.stored, defaultFooterSeparator); stripped != testCase.expected {
+				t.Errorf("expected %q, got %q", testCase.expected, stripped)
+			}
+		})
+	}
+}
+
+func TestStripManagedByFooterCustomSeparator(t *testing.T) {
+	separator := "\n\n<!-- terraform -->\n"
+
+	testCases := map[string]struct {
+		stored   string
+		expected string
+	}{
+		"custom separator": {
+			stored:   appendManagedByFooter("API key", separator, "1.2.0"),
+			expected: "API key",
+		},
+		"footer only": {
+			stored:   appendManagedByFooter("", separator, "1.2.0"),
+			expected: "",
+		},
+		"default separator": {
+			stored:   appendManagedByFooter("API key", defaultFooterSeparator, "1.0.0"),
+			expected: "API key",
+		},
+		"markdown rule without footer": {
+			stored:   "Intro\n\n<!-- terraform -->\nNot managed by anything",
+			expected: "Intro\n\n<!-- terraform -->\nNot managed by anything",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if stripped := stripManagedByFooter(testCase.stored, separator); stripped != testCase.expected {
+				t.Errorf("expected %q, got %q", testCase.expected, stripped)
+			}
+		})
+	}
+}
+
+func TestManagedByVersion(t *testing.T) {
+	d := &ProviderData{Version: "1.2.0", FooterSeparator: "\n\n<!-- terraform -->\n"}
+
+	testCases := map[string]struct {
+		stored   string
+		expected types.String
+	}{
+		"custom separator": {
+			stored:   appendManagedByFooter("API key", d.FooterSeparator, "1.2.0"),
+			expected: types.StringValue("1.2.0"),
+		},
+		"default separator": {
+			stored:   appendManagedByFooter("API key", defaultFooterSeparator, "0.9.0"),
+			expected: types.StringValue("0.9.0"),
+		},
+		"footer only": {
+			stored:   appendManagedByFooter("", defaultFooterSeparator, "dev"),
+			expected: types.StringValue("dev"),
+		},
+		"footer text mid-string": {
+			stored:   "Was: Managed by terraform-provider-supabase-vault v0.1.0 elsewhere",
+			expected: types.StringNull(),
+		},
+		"no footer": {
+			stored:   "Created outside Terraform",
+			expected: types.StringNull(),
+		},
+		"empty": {
+			stored:   "",
+			expected: types.StringNull(),
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if version := d.managedByVersion(testCase.stored); !version.Equal(testCase.expected) {
+				t.Errorf("expected %s, got %s", testCase.expected, version)
+			}
+		})
+	}
+}
+
+func TestValidateFooterSeparator(t *testing.T) {
+	testCases := map[string]struct {
+		separator string
+		expectErr bool
+	}{
+		"default":      {separator: defaultFooterSeparator},
+		"html comment": {separator: "\n\n<!-- terraform -->\n"},
+		"empty":        {separator: "", expectErr: true},
+		"newlines":     {separator: "\n\n", expectErr: true},
+		"nul":          {separator: "\n\x00\n", expectErr: true},
+		"labels":       {separator: "\n\n" + labelsMarker, expectErr: true},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			err := validateFooterSeparator(testCase.separator)
+
+			if testCase.expectErr && err == nil {
+				t.Errorf("expected error for %q, got none", testCase.separator)
+			}
+			if !testCase.expectErr && err != nil {
+				t.Errorf("unexpected error for %q: %s", testCase.separator, err)
+			}
+		})
+	}
+}
+
+func TestDefaultDescriptionRoundTrip(t *testing.T) {
+	d := &ProviderData{Version: "1.2.0", DefaultDescription: "Provisioned via platform IaC"}
+
+	testCases := map[string]struct {
+		description types.String
+		labels      map[string]string
+		stored      string
+	}{
+		"omitted": {
+			description: types.StringNull(),
+			stored:      appendManagedByFooter("Provisioned via platform IaC", defaultFooterSeparator, "1.2.0"),
+		},
+		"resource description": {
+			description: types.StringValue("API key"),
+			stored:      appendManagedByFooter("API key", defaultFooterSeparator, "1.2.0"),
+		},
+		"resource description equal to the default": {
+			description: types.StringValue("Provisioned via platform IaC"),
+			stored:      appendManagedByFooter("Provisioned via platform IaC", defaultFooterSeparator, "1.2.0"),
+		},
+		"omitted with labels": {
+			description: types.StringNull(),
+			labels:      map[string]string{"owner": "platform"},
+			stored:      appendManagedByFooter("Provisioned via platform IaC\n\n---\nLabels: {\"owner\":\"platform\"}", defaultFooterSeparator, "1.2.0"),
+		},
+		"resource description with labels": {
+			description: types.StringValue("API key"),
+			labels:      map[string]string{"rotation_policy": "90d", "owner": "payments"},
+			stored:      appendManagedByFooter("API key\n\n---\nLabels: {\"owner\":\"payments\",\"rotation_policy\":\"90d\"}", defaultFooterSeparator, "1.2.0"),
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			stored := d.storedDescription(testCase.description, testCase.labels)
+			if stored != testCase.stored {
+				t.Fatalf("expected %q to be stored, got %q", testCase.stored, stored)
+			}
+
+			read, labels := d.configuredDescription(stored, testCase.description)
+			if !read.Equal(testCase.description) {
+				t.Errorf("expected %s to be read back, got %s", testCase.description, read)
+			}
+			if !reflect.DeepEqual(labels, testCase.labels) {
+				t.Errorf("expected labels %v to be read back, got %v", testCase.labels, labels)
+			}
+		})
+	}
+}
+
+func TestDescriptionMentioningFooterRoundTrip(t *testing.T) {
+	d := &ProviderData{Version: "1.2.0"}
+
+	testCases := map[string]string{
+		"mid-string":        "Managed by terraform-provider-supabase-vault v0.1.0 before the import",
+		"text after footer": "Imported\n\n---\nManaged by terraform-provider-supabase-vault v0.1.0\nby another workspace",
+	}
+
+	for name, text := range testCases {
+		t.Run(name, func(t *testing.T) {
+			description := types.StringValue(text)
+			if err := validateDescription(text); err != nil {
+				t.Fatalf("unexpected error for %q: %s", text, err)
+			}
+
+			read, _ := d.configuredDescription(d.storedDescription(description, nil), description)
+			if !read.Equal(description) {
+				t.Errorf("expected %s to be read back, got %s", description, read)
+			}
+		})
+	}
+}
+
+func TestConfiguredDescriptionShowFooterOnRead(t *testing.T) {
+	d := &ProviderData{Version: "1.2.0", ShowFooterOnRead: true}
+
+	stored := appendManagedByFooter("API key", defaultFooterSeparator, "1.0.0")
+
+	if read, _ := d.configuredDescription(stored, types.StringValue("API key")); read.ValueString() != stored {
+		t.Errorf("expected the stored description %q, got %s", stored, read)
+	}
+}
+
+func TestStoredDescriptionWithoutDescription(t *testing.T) {
+	d := &ProviderData{Version: "1.2.0"}
+
+	testCases := map[string]types.String{
+		"null":  types.StringNull(),
+		"empty": types.StringValue(""),
+	}
+
+	for name, description := range testCases {
+		t.Run(name, func(t *testing.T) {
+			// Clearing a description must not leave a lone footer behind
+			if stored := d.storedDescription(description, nil); stored != "" {
+				t.Fatalf("expected an empty description to be stored, got %q", stored)
+			}
+
+			if read, _ := d.configuredDescription("", description); !read.IsNull() {
+				t.Errorf("expected a null description to be read back, got %s", read)
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import "github.com/jackc/pgx/v5"
+
+// qualifiedName returns object, qualified by schema unless it is empty, as a
+// quoted SQL identifier. Every identifier that isn't a constant in the query
+// text must go through it: quoting makes quotes, dots and anything else in a
+// name part of the identifier, so a name can't inject SQL.
+func qualifiedName(schema, object string) string {
+	if schema == "" {
+		return pgx.Identifier{object}.Sanitize()
+	}
+
+	return pgx.Identifier{schema, object}.Sanitize()
+}
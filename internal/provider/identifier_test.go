@@ -0,0 +1,51 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import "testing"
+
+func TestQualifiedName(t *testing.T) {
+	testCases := map[string]struct {
+		schema string
+		object string
+		want   string
+	}{
+		"unqualified": {
+			object: "postgres",
+			want:   `"postgres"`,
+		},
+		"qualified": {
+			schema: "vault",
+			object: "secrets",
+			want:   `"vault"."secrets"`,
+		},
+		"dot in name": {
+			schema: "vault.secrets",
+			object: "x",
+			want:   `"vault.secrets"."x"`,
+		},
+		"quote in name": {
+			schema: `va"ult`,
+			object: `sec"rets`,
+			want:   `"va""ult"."sec""rets"`,
+		},
+		"injection": {
+			schema: `vault"; DROP TABLE vault.secrets; --`,
+			object: "secrets",
+			want:   `"vault""; DROP TABLE vault.secrets; --"."secrets"`,
+		},
+		"null byte": {
+			object: "sec\x00rets",
+			want:   `"secrets"`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if got := qualifiedName(testCase.schema, testCase.object); got != testCase.want {
+				t.Errorf("expected %s, got %s", testCase.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// labelsMarker starts the block that stores a secret's labels as JSON in its
+// description, between the free-text description and the managed-by footer.
+const labelsMarker = "---\nLabels: "
+
+// appendLabels appends labels to description as a JSON block. Without
+// labels the description is returned unchanged.
+func appendLabels(description string, labels map[string]string) string {
+	if len(labels) == 0 {
+		return description
+	}
+
+	// Marshalling a map of strings can't fail, and its keys come out sorted
+	// so the block is stable
+	encoded, _ := json.Marshal(labels)
+
+	block := labelsMarker + string(encoded)
+	if description == "" {
+		return block
+	}
+
+	return description + "\n\n" + block
+}
+
+// splitLabels separates the labels block added by appendLabels from a
+// description whose managed-by footer was already stripped. A block that
+// isn't valid JSON is left in the description, so the edit shows as drift.
+func splitLabels(description string) (string, map[string]string) {
+	// A secret with labels but no description holds only the block
+	text, block := "", description
+	if !strings.HasPrefix(description, labelsMarker) {
+		index := strings.LastIndex(description, "\n\n"+labelsMarker)
+		if index < 0 {
+			return description, nil
+		}
+		text, block = description[:index], description[index+2:]
+	}
+
+	var labels map[string]string
+	if err := json.Unmarshal([]byte(strings.TrimPrefix(block, labelsMarker)), &labels); err != nil {
+		return description, nil
+	}
+
+	return text, labels
+}
+
+// withLabel returns a copy of labels with key set to value, or labels itself
+// when value is null, for attributes stored as a label.
+func withLabel(labels map[string]string, key string, value types.String) map[string]string {
+	if value.IsNull() || value.IsUnknown() {
+		return labels
+	}
+
+	result := make(map[string]string, len(labels)+1)
+	for existing, label := range labels {
+		result[existing] = label
+	}
+	result[key] = value.ValueString()
+
+	return result
+}
@@ -0,0 +1,72 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestSplitLabels(t *testing.T) {
+	testCases := map[string]struct {
+		description    string
+		expectedText   string
+		expectedLabels map[string]string
+	}{
+		"description and labels": {
+			description:    appendLabels("API key", map[string]string{"owner": "payments"}),
+			expectedText:   "API key",
+			expectedLabels: map[string]string{"owner": "payments"},
+		},
+		"labels only": {
+			description:    appendLabels("", map[string]string{"owner": "payments"}),
+			expectedText:   "",
+			expectedLabels: map[string]string{"owner": "payments"},
+		},
+		"no labels": {
+			description:  "API key",
+			expectedText: "API key",
+		},
+		"labels containing the marker": {
+			description:    appendLabels("API key", map[string]string{"note": "\n\n---\nLabels: {}"}),
+			expectedText:   "API key",
+			expectedLabels: map[string]string{"note": "\n\n---\nLabels: {}"},
+		},
+		"edited block that is no longer JSON": {
+			description:  "API key\n\n---\nLabels: {\"owner\":",
+			expectedText: "API key\n\n---\nLabels: {\"owner\":",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			text, labels := splitLabels(testCase.description)
+
+			if text != testCase.expectedText {
+				t.Errorf("expected description %q, got %q", testCase.expectedText, text)
+			}
+			if !reflect.DeepEqual(labels, testCase.expectedLabels) {
+				t.Errorf("expected labels %v, got %v", testCase.expectedLabels, labels)
+			}
+		})
+	}
+}
+
+func TestWithLabel(t *testing.T) {
+	labels := map[string]string{"team": "payments"}
+
+	if result := withLabel(labels, expiryLabel, types.StringNull()); len(result) != 1 {
+		t.Errorf("expected the labels unchanged without a value, got %v", result)
+	}
+
+	result := withLabel(labels, expiryLabel, types.StringValue("2030-01-31T00:00:00Z"))
+	if result[expiryLabel] != "2030-01-31T00:00:00Z" || result["team"] != "payments" {
+		t.Errorf("unexpected labels %v", result)
+	}
+	if _, ok := labels[expiryLabel]; ok {
+		t.Error("expected the original labels to be left unchanged")
+	}
+}
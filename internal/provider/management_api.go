@@ -0,0 +1,176 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultManagementAPIURL is the Supabase Management API used unless
+// management_api_url is set.
+const defaultManagementAPIURL = "https://api.supabase.com"
+
+// managementAPITimeout bounds a single Management API request.
+const managementAPITimeout = 30 * time.Second
+
+// projectRefPattern matches a Supabase project reference, the subdomain of
+// the project's URL.
+var projectRefPattern = regexp.MustCompile(`^[a-z0-9]{20}$`)
+
+// validateProjectRef returns an error if ref is not a Supabase project
+// reference.
+func validateProjectRef(ref string) error {
+	if !projectRefPattern.MatchString(ref) {
+		return fmt.Errorf("%q is not a Supabase project reference (expected the 20 lowercase letters and digits from the project's URL)", ref)
+	}
+
+	return nil
+}
+
+// managementAPIClient runs SQL against a project through the Supabase
+// Management API, for projects the provider can't open a Postgres
+// connection to.
+type managementAPIClient struct {
+	baseURL    string
+	token      string
+	projectRef string
+	httpClient *http.Client
+}
+
+func newManagementAPIClient(baseURL, token, projectRef string) *managementAPIClient {
+	return &managementAPIClient{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		token:      token,
+		projectRef: projectRef,
+		httpClient: &http.Client{Timeout: managementAPITimeout},
+	}
+}
+
+// managementAPIError is a request the Management API refused.
+type managementAPIError struct {
+	status  int
+	message string
+}
+
+func (e *managementAPIError) Error() string {
+	return fmt.Sprintf("Supabase Management API returned %d: %s", e.status, e.message)
+}
+
+// query runs sql with args interpolated as literals and returns the rows it
+// produced, each mapping column names to their JSON values. The endpoint
+// takes no bind parameters, so every argument goes through quoteLiteral.
+func (c *managementAPIClient) query(ctx context.Context, sql string, args ...any) ([]map[string]any, error) {
+	statement, err := interpolateArgs(sql, args)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := json.Marshal(map[string]string{"query": statement})
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := fmt.Sprintf("%s/v1/projects/%s/database/query", c.baseURL, url.PathEscape(c.projectRef))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("calling the Supabase Management API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading the Supabase Management API response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var failure struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(content, &failure) != nil || failure.Message == "" {
+			failure.Message = strings.TrimSpace(string(content))
+		}
+
+		return nil, &managementAPIError{status: resp.StatusCode, message: failure.Message}
+	}
+
+	var rows []map[string]any
+	if err := json.Unmarshal(content, &rows); err != nil {
+		return nil, fmt.Errorf("decoding the Supabase Management API response: %w", err)
+	}
+
+	return rows, nil
+}
+
+// placeholderPattern matches the $1, $2, ... placeholders of a statement.
+var placeholderPattern = regexp.MustCompile(`\$([0-9]+)`)
+
+// interpolateArgs replaces the $1, $2, ... placeholders in sql with args
+// quoted as literals. The statement is scanned once, so placeholders inside
+// an interpolated literal are left alone. The API has no bind parameters,
+// so secret values end up in the statement text; the management_token
+// description warns about this.
+func interpolateArgs(sql string, args []any) (string, error) {
+	var err error
+
+	statement := placeholderPattern.ReplaceAllStringFunc(sql, func(placeholder string) string {
+		index, _ := strconv.Atoi(placeholder[1:])
+		if index < 1 || index > len(args) {
+			err = fmt.Errorf("no argument for placeholder %s", placeholder)
+			return placeholder
+		}
+
+		literal, quoteErr := quoteLiteral(args[index-1])
+		if quoteErr != nil {
+			err = fmt.Errorf("argument %s: %w", placeholder, quoteErr)
+			return placeholder
+		}
+
+		return literal
+	})
+
+	return statement, err
+}
+
+// quoteLiteral returns value as a SQL literal.
+func quoteLiteral(value any) (string, error) {
+	switch value := value.(type) {
+	case nil:
+		return "NULL", nil
+	case string:
+		return quoteString(value)
+	}
+
+	return "", fmt.Errorf("unsupported argument type %T", value)
+}
+
+// quoteString returns value as a string literal for statements that take no
+// bind parameters. It uses the escape string syntax, which quotes the same
+// way whatever standard_conforming_strings is set to.
+func quoteString(value string) (string, error) {
+	if strings.ContainsRune(value, 0) {
+		return "", fmt.Errorf("strings can't contain NUL bytes")
+	}
+
+	escaped := strings.ReplaceAll(value, `\`, `\\`)
+	escaped = strings.ReplaceAll(escaped, `'`, `''`)
+
+	return "E'" + escaped + "'", nil
+}
@@ -0,0 +1,187 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const testProjectRef = "abcdefghijklmnopqrst"
+
+func TestInterpolateArgs(t *testing.T) {
+	testCases := map[string]struct {
+		sql       string
+		args      []any
+		expected  string
+		expectErr bool
+	}{
+		"string and null": {
+			sql:      "SELECT vault.create_secret($1, $2, $3, $4)",
+			args:     []any{"value", "name", "", nil},
+			expected: "SELECT vault.create_secret(E'value', E'name', E'', NULL)",
+		},
+		"quotes and backslashes": {
+			sql:      "SELECT $1",
+			args:     []any{`it's a \' trap`},
+			expected: `SELECT E'it''s a \\'' trap'`,
+		},
+		"placeholder in a value": {
+			sql:      "UPDATE vault.secrets SET name = $2 WHERE id = $1",
+			args:     []any{"id", "$1"},
+			expected: "UPDATE vault.secrets SET name = E'$1' WHERE id = E'id'",
+		},
+		"ten arguments": {
+			sql:      "SELECT $10, $1",
+			args:     []any{"a", nil, nil, nil, nil, nil, nil, nil, nil, "j"},
+			expected: "SELECT E'j', E'a'",
+		},
+		"missing argument": {
+			sql:       "SELECT $2",
+			args:      []any{"a"},
+			expectErr: true,
+		},
+		"NUL byte": {
+			sql:       "SELECT $1",
+			args:      []any{"a\x00b"},
+			expectErr: true,
+		},
+		"unsupported type": {
+			sql:       "SELECT $1",
+			args:      []any{42},
+			expectErr: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			statement, err := interpolateArgs(testCase.sql, testCase.args)
+
+			if testCase.expectErr {
+				if err == nil {
+					t.Fatalf("expected an error, got: %s", statement)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if statement != testCase.expected {
+				t.Errorf("expected %s, got %s", testCase.expected, statement)
+			}
+		})
+	}
+}
+
+// testManagementAPI serves the database query endpoint of testProjectRef
+// with handle, which gets the submitted statement.
+func testManagementAPI(t *testing.T, handle func(statement string) (int, any)) *managementAPIClient {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v1/projects/"+testProjectRef+"/database/query" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer sbp_test" {
+			t.Errorf("unexpected Authorization header %q", r.Header.Get("Authorization"))
+		}
+
+		var body struct {
+			Query string `json:"query"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decoding request: %s", err)
+		}
+
+		status, response := handle(body.Query)
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+	t.Cleanup(server.Close)
+
+	return newManagementAPIClient(server.URL+"/", "sbp_test", testProjectRef)
+}
+
+func TestManagementAPIClientQuery(t *testing.T) {
+	client := testManagementAPI(t, func(statement string) (int, any) {
+		if statement != "SELECT id FROM vault.secrets WHERE name = E'api_key'" {
+			t.Errorf("unexpected statement %s", statement)
+		}
+
+		return http.StatusCreated, []map[string]any{{"id": "4c1f2a3b-5d6e-4f70-8a9b-0c1d2e3f4a5b"}}
+	})
+
+	rows, err := client.query(context.Background(), lookupSecretIDQuery, "api_key")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(rows) != 1 || rows[0]["id"] != "4c1f2a3b-5d6e-4f70-8a9b-0c1d2e3f4a5b" {
+		t.Errorf("unexpected rows %v", rows)
+	}
+}
+
+func TestManagementAPIClientQueryError(t *testing.T) {
+	client := testManagementAPI(t, func(statement string) (int, any) {
+		return http.StatusBadRequest, map[string]string{"message": "permission denied for function create_secret"}
+	})
+
+	_, err := client.query(context.Background(), "SELECT 1")
+
+	var apiErr *managementAPIError
+	if !errors.As(err, &apiErr) || apiErr.status != http.StatusBadRequest || apiErr.message != "permission denied for function create_secret" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestQuoteString(t *testing.T) {
+	testCases := map[string]struct {
+		value     string
+		expected  string
+		expectErr bool
+	}{
+		"plain":     {value: "on", expected: "E'on'"},
+		"empty":     {value: "", expected: "E''"},
+		"quote":     {value: "it's", expected: "E'it''s'"},
+		"injection": {value: "x'; DROP TABLE vault.secrets; --", expected: "E'x''; DROP TABLE vault.secrets; --'"},
+		// Without escaping the backslash, \' would end the literal when
+		// standard_conforming_strings is off
+		"backslash quote": {value: `x\'; DROP TABLE vault.secrets; --`, expected: `E'x\\''; DROP TABLE vault.secrets; --'`},
+		"nul":             {value: "x\x00", expectErr: true},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			quoted, err := quoteString(testCase.value)
+
+			if testCase.expectErr {
+				if err == nil {
+					t.Fatalf("expected error for %q, got %s", testCase.value, quoted)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error for %q: %s", testCase.value, err)
+			}
+			if quoted != testCase.expected {
+				t.Errorf("expected %s, got %s", testCase.expected, quoted)
+			}
+		})
+	}
+}
+
+func TestValidateProjectRef(t *testing.T) {
+	if err := validateProjectRef(testProjectRef); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	for _, ref := range []string{"", "ABCDEFGHIJKLMNOPQRST", "abc", "abcdefghijklmnopqrs/"} {
+		if err := validateProjectRef(ref); err == nil {
+			t.Errorf("expected an error for %q", ref)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/hex"
+	"fmt"
+)
+
+// nonceLength is the size in bytes of a pgsodium deterministic AEAD nonce,
+// the kind Vault stores in vault.secrets.nonce.
+const nonceLength = 16
+
+// validateNonce returns an error if nonce is not a hex-encoded Vault nonce.
+func validateNonce(nonce string) error {
+	decoded, err := hex.DecodeString(nonce)
+	if err != nil {
+		return fmt.Errorf("nonce must be hex-encoded: %w", err)
+	}
+
+	if len(decoded) != nonceLength {
+		return fmt.Errorf("nonce must be %d bytes (%d hex characters), got %d bytes", nonceLength, nonceLength*2, len(decoded))
+	}
+
+	return nil
+}
@@ -0,0 +1,38 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateNonce(t *testing.T) {
+	testCases := map[string]struct {
+		nonce     string
+		expectErr bool
+	}{
+		"lowercase":  {nonce: "00112233445566778899aabbccddeeff"},
+		"uppercase":  {nonce: "00112233445566778899AABBCCDDEEFF"},
+		"empty":      {nonce: "", expectErr: true},
+		"too short":  {nonce: "00112233445566778899aabbccddee", expectErr: true},
+		"too long":   {nonce: strings.Repeat("00", nonceLength+1), expectErr: true},
+		"odd length": {nonce: "00112233445566778899aabbccddeef", expectErr: true},
+		"non-hex":    {nonce: "00112233445566778899aabbccddeegg", expectErr: true},
+		"prefixed":   {nonce: "\\x00112233445566778899aabbccddeeff", expectErr: true},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			err := validateNonce(testCase.nonce)
+
+			if testCase.expectErr && err == nil {
+				t.Errorf("expected error for %q, got none", testCase.nonce)
+			}
+			if !testCase.expectErr && err != nil {
+				t.Errorf("unexpected error for %q: %s", testCase.nonce, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"runtime"
+)
+
+// passfileWarning returns a warning if the passfile at path can be read by
+// users other than its owner, or an empty string if it can't. Like libpq,
+// only permissions of 0600 or stricter are considered safe; Windows has no
+// such permission bits, so nothing is checked there.
+func passfileWarning(info fs.FileInfo) string {
+	if runtime.GOOS == "windows" || info.Mode().Perm()&0o077 == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("The passfile has permissions %#o, so other users on this machine may be able to read the passwords in it. Restrict it with chmod 0600.", info.Mode().Perm())
+}
+
+// statPassfile returns the file info of the passfile at path, which must be
+// a regular file.
+func statPassfile(path string) (fs.FileInfo, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.Mode().IsRegular() {
+		return nil, fmt.Errorf("passfile %q is not a regular file", path)
+	}
+
+	return info, nil
+}
@@ -0,0 +1,70 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestPassfileWarning(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file permissions aren't checked on Windows")
+	}
+
+	testCases := map[string]struct {
+		mode        os.FileMode
+		wantWarning bool
+	}{
+		"owner only": {
+			mode: 0o600,
+		},
+		"owner read only": {
+			mode: 0o400,
+		},
+		"group readable": {
+			mode:        0o640,
+			wantWarning: true,
+		},
+		"world readable": {
+			mode:        0o644,
+			wantWarning: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), ".pgpass")
+			if err := os.WriteFile(path, []byte("*:*:*:postgres:secret\n"), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.Chmod(path, tc.mode); err != nil {
+				t.Fatal(err)
+			}
+
+			info, err := statPassfile(path)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if got := passfileWarning(info) != ""; got != tc.wantWarning {
+				t.Errorf("expected warning %t, got %t", tc.wantWarning, got)
+			}
+		})
+	}
+}
+
+func TestStatPassfile(t *testing.T) {
+	dir := t.TempDir()
+
+	if _, err := statPassfile(filepath.Join(dir, "missing")); err == nil {
+		t.Error("expected an error for a missing passfile")
+	}
+
+	if _, err := statPassfile(dir); err == nil {
+		t.Error("expected an error for a directory")
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// privilegeProbe is a privilege check_privileges verifies the connecting
+// role holds.
+type privilegeProbe struct {
+	privilege string
+	object    string
+
+	// check is a boolean SQL expression, NULL if the object doesn't exist
+	check string
+
+	// write is set for privileges only needed to change secrets
+	write bool
+}
+
+// functionPrivilegeCheck checks EXECUTE on any overload of a vault function.
+func functionPrivilegeCheck(function string) string {
+	return fmt.Sprintf("(SELECT bool_or(has_function_privilege(p.oid, 'EXECUTE')) FROM pg_proc p JOIN pg_namespace n ON n.oid = p.pronamespace "+
+		"WHERE n.nspname = 'vault' AND p.proname = '%s')", function)
+}
+
+// tablePrivilegeCheck checks privilege on a vault table or view.
+func tablePrivilegeCheck(table, privilege string) string {
+	return fmt.Sprintf("has_table_privilege(to_regclass('vault.%s'), '%s')", table, privilege)
+}
+
+// privilegeProbes are the privileges the provider's operations need.
+var privilegeProbes = []privilegeProbe{
+	{privilege: "EXECUTE", object: "function vault.create_secret", check: functionPrivilegeCheck("create_secret"), write: true},
+	{privilege: "EXECUTE", object: "function vault.update_secret", check: functionPrivilegeCheck("update_secret"), write: true},
+	{privilege: "INSERT", object: "table vault.secrets", check: tablePrivilegeCheck("secrets", "INSERT"), write: true},
+	{privilege: "DELETE", object: "table vault.secrets", check: tablePrivilegeCheck("secrets", "DELETE"), write: true},
+	{privilege: "SELECT", object: "view vault.decrypted_secrets", check: tablePrivilegeCheck("decrypted_secrets", "SELECT")},
+}
+
+// missingPrivileges describes each probe that wasn't granted, in order.
+func missingPrivileges(probes []privilegeProbe, granted []sql.NullBool) []string {
+	var missing []string
+	for i, probe := range probes {
+		switch {
+		case !granted[i].Valid:
+			missing = append(missing, fmt.Sprintf("%s on %s (%s does not exist)", probe.privilege, probe.object, probe.object))
+		case !granted[i].Bool:
+			missing = append(missing, fmt.Sprintf("%s on %s", probe.privilege, probe.object))
+		}
+	}
+
+	return missing
+}
+
+// checkPrivileges returns an error listing every privilege the connecting
+// role lacks, in a single query. A read-only provider only needs to read.
+func checkPrivileges(ctx context.Context, pool *pgxpool.Pool, readOnly bool) error {
+	var probes []privilegeProbe
+	checks := []string{"current_user"}
+	for _, probe := range privilegeProbes {
+		if readOnly && probe.write {
+			continue
+		}
+		probes = append(probes, probe)
+		checks = append(checks, probe.check)
+	}
+
+	var role string
+	granted := make([]sql.NullBool, len(probes))
+	dest := []any{&role}
+	for i := range granted {
+		dest = append(dest, &granted[i])
+	}
+
+	if err := pool.QueryRow(ctx, "SELECT "+strings.Join(checks, ", ")).Scan(dest...); err != nil {
+		return fmt.Errorf("checking privileges: %w", err)
+	}
+
+	missing := missingPrivileges(probes, granted)
+	if len(missing) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("role %q is missing these privileges:\n  - %s", role, strings.Join(missing, "\n  - "))
+}
@@ -0,0 +1,43 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+)
+
+func TestMissingPrivileges(t *testing.T) {
+	granted := sql.NullBool{Bool: true, Valid: true}
+	denied := sql.NullBool{Valid: true}
+	missingObject := sql.NullBool{}
+
+	testCases := map[string]struct {
+		granted  []sql.NullBool
+		expected []string
+	}{
+		"all granted": {
+			granted: []sql.NullBool{granted, granted, granted, granted, granted},
+		},
+		"denied": {
+			granted:  []sql.NullBool{granted, denied, granted, denied, granted},
+			expected: []string{"EXECUTE on function vault.update_secret", "DELETE on table vault.secrets"},
+		},
+		"missing object": {
+			granted:  []sql.NullBool{granted, granted, granted, granted, missingObject},
+			expected: []string{"SELECT on view vault.decrypted_secrets (view vault.decrypted_secrets does not exist)"},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			missing := missingPrivileges(privilegeProbes, testCase.granted)
+
+			if !reflect.DeepEqual(missing, testCase.expected) {
+				t.Errorf("expected %v, got %v", testCase.expected, missing)
+			}
+		})
+	}
+}
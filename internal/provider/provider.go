@@ -7,18 +7,24 @@ import (
 	"context"
 	"fmt"
 	"net/url"
-	"strconv"
+	"os"
+	"regexp"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
 	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -26,6 +32,10 @@ import (
 var _ provider.Provider = &SupabaseVaultProvider{}
 var _ provider.ProviderWithFunctions = &SupabaseVaultProvider{}
 var _ provider.ProviderWithEphemeralResources = &SupabaseVaultProvider{}
+var _ provider.ProviderWithValidateConfig = &SupabaseVaultProvider{}
+
+// supabasePoolerPort is the port Supabase's transaction-mode poolers listen on.
+const supabasePoolerPort = 6543
 
 // SupabaseVaultProvider defines the provider implementation.
 type SupabaseVaultProvider struct {
@@ -38,17 +48,201 @@ type SupabaseVaultProvider struct {
 // SupabaseVaultProviderModel describes the provider data model.
 type SupabaseVaultProviderModel struct {
 	Host     types.String `tfsdk:"host"`
+	Endpoint types.String `tfsdk:"endpoint"`
 	Port     types.Int64  `tfsdk:"port"`
 	Database types.String `tfsdk:"database"`
 	User     types.String `tfsdk:"user"`
 	Password types.String `tfsdk:"password"`
+	Passfile types.String `tfsdk:"passfile"`
+	Service  types.String `tfsdk:"service"`
 	SSLMode  types.String `tfsdk:"sslmode"`
+
+	ChannelBinding types.String `tfsdk:"channel_binding"`
+	SSLNegotiation types.String `tfsdk:"ssl_negotiation"`
+
+	LogQueries types.Bool `tfsdk:"log_queries"`
+
+	PoolAcquireTimeout types.String `tfsdk:"pool_acquire_timeout"`
+	ConnectJitter      types.String `tfsdk:"connect_jitter"`
+
+	AutoCreateExtensions types.Bool `tfsdk:"auto_create_extensions"`
+
+	CheckPrivileges types.Bool `tfsdk:"check_privileges"`
+	SkipPing        types.Bool `tfsdk:"skip_ping"`
+
+	ConnectionParams types.Map    `tfsdk:"connection_params"`
+	ConnectionQuery  types.String `tfsdk:"connection_query"`
+
+	AllowInvalidUTF8Names types.Bool `tfsdk:"allow_invalid_utf8_names"`
+
+	EnvironmentNaming types.String `tfsdk:"environment_naming"`
+
+	ReadOnly        types.Bool `tfsdk:"read_only"`
+	AutoReconnect   types.Bool `tfsdk:"auto_reconnect"`
+	ErrorOnMissing  types.Bool `tfsdk:"error_on_missing"`
+	UseTransactions types.Bool `tfsdk:"use_transactions"`
+	StrictKeyIDRead types.Bool `tfsdk:"strict_key_id_read"`
+
+	SessionLabel types.String `tfsdk:"session_label"`
+	SessionSQL   types.List   `tfsdk:"session_sql"`
+
+	AllowedNamePatterns types.List `tfsdk:"allowed_name_patterns"`
+
+	CorrelationID types.String `tfsdk:"correlation_id"`
+
+	DefaultDescription types.String `tfsdk:"default_description"`
+	FooterSeparator    types.String `tfsdk:"footer_separator"`
+
+	ShowFooterOnRead types.Bool `tfsdk:"show_footer_on_read"`
+
+	SuppressStateWarnings types.Bool `tfsdk:"suppress_state_warnings"`
+	SuppressTLSWarning    types.Bool `tfsdk:"suppress_tls_warning"`
+	QueryComments         types.Bool `tfsdk:"query_comments"`
+
+	TimestampTimezone types.String `tfsdk:"timestamp_timezone"`
+
+	TCPKeepalive         types.String `tfsdk:"tcp_keepalive"`
+	TCPKeepaliveInterval types.String `tfsdk:"tcp_keepalive_interval"`
+
+	QueryExecMode types.String `tfsdk:"query_exec_mode"`
+
+	MaxValueBytes types.Int64 `tfsdk:"max_value_bytes"`
+
+	AllowEmptyValue types.Bool `tfsdk:"allow_empty_value"`
+
+	MaxConcurrentOperations types.Int64 `tfsdk:"max_concurrent_operations"`
+
+	ManagementToken  types.String `tfsdk:"management_token"`
+	ProjectRef       types.String `tfsdk:"project_ref"`
+	ManagementAPIURL types.String `tfsdk:"management_api_url"`
 }
 
 // ProviderData holds the connection pool and version for resources.
 type ProviderData struct {
 	Pool    *pgxpool.Pool
 	Version string
+
+	// AcquireTimeout bounds how long an operation waits for a pooled
+	// connection. Zero means no separate deadline.
+	AcquireTimeout time.Duration
+
+	// ReadOnly makes every mutating operation fail.
+	ReadOnly bool
+
+	// ErrorOnMissing makes reading a managed secret that no longer exists
+	// fail instead of removing it from state.
+	ErrorOnMissing bool
+
+	// SkipPing leaves out pinging a new pool, so it connects on first use.
+	SkipPing bool
+
+	// AutoReconnect re-creates Pool once when an operation loses its
+	// database connection, see reconnecting.
+	AutoReconnect bool
+
+	// StrictKeyIDRead makes failing to read key_id back after writing a
+	// secret an error rather than storing null, see keyIDReadFailed.
+	StrictKeyIDRead bool
+
+	// UseTransactions runs each supabase-vault_secret operation in a single
+	// transaction, see beginOperationTx.
+	UseTransactions bool
+
+	// AllowInvalidUTF8Names base64-wraps names that aren't valid UTF-8
+	// instead of rejecting them.
+	AllowInvalidUTF8Names bool
+
+	// EnvironmentNaming is how secrets with an environment are stored, one
+	// of environmentNamings. Empty means environmentNamingPrefix.
+	EnvironmentNaming string
+
+	// AllowedNamePatterns restricts the names secrets can be written under.
+	// Empty means no restriction.
+	AllowedNamePatterns []*regexp.Regexp
+
+	// CorrelationID identifies this run in the provider's logs and, through
+	// SQL comments and application_name, in the database's logs.
+	CorrelationID string
+
+	// SessionLabel is the application_name every pooled connection reports,
+	// empty when not configured.
+	SessionLabel string
+
+	// SessionSQL are the statements run on every new connection, in order.
+	SessionSQL []string
+
+	// DefaultDescription is stored for secrets that don't set a
+	// description, empty when not configured.
+	DefaultDescription string
+
+	// FooterSeparator delimits the managed-by footer in descriptions, see
+	// footerSeparator.
+	FooterSeparator string
+
+	// ShowFooterOnRead reads descriptions back exactly as stored, without
+	// stripping the managed-by footer.
+	ShowFooterOnRead bool
+
+	// SuppressStateWarnings hides the plan warning about secret values
+	// being written to state.
+	SuppressStateWarnings bool
+
+	// QueryComments tags the statements of each resource operation with a
+	// comment naming it, see tagQueries.
+	QueryComments bool
+
+	// MaxValueBytes bounds the size of secret values, see maxValueBytes.
+	MaxValueBytes int64
+
+	// AllowEmptyValue lets supabase-vault_secret store an empty value, see
+	// checkEmptyValue.
+	AllowEmptyValue bool
+
+	// TimestampLocation is the time zone created_at and updated_at are
+	// formatted in. Nil means UTC.
+	TimestampLocation *time.Location
+
+	// managementAPI runs supabase-vault_secret's statements when the
+	// provider is configured with management_token, in which case Pool is
+	// nil. See secrets.
+	managementAPI *managementAPIClient
+
+	// operations bounds how many resource operations run at once, see
+	// beginOperation. Nil means no limit.
+	operations chan struct{}
+
+	// sessions holds the backend PIDs of the pool's open connections.
+	sessions sync.Map
+
+	// poolConfig is the configuration Pool was created from, the base for
+	// resource-level connection overrides and for reconnecting.
+	poolConfig *pgxpool.Config
+
+	// poolMu guards replacing Pool when reconnecting.
+	poolMu sync.RWMutex
+
+	// overrides caches the provider data of every connection override
+	// opened so far, keyed by ConnectionOverrideModel.key.
+	overrides   map[string]*ProviderData
+	overridesMu sync.Mutex
+
+	// queryExecModeSet records that query_exec_mode was configured, so it
+	// isn't overridden for a detected Supabase transaction-mode pooler.
+	queryExecModeSet bool
+
+	// simpleProtocol is set once the connection is known to go through a
+	// transaction-mode pooler, after which queries avoid prepared statements.
+	simpleProtocol atomic.Bool
+
+	// vaultExt caches the installed supabase_vault extension, see
+	// vaultExtension.
+	vaultExt         *vaultExtension
+	vaultExtensionMu sync.Mutex
+
+	// keys caches whether pgsodium keys exist by lowercase id, see
+	// keyExists.
+	keys   map[string]bool
+	keysMu sync.Mutex
 }
 
 func (p *SupabaseVaultProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -60,35 +254,604 @@ func (p *SupabaseVaultProvider) Schema(ctx context.Context, req provider.SchemaR
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
 			"host": schema.StringAttribute{
-				MarkdownDescription: "PostgreSQL host address",
-				Required:            true,
+				MarkdownDescription: "PostgreSQL host address. May include a scheme, port and database (e.g. `db.example.supabase.co:5432/postgres`), which are normalized. Exactly one of `host`, `endpoint` or `management_token` must be set, unless `service` supplies the host. Falls back to the `PGHOST` environment variable.",
+				Optional:            true,
+			},
+			"service": schema.StringAttribute{
+				MarkdownDescription: "Name of a service in the connection service file (`PGSERVICEFILE` or `~/.pg_service.conf`) to take the connection settings from, like libpq's `service` parameter. May also be set with `PGSERVICE`. " +
+					"`host`, `endpoint`, `port`, `database`, `user` and `password` override the service's settings when set, and no defaults are applied on top of it. Conflicts with `management_token`.",
+				Optional: true,
+			},
+			"endpoint": schema.StringAttribute{
+				MarkdownDescription: "Explicit `host:port` to connect to, used verbatim without the normalization applied to `host`. Useful for read-replica or region-specific endpoints. Conflicts with `host` and `port`.",
+				Optional:            true,
 			},
 			"port": schema.Int64Attribute{
-				MarkdownDescription: "PostgreSQL port number",
+				MarkdownDescription: "PostgreSQL port number. Falls back to the `PGPORT` environment variable, then `5432`.",
 				Optional:            true,
 			},
 			"database": schema.StringAttribute{
-				MarkdownDescription: "PostgreSQL database name (defaults to 'postgres')",
+				MarkdownDescription: "PostgreSQL database name. Falls back to the `PGDATABASE` environment variable, then `postgres`.",
 				Optional:            true,
 			},
 			"user": schema.StringAttribute{
-				MarkdownDescription: "PostgreSQL user (defaults to 'postgres')",
+				MarkdownDescription: "PostgreSQL user. Falls back to the `PGUSER` environment variable, then `postgres`.",
 				Optional:            true,
 			},
 			"password": schema.StringAttribute{
-				MarkdownDescription: "PostgreSQL password",
-				Required:            true,
+				MarkdownDescription: "PostgreSQL password. When omitted, the password is looked up in the passfile: `passfile`, `PGPASSFILE` or `~/.pgpass`, as libpq does.",
+				Optional:            true,
 				Sensitive:           true,
 			},
+			"passfile": schema.StringAttribute{
+				MarkdownDescription: "Path to a PostgreSQL password file in the `.pgpass` format to look the password up in, instead of setting `password`. " +
+					"Defaults to `PGPASSFILE`, or `~/.pgpass` when that isn't set. A warning is shown if the file can be read by other users.",
+				Optional: true,
+			},
 			"sslmode": schema.StringAttribute{
 				MarkdownDescription: "PostgreSQL SSL mode (require, verify-full, etc.). If not specified, Supabase will use its default SSL configuration.",
 				Optional:            true,
 			},
+			"channel_binding": schema.StringAttribute{
+				MarkdownDescription: "SCRAM channel binding, one of `disable`, `prefer` or `require`. With `require` the connection fails unless the server authenticates with SCRAM-SHA-256-PLUS, binding the password exchange to the TLS session so it can't be relayed by a man in the middle. " +
+					"Channel binding needs TLS, so `require` can't be combined with `sslmode = \"disable\"`; pair it with `verify-full` for the strongest guarantees. If not specified, the driver's default of `prefer` is used.",
+				Optional: true,
+			},
+			"ssl_negotiation": schema.StringAttribute{
+				MarkdownDescription: "How TLS is negotiated, one of `postgres` or `direct`. With `direct` the TLS handshake starts immediately instead of after a plaintext SSLRequest, saving a round trip; it needs PostgreSQL 17 or later on the server and an `sslmode` of `require` or stricter (an unset `sslmode` is treated as `require`). If not specified, `postgres` is used.",
+				Optional:            true,
+			},
+			"log_queries": schema.BoolAttribute{
+				MarkdownDescription: "Log every SQL statement the provider executes through the Terraform log (visible with `TF_LOG=DEBUG` or lower). Bind parameters are always redacted. Defaults to `false`.",
+				Optional:            true,
+			},
+			"pool_acquire_timeout": schema.StringAttribute{
+				MarkdownDescription: "Maximum time an operation waits to acquire a connection from the pool, as a Go duration string (e.g. `30s`). This is separate from statement execution time. If not specified, operations wait until a connection becomes available.",
+				Optional:            true,
+			},
+			"connect_jitter": schema.StringAttribute{
+				MarkdownDescription: "Wait a random time of up to this Go duration string (e.g. `5s`) before first connecting, so that many runs started at once against the same database spread out their connection attempts. " +
+					"Defaults to `0s`, connecting right away.",
+				Optional: true,
+			},
+			"auto_create_extensions": schema.BoolAttribute{
+				MarkdownDescription: "Create the `supabase_vault` extension (and `pgsodium`, where available) if they are not installed yet. Intended for local development and ephemeral test databases; the connecting role needs permission to create extensions, which usually means superuser. Defaults to `false`.",
+				Optional:            true,
+			},
+			"skip_ping": schema.BoolAttribute{
+				MarkdownDescription: "Create the connection pool without pinging the database, for roles that can connect but not run the ping's query, or to save the round trip. " +
+					"Connection problems then surface on the first operation instead of when the provider is configured. Defaults to `false`.",
+				Optional: true,
+			},
+			"check_privileges": schema.BoolAttribute{
+				MarkdownDescription: "Check when the provider is configured that the connecting role can execute `vault.create_secret` and `vault.update_secret`, insert into and delete from `vault.secrets` and select from `vault.decrypted_secrets`, " +
+					"failing with a single error listing every missing privilege instead of partway through an apply. A `read_only` provider only checks it can select. Costs one extra query per run. Defaults to `false`.",
+				Optional: true,
+			},
+			"connection_params": schema.MapAttribute{
+				MarkdownDescription: "Additional connection parameters not modelled by the provider (e.g. `target_session_attrs`, `options`). Dedicated attributes such as `sslmode` take precedence; `host`, `port`, `user`, `password` and `database` can't be set here.",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"connection_query": schema.StringAttribute{
+				MarkdownDescription: "Additional connection parameters as a query string, e.g. `connect_timeout=5&target_session_attrs=read-write`. Values are URL-decoded and merged with `connection_params` under the same rules; a parameter can't be set in both.",
+				Optional:            true,
+			},
+			"allow_invalid_utf8_names": schema.BoolAttribute{
+				MarkdownDescription: "Store secret names that aren't valid UTF-8 base64-encoded (prefixed with `base64:`) instead of rejecting them. Defaults to `false`.",
+				Optional:            true,
+			},
+			"environment_naming": schema.StringAttribute{
+				MarkdownDescription: "How `supabase-vault_secret` stores secrets that set `environment`. `prefix` stores them as `<environment>_<name>`, so the same `name` can be used in every environment; " +
+					"`label` stores the name unchanged. Either way the environment is also stored as the `" + environmentLabel + "` label. Defaults to `prefix`.",
+				Optional: true,
+			},
+			"read_only": schema.BoolAttribute{
+				MarkdownDescription: "Refuse to create, update or delete secrets. Reads and data sources keep working, which makes this a safety rail for plan-only or audit runs against production. Defaults to `false`.",
+				Optional:            true,
+			},
+			"error_on_missing": schema.BoolAttribute{
+				MarkdownDescription: "Fail refreshing a managed secret that was deleted outside Terraform, instead of removing it from state so the next apply quietly recreates it. " +
+					"Applies to `supabase-vault_secret`, `supabase-vault_secret_metadata` and `supabase-vault_bulk_secrets`. Defaults to `false`.",
+				Optional: true,
+			},
+			"strict_key_id_read": schema.BoolAttribute{
+				MarkdownDescription: "Fail creating or updating a `supabase-vault_secret` when its `key_id` and timestamps can't be read back afterwards, instead of storing them as null with a warning in the logs. " +
+					"A secret created this way is tainted and replaced on the next apply. Defaults to `false`.",
+				Optional: true,
+			},
+			"use_transactions": schema.BoolAttribute{
+				MarkdownDescription: "Run each `supabase-vault_secret` create, update and delete in a single transaction, committed only once every statement of the operation has succeeded. " +
+					"A create that fails part way, e.g. while reading back the new secret's `key_id`, then leaves no secret behind. " +
+					"Each operation holds one pooled connection until it ends. Has no effect with `management_token`. Defaults to `false`.",
+				Optional: true,
+			},
+			"auto_reconnect": schema.BoolAttribute{
+				MarkdownDescription: "Re-create the connection pool once and retry when an operation loses its database connection, e.g. because Supabase restarted the database during a long apply. " +
+					"Only failures that happened before the statement could take effect are retried. Defaults to `false`.",
+				Optional: true,
+			},
+			"allowed_name_patterns": schema.ListAttribute{
+				MarkdownDescription: "Regular expressions (Go [RE2 syntax](https://github.com/google/re2/wiki/Syntax)) secret names must match at least one of, e.g. `^[A-Z][A-Z0-9_]+$`. Creating or renaming a secret to any other name is refused. Patterns aren't anchored implicitly. If not specified, any name is allowed.",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"correlation_id": schema.StringAttribute{
+				MarkdownDescription: "ID that ties this run's provider logs to the database's logs. It is added to every provider log entry, prefixed to every SQL statement as a `/* correlation_id=... */` comment and, unless `session_label` or a `connection_params` `application_name` is set, reported as the `application_name` `tf-supabase-vault/<id>`. Up to 64 letters, digits, `_`, `.`, `:` or `-`. If not specified, a random ID is generated for each run.",
+				Optional:            true,
+			},
+			"default_description": schema.StringAttribute{
+				MarkdownDescription: "Description stored for every secret that doesn't set `description`, e.g. `\"Provisioned via platform IaC\"`. The managed-by footer is still appended, and a resource-level `description` replaces it.",
+				Optional:            true,
+			},
+			"footer_separator": schema.StringAttribute{
+				MarkdownDescription: "Separator between a secret's description and the managed-by footer the provider appends to it, e.g. `\"\\n\\n<!-- terraform -->\\n\"` for dashboards that render descriptions as markdown. " +
+					"Footers written with the default separator are still recognized after changing it. Defaults to `\"\\n\\n---\\n\"`.",
+				Optional: true,
+			},
+			"show_footer_on_read": schema.BoolAttribute{
+				MarkdownDescription: "Read descriptions back exactly as stored, including the managed-by footer, instead of stripping it. Useful for debugging footer-related drift, but every `supabase-vault_secret` will then plan a description change on each run. Defaults to `false`.",
+				Optional:            true,
+			},
+			"suppress_state_warnings": schema.BoolAttribute{
+				MarkdownDescription: "Hide the plan warning shown whenever a `supabase-vault_secret` writes `value` to the Terraform state, e.g. once state is known to be stored encrypted with restricted access. Defaults to `false`.",
+				Optional:            true,
+			},
+			"suppress_tls_warning": schema.BoolAttribute{
+				MarkdownDescription: "Hide the warning shown when the connection to a host other than localhost may be unencrypted, because `sslmode` is `disable`, `allow` or `prefer` or isn't set (libpq's default is `prefer`, which falls back to plaintext). Defaults to `false`.",
+				Optional:            true,
+			},
+			"query_comments": schema.BoolAttribute{
+				MarkdownDescription: "Prefix every statement a `supabase-vault_secret` runs with a comment naming the operation, e.g. `/* terraform supabase-vault_secret create */`, so it can be attributed in `pg_stat_activity` and the Supabase query logs. " +
+					"Secret names and values are never included; use `correlation_id` to tie the statements to a run's logs, which name the secret. Defaults to `false`.",
+				Optional: true,
+			},
+			"timestamp_timezone": schema.StringAttribute{
+				MarkdownDescription: "Time zone `created_at` and `updated_at` are formatted in, as an IANA name such as `\"UTC\"`, `\"Europe/Berlin\"` or `\"Local\"` for the time zone of the machine running Terraform. Timestamps are RFC 3339 with the zone's offset. Defaults to `\"UTC\"`.",
+				Optional:            true,
+			},
+			"tcp_keepalive": schema.StringAttribute{
+				MarkdownDescription: "Idle time before the first TCP keepalive probe is sent on a database connection, e.g. `\"60s\"`. Keeps idle connections from being dropped by load balancers or NAT gateways during long applies. If neither this nor `tcp_keepalive_interval` is specified, pgx's default dialer settings are used.",
+				Optional:            true,
+			},
+			"tcp_keepalive_interval": schema.StringAttribute{
+				MarkdownDescription: "Time between TCP keepalive probes once they have started, e.g. `\"15s\"`. If only one of `tcp_keepalive` and `tcp_keepalive_interval` is specified, the other defaults to 15 seconds.",
+				Optional:            true,
+			},
+			"query_exec_mode": schema.StringAttribute{
+				MarkdownDescription: "How pgx executes queries, one of:\n" +
+					"  - `cache_statement`: prepare each statement once per connection and reuse it. Fastest, but breaks behind transaction-mode poolers.\n" +
+					"  - `cache_describe`: cache statement descriptions and execute unnamed statements. Nearly as fast, but a cached description goes stale if a table changes during the run.\n" +
+					"  - `describe_exec`: describe and execute every statement without caching. Works behind poolers at the cost of an extra round trip.\n" +
+					"  - `exec`: execute with the extended protocol, letting PostgreSQL infer parameter types. One round trip and pooler-safe, but parameters are sent as text.\n" +
+					"  - `simple`: the simple query protocol with client-side parameter interpolation. Works with any pooler.\n\n" +
+					"If not specified, `cache_statement` is used, or `simple` behind a Supabase transaction-mode pooler: on port 6543, or on a `*.pooler.supabase.com` host on any port but the session-mode 5432. " +
+					"Independently of this setting, the first prepared-statement error switches the provider to the simple protocol.",
+				Optional: true,
+			},
+			"max_value_bytes": schema.Int64Attribute{
+				MarkdownDescription: "Largest secret value, in bytes, `supabase-vault_secret` stores. Larger values are rejected before reaching the database, rather than failing during encryption. Defaults to `1048576` (1 MiB).",
+				Optional:            true,
+			},
+			"allow_empty_value": schema.BoolAttribute{
+				MarkdownDescription: "Let `supabase-vault_secret` store an empty value. An empty value is usually a mistake, such as an unset variable passed as `\"\"`, so it is rejected at plan time unless this is set. Defaults to `false`.",
+				Optional:            true,
+			},
+			"max_concurrent_operations": schema.Int64Attribute{
+				MarkdownDescription: "Maximum number of resource operations that query the database at once, however high Terraform's `-parallelism`. " +
+					"Further operations wait for a running one to finish. Use it to stay within the connection limits of a Supabase project. If not specified, operations aren't limited.",
+				Optional: true,
+			},
+			"management_token": schema.StringAttribute{
+				MarkdownDescription: "Supabase personal access token to manage secrets through the [Management API](https://supabase.com/docs/reference/api) instead of a Postgres connection, for projects behind a firewall. " +
+					"Requires `project_ref`, and replaces `host` and `endpoint`. Only `supabase-vault_secret` is supported in this mode, without `nonce`, `validate_only`, `content_addressed`, `connection` or importing; other resources and data sources fail with an error. " +
+					"The Management API has no bind parameters, so secret values are sent inside the SQL text as quoted literals rather than separately from it, and may appear wherever Supabase logs the statements it runs.",
+				Optional:  true,
+				Sensitive: true,
+			},
+			"project_ref": schema.StringAttribute{
+				MarkdownDescription: "Reference of the Supabase project to manage with `management_token`, the subdomain of the project's URL",
+				Optional:            true,
+			},
+			"management_api_url": schema.StringAttribute{
+				MarkdownDescription: "Base URL of the Supabase Management API. Defaults to `" + defaultManagementAPIURL + "`.",
+				Optional:            true,
+			},
+			"session_label": schema.StringAttribute{
+				MarkdownDescription: "Label reported as the `application_name` of every connection the provider opens, overriding any `application_name` in `connection_params`. Sessions left behind by aborted runs can then be found in `pg_stat_activity` and terminated with the `supabase-vault_session_cleanup` resource.",
+				Optional:            true,
+			},
+			"session_sql": schema.ListAttribute{
+				MarkdownDescription: "SQL statements run in order on every new connection the provider opens, e.g. `SET statement_timeout = '30s'` or `SET search_path = vault, public`, for session setup no other attribute covers. A failing statement fails the connection. " +
+					"Each entry must be a single `SET <setting> = <value>` or `SET <setting> TO <value>` statement; `role`, `session_authorization` and `session_replication_role` can't be set. Behind a transaction-mode pooler, session settings may not persist across transactions.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
 		},
 	}
 }
 
+func (p *SupabaseVaultProvider) ValidateConfig(ctx context.Context, req provider.ValidateConfigRequest, resp *provider.ValidateConfigResponse) {
+	var data SupabaseVaultProviderModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Catch misconfigurations at plan time rather than when Configure opens a
+	// pool. Unknown values are checked again in Configure once they're known.
+	switch {
+	case !data.ManagementToken.IsNull() && (!data.Endpoint.IsNull() || !data.Host.IsNull() || !data.Service.IsNull()):
+		resp.Diagnostics.AddAttributeError(
+			path.Root("management_token"),
+			"Conflicting connection attributes",
+			"management_token manages secrets without a database connection, so host, endpoint and service must not be set alongside it.",
+		)
+	case !data.Endpoint.IsNull() && !data.Host.IsNull():
+		resp.Diagnostics.AddAttributeError(
+			path.Root("endpoint"),
+			"Conflicting connection attributes",
+			"Only one of host or endpoint can be set.",
+		)
+	case !data.Endpoint.IsNull() && !data.Port.IsNull():
+		resp.Diagnostics.AddAttributeError(
+			path.Root("port"),
+			"Conflicting connection attributes",
+			"endpoint already includes the port, so port must not be set alongside it.",
+		)
+	case data.Endpoint.IsNull() && data.Host.IsNull() && data.ManagementToken.IsNull() && data.Service.IsNull() && os.Getenv("PGSERVICE") == "" && os.Getenv("PGHOST") == "":
+		resp.Diagnostics.AddError(
+			"Missing connection attribute",
+			"One of host, endpoint, service or management_token must be set, or the PGHOST environment variable.",
+		)
+	}
+
+	if !data.ManagementToken.IsNull() && data.ProjectRef.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("project_ref"),
+			"Missing project_ref",
+			"project_ref must be set together with management_token.",
+		)
+	}
+	if data.ManagementToken.IsNull() && !data.ProjectRef.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("project_ref"),
+			"Unused project_ref",
+			"project_ref only applies together with management_token.",
+		)
+	}
+	if isKnown(data.ProjectRef) {
+		if err := validateProjectRef(data.ProjectRef.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("project_ref"),
+				"Invalid project_ref",
+				err.Error(),
+			)
+		}
+	}
+
+	if !data.Password.IsNull() && !data.Passfile.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("passfile"),
+			"Conflicting password attributes",
+			"Only one of password or passfile can be set.",
+		)
+	}
+
+	if isKnown(data.Service) && strings.TrimSpace(data.Service.ValueString()) == "" {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("service"),
+			"Invalid service",
+			"service must not be empty.",
+		)
+	}
+
+	if isKnown(data.Host) && strings.TrimSpace(data.Host.ValueString()) == "" {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("host"),
+			"Invalid host",
+			"host must not be empty.",
+		)
+	}
+
+	if isKnown(data.Endpoint) {
+		if _, err := validateEndpoint(data.Endpoint.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("endpoint"),
+				"Invalid endpoint",
+				err.Error(),
+			)
+		}
+	}
+
+	if !data.Port.IsNull() && !data.Port.IsUnknown() {
+		if port := data.Port.ValueInt64(); port < 1 || port > 65535 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("port"),
+				"Invalid port",
+				fmt.Sprintf("port must be between 1 and 65535, got: %d", port),
+			)
+		}
+	}
+
+	if !data.MaxValueBytes.IsNull() && !data.MaxValueBytes.IsUnknown() {
+		if maxValueBytes := data.MaxValueBytes.ValueInt64(); maxValueBytes < 1 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("max_value_bytes"),
+				"Invalid max value bytes",
+				fmt.Sprintf("max_value_bytes must be at least 1, got: %d", maxValueBytes),
+			)
+		}
+	}
+
+	if !data.MaxConcurrentOperations.IsNull() && !data.MaxConcurrentOperations.IsUnknown() {
+		if maxConcurrentOperations := data.MaxConcurrentOperations.ValueInt64(); maxConcurrentOperations < 1 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("max_concurrent_operations"),
+				"Invalid max concurrent operations",
+				fmt.Sprintf("max_concurrent_operations must be at least 1, got: %d", maxConcurrentOperations),
+			)
+		}
+	}
+
+	if isKnown(data.Database) {
+		if err := validateDatabaseName(data.Database.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("database"),
+				"Invalid database name",
+				err.Error(),
+			)
+		}
+	}
+
+	if isKnown(data.SSLMode) {
+		if err := validateSSLMode(data.SSLMode.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("sslmode"),
+				"Invalid sslmode",
+				err.Error(),
+			)
+		}
+	}
+
+	if isKnown(data.ChannelBinding) {
+		if err := validateChannelBinding(data.ChannelBinding.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("channel_binding"),
+				"Invalid channel_binding",
+				err.Error(),
+			)
+		}
+
+		// Channel binding is tied to the TLS session
+		if data.ChannelBinding.ValueString() == "require" && data.SSLMode.ValueString() == "disable" {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("channel_binding"),
+				"Conflicting channel_binding",
+				"channel_binding = \"require\" needs TLS, but sslmode is \"disable\".",
+			)
+		}
+	}
+
+	if isKnown(data.SSLNegotiation) {
+		if err := validateSSLNegotiation(data.SSLNegotiation.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("ssl_negotiation"),
+				"Invalid ssl_negotiation",
+				err.Error(),
+			)
+		}
+
+		// Direct negotiation starts with the TLS handshake, so TLS can't be optional
+		if sslMode := data.SSLMode.ValueString(); data.SSLNegotiation.ValueString() == "direct" && (sslMode == "disable" || sslMode == "allow") {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("ssl_negotiation"),
+				"Conflicting ssl_negotiation",
+				fmt.Sprintf("ssl_negotiation = \"direct\" needs TLS, but sslmode is %q. Use require, verify-ca or verify-full.", sslMode),
+			)
+		}
+	}
+
+	if isKnown(data.TimestampTimezone) {
+		if _, err := parseTimestampTimezone(data.TimestampTimezone.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("timestamp_timezone"),
+				"Invalid timestamp_timezone",
+				err.Error(),
+			)
+		}
+	}
+
+	if isKnown(data.PoolAcquireTimeout) {
+		if timeout, err := time.ParseDuration(data.PoolAcquireTimeout.ValueString()); err != nil || timeout <= 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("pool_acquire_timeout"),
+				"Invalid pool acquire timeout",
+				fmt.Sprintf("Expected a positive duration such as \"30s\", got: %q", data.PoolAcquireTimeout.ValueString()),
+			)
+		}
+	}
+
+	if isKnown(data.ConnectJitter) {
+		if _, err := parseConnectJitter(data.ConnectJitter.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("connect_jitter"),
+				"Invalid connect jitter",
+				err.Error(),
+			)
+		}
+	}
+
+	keepAliveAttributes := map[string]types.String{
+		"tcp_keepalive":          data.TCPKeepalive,
+		"tcp_keepalive_interval": data.TCPKeepaliveInterval,
+	}
+	for attribute, value := range keepAliveAttributes {
+		if !isKnown(value) {
+			continue
+		}
+		if _, err := parseKeepAliveDuration(value.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root(attribute),
+				"Invalid TCP keepalive duration",
+				err.Error(),
+			)
+		}
+	}
+
+	if isKnown(data.QueryExecMode) {
+		if _, err := parseQueryExecMode(data.QueryExecMode.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("query_exec_mode"),
+				"Invalid query execution mode",
+				err.Error(),
+			)
+		}
+	}
+
+	if isKnown(data.CorrelationID) {
+		if err := validateCorrelationID(data.CorrelationID.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("correlation_id"),
+				"Invalid correlation ID",
+				err.Error(),
+			)
+		}
+	}
+
+	if isKnown(data.DefaultDescription) {
+		if err := validateDescription(data.DefaultDescription.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("default_description"),
+				"Invalid default description",
+				err.Error(),
+			)
+		}
+	}
+
+	if isKnown(data.EnvironmentNaming) {
+		if err := validateEnvironmentNaming(data.EnvironmentNaming.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("environment_naming"),
+				"Invalid environment_naming",
+				err.Error(),
+			)
+		}
+	}
+
+	if isKnown(data.FooterSeparator) {
+		if err := validateFooterSeparator(data.FooterSeparator.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("footer_separator"),
+				"Invalid footer separator",
+				err.Error(),
+			)
+		}
+	}
+
+	if isKnown(data.SessionLabel) {
+		if err := validateSessionLabel(data.SessionLabel.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("session_label"),
+				"Invalid session label",
+				err.Error(),
+			)
+		}
+	}
+
+	if !data.SessionSQL.IsNull() && !data.SessionSQL.IsUnknown() {
+		statements := []types.String{}
+		resp.Diagnostics.Append(data.SessionSQL.ElementsAs(ctx, &statements, false)...)
+
+		for i, statement := range statements {
+			if !isKnown(statement) {
+				continue
+			}
+			if err := validateSessionStatement(statement.ValueString()); err != nil {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("session_sql").AtListIndex(i),
+					"Invalid session SQL",
+					err.Error(),
+				)
+			}
+		}
+	}
+
+	if !data.AllowedNamePatterns.IsNull() && !data.AllowedNamePatterns.IsUnknown() {
+		patterns := []types.String{}
+		resp.Diagnostics.Append(data.AllowedNamePatterns.ElementsAs(ctx, &patterns, false)...)
+
+		for i, pattern := range patterns {
+			if !isKnown(pattern) {
+				continue
+			}
+			if _, err := regexp.Compile(pattern.ValueString()); err != nil {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("allowed_name_patterns").AtListIndex(i),
+					"Invalid name pattern",
+					fmt.Sprintf("Unable to compile %q: %s", pattern.ValueString(), err),
+				)
+			}
+		}
+	}
+
+	if !data.ConnectionParams.IsNull() && !data.ConnectionParams.IsUnknown() {
+		connectionParams := map[string]types.String{}
+		resp.Diagnostics.Append(data.ConnectionParams.ElementsAs(ctx, &connectionParams, false)...)
+
+		known := map[string]string{}
+		for key, value := range connectionParams {
+			if value.IsUnknown() {
+				continue
+			}
+			known[key] = value.ValueString()
+		}
+
+		if _, err := sanitizeConnectionParams(known); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("connection_params"),
+				"Invalid connection parameter",
+				err.Error(),
+			)
+		}
+	}
+
+	// Conflicts with connection_params are only checked once both are known
+	if isKnown(data.ConnectionQuery) {
+		known := map[string]string{}
+		if !data.ConnectionParams.IsUnknown() {
+			connectionParams := map[string]types.String{}
+			resp.Diagnostics.Append(data.ConnectionParams.ElementsAs(ctx, &connectionParams, false)...)
+			for key, value := range connectionParams {
+				known[key] = value.ValueString()
+			}
+		}
+
+		merged, err := mergeConnectionQuery(data.ConnectionQuery.ValueString(), known)
+		if err == nil && !data.ConnectionParams.IsUnknown() {
+			_, err = sanitizeConnectionParams(merged)
+		}
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("connection_query"),
+				"Invalid connection parameter",
+				err.Error(),
+			)
+		}
+	}
+}
+
+// isKnown reports whether value is set and known.
+func isKnown(value types.String) bool {
+	return !value.IsNull() && !value.IsUnknown()
+}
+
 func (p *SupabaseVaultProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
+	ctx, span := startSpan(ctx, "provider.configure")
+	defer span.end(&resp.Diagnostics)
+
 	var data SupabaseVaultProviderModel
 
 	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
@@ -97,103 +860,563 @@ func (p *SupabaseVaultProvider) Configure(ctx context.Context, req provider.Conf
 		return
 	}
 
-	// Set defaults
-	port := int64(5432)
+	// Set defaults. A service supplies its own settings, so only explicit
+	// attributes are put on top of it and pgx fills in the rest. Like libpq,
+	// fall back to the PGSERVICE environment variable.
+	service := data.Service.ValueString()
+	if data.Service.IsNull() {
+		service = os.Getenv("PGSERVICE")
+	}
+	useService := service != ""
+
+	port, portSource := int64(5432), settingSourceDefault
 	if !data.Port.IsNull() {
-		port = data.Port.ValueInt64()
-	}
-
-	database := "postgres"
-	if !data.Database.IsNull() {
-		database = data.Database.ValueString()
-	}
-
-	user := "postgres"
-	if !data.User.IsNull() {
-		user = data.User.ValueString()
-	}
-
-	// Strip protocol prefix from host if present (e.g., https:// or http://)
-	host := data.Host.ValueString()
-	host = strings.TrimPrefix(host, "https://")
-	host = strings.TrimPrefix(host, "http://")
-	host = strings.TrimPrefix(host, "postgres://")
-	host = strings.TrimPrefix(host, "postgresql://")
-	// Remove trailing slash if present
-	host = strings.TrimSuffix(host, "/")
-
-	// Parse host to extract just the hostname (in case port/database are included)
-	// Handle formats like: hostname, hostname:port, hostname:port/database
-	hostname := host
-	parsedPort := port
-	parsedDatabase := database
-
-	// Check if host contains port (format: hostname:port or hostname:port/database)
-	if strings.Contains(host, ":") {
-		parts := strings.SplitN(host, ":", 2)
-		hostname = parts[0]
-		remaining := parts[1]
-
-		// Check if remaining part contains database (format: port/database)
-		if strings.Contains(remaining, "/") {
-			dbParts := strings.SplitN(remaining, "/", 2)
-			if portStr := dbParts[0]; portStr != "" {
-				// Port is already in host, use it
-				if parsedPortInt, err := strconv.ParseInt(portStr, 10, 64); err == nil {
-					parsedPort = parsedPortInt
-				}
-			}
-			if dbName := dbParts[1]; dbName != "" {
-				// Database is already in host, use it
-				parsedDatabase = dbName
+		port, portSource = data.Port.ValueInt64(), settingSourceConfig
+	} else if useService {
+		port, portSource = 0, settingSourceService
+	} else if envPort := os.Getenv("PGPORT"); envPort != "" {
+		parsed, err := parseEnvPort(envPort)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Invalid PGPORT",
+				err.Error(),
+			)
+			return
+		}
+		port, portSource = parsed, "PGPORT"
+	}
+
+	// Fall back to the standard libpq environment variables before the
+	// defaults
+	database, databaseSource := libpqSetting(data.Database, "PGDATABASE", "postgres")
+	if data.Database.IsNull() && useService {
+		database, databaseSource = "", settingSourceService
+	}
+
+	user, userSource := libpqSetting(data.User, "PGUSER", "postgres")
+	if data.User.IsNull() && useService {
+		user, userSource = "", settingSourceService
+	}
+
+	// PGHOST only stands in for host when nothing else says where to connect
+	host, hostSource := data.Host.ValueString(), settingSourceConfig
+	hasHost := !data.Host.IsNull()
+	switch {
+	case !data.Endpoint.IsNull():
+		hostSource = settingSourceEndpoint
+	case useService && !hasHost:
+		hostSource = settingSourceService
+	case !hasHost:
+		host = os.Getenv("PGHOST")
+		hasHost, hostSource = host != "", "PGHOST"
+	}
+
+	var acquireTimeout time.Duration
+	if !data.PoolAcquireTimeout.IsNull() {
+		var err error
+		acquireTimeout, err = time.ParseDuration(data.PoolAcquireTimeout.ValueString())
+		if err != nil || acquireTimeout <= 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("pool_acquire_timeout"),
+				"Invalid pool acquire timeout",
+				fmt.Sprintf("Expected a positive duration such as \"30s\", got: %q", data.PoolAcquireTimeout.ValueString()),
+			)
+			return
+		}
+	}
+
+	var connectJitter time.Duration
+	if !data.ConnectJitter.IsNull() {
+		var err error
+		connectJitter, err = parseConnectJitter(data.ConnectJitter.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("connect_jitter"),
+				"Invalid connect jitter",
+				err.Error(),
+			)
+			return
+		}
+	}
+
+	timestampLocation := time.UTC
+	if !data.TimestampTimezone.IsNull() {
+		var err error
+		timestampLocation, err = parseTimestampTimezone(data.TimestampTimezone.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("timestamp_timezone"),
+				"Invalid timestamp_timezone",
+				err.Error(),
+			)
+			return
+		}
+	}
+
+	// Compile the name patterns once rather than on every write
+	var allowedNamePatterns []*regexp.Regexp
+	if !data.AllowedNamePatterns.IsNull() {
+		patterns := []string{}
+		resp.Diagnostics.Append(data.AllowedNamePatterns.ElementsAs(ctx, &patterns, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		for i, pattern := range patterns {
+			compiled, err := regexp.Compile(pattern)
+			if err != nil {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("allowed_name_patterns").AtListIndex(i),
+					"Invalid name pattern",
+					fmt.Sprintf("Unable to compile %q: %s", pattern, err),
+				)
+				return
 			}
-		} else {
-			// Only port, no database
-			if portStr := remaining; portStr != "" {
-				if parsedPortInt, err := strconv.ParseInt(portStr, 10, 64); err == nil {
-					parsedPort = parsedPortInt
-				}
+			allowedNamePatterns = append(allowedNamePatterns, compiled)
+		}
+	}
+
+	var sessionSQL []string
+	if !data.SessionSQL.IsNull() {
+		resp.Diagnostics.Append(data.SessionSQL.ElementsAs(ctx, &sessionSQL, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		for i, statement := range sessionSQL {
+			if err := validateSessionStatement(statement); err != nil {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("session_sql").AtListIndex(i),
+					"Invalid session SQL",
+					err.Error(),
+				)
+				return
 			}
 		}
-	} else if strings.Contains(host, "/") {
-		// Host contains database but no port (format: hostname/database)
-		parts := strings.SplitN(host, "/", 2)
-		hostname = parts[0]
-		if dbName := parts[1]; dbName != "" {
-			parsedDatabase = dbName
+	}
+
+	correlationID := data.CorrelationID.ValueString()
+	if data.CorrelationID.IsNull() {
+		var err error
+		correlationID, err = newCorrelationID()
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to generate correlation ID",
+				err.Error(),
+			)
+			return
+		}
+	} else if err := validateCorrelationID(correlationID); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("correlation_id"),
+			"Invalid correlation ID",
+			err.Error(),
+		)
+		return
+	}
+
+	if !data.FooterSeparator.IsNull() {
+		if err := validateFooterSeparator(data.FooterSeparator.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("footer_separator"),
+				"Invalid footer separator",
+				err.Error(),
+			)
+			return
+		}
+	}
+
+	if !data.EnvironmentNaming.IsNull() {
+		if err := validateEnvironmentNaming(data.EnvironmentNaming.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("environment_naming"),
+				"Invalid environment_naming",
+				err.Error(),
+			)
+			return
+		}
+	}
+
+	ctx = tflog.SetField(ctx, "correlation_id", correlationID)
+
+	providerData := &ProviderData{
+		Version: p.version,
+
+		AllowedNamePatterns: allowedNamePatterns,
+		SessionSQL:          sessionSQL,
+		CorrelationID:       correlationID,
+		DefaultDescription:  data.DefaultDescription.ValueString(),
+		FooterSeparator:     data.FooterSeparator.ValueString(),
+		ShowFooterOnRead:    data.ShowFooterOnRead.ValueBool(),
+
+		SuppressStateWarnings: data.SuppressStateWarnings.ValueBool(),
+		QueryComments:         data.QueryComments.ValueBool(),
+		MaxValueBytes:         data.MaxValueBytes.ValueInt64(),
+		AllowEmptyValue:       data.AllowEmptyValue.ValueBool(),
+		TimestampLocation:     timestampLocation,
+
+		AcquireTimeout:        acquireTimeout,
+		AllowInvalidUTF8Names: data.AllowInvalidUTF8Names.ValueBool(),
+		EnvironmentNaming:     data.EnvironmentNaming.ValueString(),
+		ReadOnly:              data.ReadOnly.ValueBool(),
+		AutoReconnect:         data.AutoReconnect.ValueBool(),
+		ErrorOnMissing:        data.ErrorOnMissing.ValueBool(),
+		UseTransactions:       data.UseTransactions.ValueBool(),
+		StrictKeyIDRead:       data.StrictKeyIDRead.ValueBool(),
+		SkipPing:              data.SkipPing.ValueBool(),
+
+		queryExecModeSet: !data.QueryExecMode.IsNull(),
+		operations:       newOperationLimit(data.MaxConcurrentOperations.ValueInt64()),
+	}
+
+	// The Management API runs statements over HTTPS, so there is no
+	// connection to configure
+	if !data.ManagementToken.IsNull() {
+		if err := validateProjectRef(data.ProjectRef.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("project_ref"),
+				"Invalid project_ref",
+				err.Error(),
+			)
+			return
+		}
+
+		baseURL := defaultManagementAPIURL
+		if !data.ManagementAPIURL.IsNull() {
+			baseURL = data.ManagementAPIURL.ValueString()
+		}
+		providerData.managementAPI = newManagementAPIClient(baseURL, data.ManagementToken.ValueString(), data.ProjectRef.ValueString())
+
+		tflog.Info(ctx, "Using the Supabase Management API instead of a database connection", map[string]interface{}{
+			"project_ref": data.ProjectRef.ValueString(),
+		})
+
+		resp.DataSourceData = providerData
+		resp.ResourceData = providerData
+		resp.EphemeralResourceData = providerData
+		return
+	}
+
+	// An explicit endpoint is used verbatim; otherwise host is normalized and
+	// may carry the port and database
+	var hostPort string
+	var parsedPort int64
+	var parsedDatabase string
+
+	switch {
+	case !data.Endpoint.IsNull():
+		if !data.Host.IsNull() || !data.Port.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("endpoint"),
+				"Conflicting connection attributes",
+				"endpoint already includes the host and port, so host and port must not be set alongside it.",
+			)
+			return
+		}
+
+		endpointPort, err := validateEndpoint(data.Endpoint.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("endpoint"),
+				"Invalid endpoint",
+				err.Error(),
+			)
+			return
+		}
+
+		hostPort = data.Endpoint.ValueString()
+		parsedPort = endpointPort
+		parsedDatabase = database
+	case hasHost:
+		var hostname string
+		hostname, parsedPort, parsedDatabase = parseHost(host, port, database)
+		hostPort = fmt.Sprintf("%s:%d", hostname, parsedPort)
+		if parsedPort == 0 {
+			hostPort = hostname
+		}
+	case useService:
+		if port != 0 {
+			hostPort = fmt.Sprintf(":%d", port)
+		}
+		parsedDatabase = database
+	default:
+		resp.Diagnostics.AddError(
+			"Missing connection attribute",
+			"Either host or endpoint must be set.",
+		)
+		return
+	}
+
+	tflog.Debug(ctx, "Resolved connection settings", map[string]interface{}{
+		"host_source":     hostSource,
+		"port_source":     portSource,
+		"database_source": databaseSource,
+		"user_source":     userSource,
+	})
+
+	// The database may come from the combined host string, so validate the
+	// final name before it is interpolated into the connection string. An
+	// empty name leaves the database to the service.
+	if parsedDatabase != "" || !useService {
+		if err := validateDatabaseName(parsedDatabase); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("database"),
+				"Invalid database name",
+				err.Error(),
+			)
+			return
 		}
 	}
 
-	// Build connection string
+	// Build connection string, leaving the password out so pgx looks it up in
+	// the passfile when none is configured
+	userInfo := url.QueryEscape(user)
+	if !data.Password.IsNull() {
+		userInfo += ":" + url.QueryEscape(data.Password.ValueString())
+	}
 	connString := fmt.Sprintf(
-		"postgres://%s:%s@%s:%d/%s",
-		url.QueryEscape(user),
-		url.QueryEscape(data.Password.ValueString()),
-		hostname,
-		parsedPort,
+		"postgres://%s@%s/%s",
+		userInfo,
+		hostPort,
 		parsedDatabase,
 	)
 
+	// Free-form parameters go in first so explicit attributes take precedence
+	params := url.Values{}
+	if !data.ConnectionParams.IsNull() || !data.ConnectionQuery.IsNull() {
+		connectionParams := map[string]string{}
+		if !data.ConnectionParams.IsNull() {
+			resp.Diagnostics.Append(data.ConnectionParams.ElementsAs(ctx, &connectionParams, false)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+		}
+
+		var err error
+		if !data.ConnectionQuery.IsNull() {
+			connectionParams, err = mergeConnectionQuery(data.ConnectionQuery.ValueString(), connectionParams)
+			if err != nil {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("connection_query"),
+					"Invalid connection parameter",
+					err.Error(),
+				)
+				return
+			}
+		}
+
+		params, err = sanitizeConnectionParams(connectionParams)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("connection_params"),
+				"Invalid connection parameter",
+				err.Error(),
+			)
+			return
+		}
+	}
+
+	// The passfile is read by pgx, so only check it exists and is private
+	passfile := data.Passfile.ValueString()
+	if data.Passfile.IsNull() {
+		passfile = os.Getenv("PGPASSFILE")
+	}
+	if data.Password.IsNull() && passfile != "" {
+		info, err := statPassfile(passfile)
+		switch {
+		case err != nil && !data.Passfile.IsNull():
+			resp.Diagnostics.AddAttributeError(
+				path.Root("passfile"),
+				"Invalid passfile",
+				fmt.Sprintf("Unable to read the passfile: %s", err),
+			)
+			return
+		case err == nil:
+			if warning := passfileWarning(info); warning != "" {
+				resp.Diagnostics.AddAttributeWarning(path.Root("passfile"), "Passfile readable by other users", warning)
+			}
+		}
+	}
+	if !data.Passfile.IsNull() {
+		params.Set("passfile", data.Passfile.ValueString())
+	}
+	if useService {
+		params.Set("service", service)
+	}
+
 	// Only add sslmode if explicitly provided
 	if !data.SSLMode.IsNull() {
-		connString += fmt.Sprintf("?sslmode=%s", url.QueryEscape(data.SSLMode.ValueString()))
+		if err := validateSSLMode(data.SSLMode.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("sslmode"),
+				"Invalid sslmode",
+				err.Error(),
+			)
+			return
+		}
+		params.Set("sslmode", data.SSLMode.ValueString())
+	}
+
+	if !data.ChannelBinding.IsNull() {
+		if err := validateChannelBinding(data.ChannelBinding.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("channel_binding"),
+				"Invalid channel_binding",
+				err.Error(),
+			)
+			return
+		}
+		params.Set("channel_binding", data.ChannelBinding.ValueString())
+	}
+
+	if !data.SSLNegotiation.IsNull() {
+		if err := validateSSLNegotiation(data.SSLNegotiation.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("ssl_negotiation"),
+				"Invalid ssl_negotiation",
+				err.Error(),
+			)
+			return
+		}
+		params.Set("sslnegotiation", data.SSLNegotiation.ValueString())
+	}
+
+	if len(params) > 0 {
+		connString += "?" + params.Encode()
+	}
+
+	poolConfig, err := pgxpool.ParseConfig(connString)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid PostgreSQL connection configuration",
+			fmt.Sprintf("Unable to parse connection configuration: %s", err),
+		)
+		return
+	}
+
+	// Checked on the parsed configuration, as sslmode may also come from
+	// connection_params, PGSSLMODE or a service
+	if !data.SuppressTLSWarning.ValueBool() && mayConnectWithoutTLS(&poolConfig.ConnConfig.Config) {
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("sslmode"),
+			"Connection may be unencrypted",
+			fmt.Sprintf("The connection to %s may not use TLS, so secret values could cross the network in plaintext. "+
+				"Set sslmode = \"require\" (or verify-full) to always encrypt it, as Supabase supports. Set suppress_tls_warning to hide this warning.", poolConfig.ConnConfig.Host),
+		)
+	}
+
+	// Transaction-mode poolers (Supavisor listens on 6543) don't support
+	// prepared statements, so use the simple protocol from the start unless
+	// a mode was chosen explicitly
+	poolerMode := detectPoolerMode(poolConfig.ConnConfig.Host, poolConfig.ConnConfig.Port)
+	switch {
+	case !data.QueryExecMode.IsNull():
+		queryExecMode, err := parseQueryExecMode(data.QueryExecMode.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("query_exec_mode"),
+				"Invalid query execution mode",
+				err.Error(),
+			)
+			return
+		}
+		poolConfig.ConnConfig.DefaultQueryExecMode = queryExecMode
+	case poolerMode == poolerModeTransaction:
+		poolConfig.ConnConfig.DefaultQueryExecMode = pgx.QueryExecModeSimpleProtocol
+		tflog.Info(ctx, "Detected Supabase transaction-mode pooler, using the simple query protocol", map[string]interface{}{
+			"host": poolConfig.ConnConfig.Host,
+			"port": poolConfig.ConnConfig.Port,
+		})
+	case poolerMode == poolerModeSession:
+		tflog.Info(ctx, "Detected Supabase session-mode pooler, keeping prepared statements", map[string]interface{}{
+			"host": poolConfig.ConnConfig.Host,
+			"port": poolConfig.ConnConfig.Port,
+		})
+	}
+
+	// Probe idle connections so intermediaries don't silently drop them
+	if !data.TCPKeepalive.IsNull() || !data.TCPKeepaliveInterval.IsNull() {
+		var idle, interval time.Duration
+		if !data.TCPKeepalive.IsNull() {
+			if idle, err = parseKeepAliveDuration(data.TCPKeepalive.ValueString()); err != nil {
+				resp.Diagnostics.AddAttributeError(path.Root("tcp_keepalive"), "Invalid TCP keepalive duration", err.Error())
+				return
+			}
+		}
+		if !data.TCPKeepaliveInterval.IsNull() {
+			if interval, err = parseKeepAliveDuration(data.TCPKeepaliveInterval.ValueString()); err != nil {
+				resp.Diagnostics.AddAttributeError(path.Root("tcp_keepalive_interval"), "Invalid TCP keepalive duration", err.Error())
+				return
+			}
+		}
+
+		poolConfig.ConnConfig.DialFunc = keepAliveDialFunc(poolConfig.ConnConfig.ConnectTimeout, idle, interval)
+	}
+
+	// Route executed SQL through tflog when requested
+	if data.LogQueries.ValueBool() {
+		poolConfig.ConnConfig.Tracer = newQueryTracer()
+	}
+
+	// Report the correlation ID as the application name unless one is set
+	if _, ok := poolConfig.ConnConfig.RuntimeParams["application_name"]; !ok {
+		poolConfig.ConnConfig.RuntimeParams["application_name"] = correlationApplicationNamePrefix + correlationID
+	}
+
+	// Label every session, so cleanup can find the stale ones
+	if !data.SessionLabel.IsNull() {
+		if err := validateSessionLabel(data.SessionLabel.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("session_label"),
+				"Invalid session label",
+				err.Error(),
+			)
+			return
+		}
+
+		providerData.SessionLabel = data.SessionLabel.ValueString()
+		poolConfig.ConnConfig.RuntimeParams["application_name"] = providerData.SessionLabel
+	}
+
+	// Keep track of the pool's own sessions, so cleanup never terminates live
+	// ones. Every session is set up before it is tracked, since a connection
+	// failing its setup is closed without BeforeClose.
+	poolConfig.AfterConnect = chainAfterConnect(sessionSQLAfterConnect(sessionSQL), providerData.trackConnection)
+	poolConfig.BeforeClose = providerData.untrackConnection
+
+	// Spread out the connection attempts of runs started at the same time
+	if connectJitter > 0 {
+		delay := connectJitterDelay(connectJitter)
+		tflog.Debug(ctx, "Delaying the first connection by a random part of connect_jitter", map[string]interface{}{
+			"delay": delay.String(),
+		})
+
+		select {
+		case <-ctx.Done():
+			resp.Diagnostics.AddError(
+				"Unable to connect to PostgreSQL",
+				fmt.Sprintf("Canceled while waiting out connect_jitter: %s", ctx.Err()),
+			)
+			return
+		case <-time.After(delay):
+		}
 	}
 
 	// Create connection pool (needed for concurrent Terraform operations)
 	connectCtx, connectCancel := context.WithTimeout(ctx, 10*time.Second)
 	defer connectCancel()
 
-	pool, err := pgxpool.New(connectCtx, connString)
+	pool, err := pgxpool.NewWithConfig(connectCtx, poolConfig)
 	if err != nil {
 		if connectCtx.Err() == context.DeadlineExceeded {
 			resp.Diagnostics.AddError(
 				"Unable to connect to PostgreSQL",
-				"Connection timeout: unable to create connection pool within 10 seconds. Please check your connection settings and network connectivity.",
+				"Connection timeout: unable to create connection pool within 10 seconds.\n\n"+hintConnection,
 			)
 		} else {
 			resp.Diagnostics.AddError(
 				"Unable to connect to PostgreSQL",
-				fmt.Sprintf("Unable to create connection pool: %s", err),
+				withRemediation(fmt.Sprintf("Unable to create connection pool: %s", err), err),
 			)
 		}
 		return
@@ -203,55 +1426,162 @@ func (p *SupabaseVaultProvider) Configure(ctx context.Context, req provider.Conf
 	pingCtx, pingCancel := context.WithTimeout(ctx, 10*time.Second)
 	defer pingCancel()
 
-	if err := pool.Ping(pingCtx); err != nil {
+	if providerData.SkipPing {
+		tflog.Warn(ctx, "Skipping the connection ping because skip_ping is set; connection problems will surface on the first operation")
+	} else if err := pool.Ping(pingCtx); err != nil {
 		pool.Close()
 		if pingCtx.Err() == context.DeadlineExceeded {
 			resp.Diagnostics.AddError(
 				"Unable to connect to PostgreSQL",
-				"Connection timeout: unable to ping database within 10 seconds. Please check your connection settings and network connectivity.",
+				"Connection timeout: unable to ping database within 10 seconds.\n\n"+hintConnection,
 			)
 		} else {
 			resp.Diagnostics.AddError(
 				"Unable to connect to PostgreSQL",
-				fmt.Sprintf("Unable to ping database: %s", err),
+				withRemediation(fmt.Sprintf("Unable to ping database: %s", err), err),
 			)
 		}
 		return
+	} else {
+		tflog.Info(ctx, "Successfully connected to PostgreSQL database")
 	}
 
-	tflog.Info(ctx, "Successfully connected to PostgreSQL database")
+	if data.AutoCreateExtensions.ValueBool() && data.ReadOnly.ValueBool() {
+		tflog.Warn(ctx, "Skipping auto_create_extensions because the provider is read-only")
+	} else if data.AutoCreateExtensions.ValueBool() {
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("auto_create_extensions"),
+			"Creating Vault extensions automatically",
+			"auto_create_extensions is enabled, so the provider will run CREATE EXTENSION for supabase_vault and pgsodium. "+
+				"This requires superuser-level privileges and is intended for local development and test databases only.",
+		)
 
-	// Store provider data
-	providerData := &ProviderData{
-		Pool:    pool,
-		Version: p.version,
+		if err := createVaultExtensions(ctx, pool); err != nil {
+			pool.Close()
+			resp.Diagnostics.AddError(
+				"Unable to create Vault extensions",
+				withRemediation(fmt.Sprintf("Error creating extensions: %s", err), err),
+			)
+			return
+		}
+
+		tflog.Info(ctx, "Ensured supabase_vault extension is installed")
+	}
+
+	if data.CheckPrivileges.ValueBool() {
+		if err := checkPrivileges(ctx, pool, data.ReadOnly.ValueBool()); err != nil {
+			pool.Close()
+			resp.Diagnostics.AddAttributeError(
+				path.Root("check_privileges"),
+				"Missing database privileges",
+				withRemediation(err.Error(), err),
+			)
+			return
+		}
+
+		tflog.Info(ctx, "Verified the connecting role's privileges")
 	}
 
+	// Store provider data
+	providerData.Pool = pool
+	providerData.poolConfig = poolConfig
+
 	resp.DataSourceData = providerData
 	resp.ResourceData = providerData
+	resp.EphemeralResourceData = providerData
+}
+
+// createVaultExtensions installs the extensions Vault depends on if missing.
+// pgsodium is only installed when the server offers it, as newer Vault
+// releases no longer depend on it.
+func createVaultExtensions(ctx context.Context, pool *pgxpool.Pool) error {
+	var pgsodiumAvailable bool
+	err := pool.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM pg_available_extensions WHERE name = 'pgsodium')").Scan(&pgsodiumAvailable)
+	if err != nil {
+		return fmt.Errorf("checking for pgsodium: %w", err)
+	}
+
+	if pgsodiumAvailable {
+		if _, err := pool.Exec(ctx, "CREATE EXTENSION IF NOT EXISTS pgsodium"); err != nil {
+			return fmt.Errorf("creating pgsodium extension: %w", err)
+		}
+	}
+
+	if _, err := pool.Exec(ctx, "CREATE EXTENSION IF NOT EXISTS supabase_vault CASCADE"); err != nil {
+		return fmt.Errorf("creating supabase_vault extension: %w", err)
+	}
+
+	return nil
+}
+
+// checkWritable adds an error to diags if the provider is read-only, in which
+// case the caller must not go on to mutate anything.
+func (d *ProviderData) checkWritable(diags *diag.Diagnostics, operation string) bool {
+	if !d.ReadOnly {
+		return true
+	}
+
+	diags.AddError(
+		"Provider is read-only",
+		fmt.Sprintf("Refusing to %s: the supabase-vault provider is configured with read_only = true.", operation),
+	)
+
+	return false
+}
+
+// allowMissing adds an error to diags if the provider is configured with
+// error_on_missing, in which case the caller must leave the missing secret in
+// state rather than removing it.
+func (d *ProviderData) allowMissing(diags *diag.Diagnostics, secret string) bool {
+	if !d.ErrorOnMissing {
+		return true
+	}
+
+	diags.AddError(
+		"Managed secret no longer exists",
+		fmt.Sprintf("The %s can't be found, so it was deleted outside Terraform. The provider is configured with error_on_missing = true, so it is kept in state instead of being recreated. "+
+			"Restore it, or remove the resource from state with terraform state rm to recreate it.", secret),
+	)
+
+	return false
 }
 
 func (p *SupabaseVaultProvider) Resources(ctx context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewVaultSecretResource,
+		NewVaultKeyRotationResource,
+		NewSessionCleanupResource,
+		NewVaultBulkSecretsResource,
+		NewVaultSecretExportResource,
+		NewVaultSecretMetadataResource,
+		NewVaultConfigResource,
 	}
 }
 
 func (p *SupabaseVaultProvider) EphemeralResources(ctx context.Context) []func() ephemeral.EphemeralResource {
 	return []func() ephemeral.EphemeralResource{
-		// No ephemeral resources for MVP
+		NewVaultSecretEphemeralResource,
 	}
 }
 
 func (p *SupabaseVaultProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
-		// No data sources for MVP
+		NewVaultKeyDataSource,
+		NewVaultSecretValueDataSource,
+		NewVaultSecretExistsDataSource,
+		NewVaultSecretsDataSource,
+		NewVaultImportableSecretsDataSource,
+		NewSessionsDataSource,
+		NewProviderConfigDataSource,
 	}
 }
 
 func (p *SupabaseVaultProvider) Functions(ctx context.Context) []func() function.Function {
 	return []func() function.Function{
-		// No functions for MVP
+		NewEncodeSecretFunction,
+		NewDecodeSecretFunction,
+		NewSecretHashFunction,
+		NewSecretNameFunction,
 	}
 }
 
@@ -0,0 +1,198 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/jackc/pgx/v5"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &ProviderConfigDataSource{}
+
+func NewProviderConfigDataSource() datasource.DataSource {
+	return &ProviderConfigDataSource{}
+}
+
+// ProviderConfigDataSource defines the data source implementation.
+type ProviderConfigDataSource struct {
+	providerData *ProviderData
+}
+
+// ProviderConfigDataSourceModel describes the data source data model.
+type ProviderConfigDataSourceModel struct {
+	Host             types.String `tfsdk:"host"`
+	Port             types.Int64  `tfsdk:"port"`
+	Database         types.String `tfsdk:"database"`
+	User             types.String `tfsdk:"user"`
+	PasswordSet      types.Bool   `tfsdk:"password_set"`
+	ConnectionString types.String `tfsdk:"connection_string"`
+	ApplicationName  types.String `tfsdk:"application_name"`
+	QueryExecMode    types.String `tfsdk:"query_exec_mode"`
+	PoolerMode       types.String `tfsdk:"pooler_mode"`
+	ReadOnly         types.Bool   `tfsdk:"read_only"`
+	CorrelationID    types.String `tfsdk:"correlation_id"`
+}
+
+func (d *ProviderConfigDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_provider_config"
+}
+
+func (d *ProviderConfigDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Shows the connection settings the provider resolved after applying defaults, environment variables and parsing of `host` or `endpoint`, " +
+			"to confirm which database it connects to. The password is never included. Nothing is read from the database.",
+
+		Attributes: map[string]schema.Attribute{
+			"host": schema.StringAttribute{
+				MarkdownDescription: "Host connected to",
+				Computed:            true,
+			},
+			"port": schema.Int64Attribute{
+				MarkdownDescription: "Port connected to",
+				Computed:            true,
+			},
+			"database": schema.StringAttribute{
+				MarkdownDescription: "Database connected to",
+				Computed:            true,
+			},
+			"user": schema.StringAttribute{
+				MarkdownDescription: "User connected as",
+				Computed:            true,
+			},
+			"password_set": schema.BoolAttribute{
+				MarkdownDescription: "Whether a password is configured",
+				Computed:            true,
+			},
+			"connection_string": schema.StringAttribute{
+				MarkdownDescription: "Connection string the pool was created from, with the password replaced by `xxxxx`",
+				Computed:            true,
+			},
+			"application_name": schema.StringAttribute{
+				MarkdownDescription: "`application_name` the provider's sessions report, null when none is set",
+				Computed:            true,
+			},
+			"query_exec_mode": schema.StringAttribute{
+				MarkdownDescription: "How queries are executed, one of the `query_exec_mode` values. Reports `simple` once the provider has switched to the simple protocol after a prepared-statement error.",
+				Computed:            true,
+			},
+			"pooler_mode": schema.StringAttribute{
+				MarkdownDescription: "Supabase pooling mode detected from the host and port, `transaction` or `session`, null when not connecting through a pooler",
+				Computed:            true,
+			},
+			"read_only": schema.BoolAttribute{
+				MarkdownDescription: "Whether the provider refuses to create, update or delete secrets",
+				Computed:            true,
+			},
+			"correlation_id": schema.StringAttribute{
+				MarkdownDescription: "Correlation ID of this run",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *ProviderConfigDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.providerData = providerData
+}
+
+// queryExecModeName returns the query_exec_mode value for mode.
+func queryExecModeName(mode pgx.QueryExecMode) string {
+	switch mode {
+	case pgx.QueryExecModeCacheDescribe:
+		return "cache_describe"
+	case pgx.QueryExecModeDescribeExec:
+		return "describe_exec"
+	case pgx.QueryExecModeExec:
+		return "exec"
+	case pgx.QueryExecModeSimpleProtocol:
+		return "simple"
+	}
+
+	return "cache_statement"
+}
+
+// redactConnectionString replaces the password in a URL connection string.
+func redactConnectionString(connString string) string {
+	u, err := url.Parse(connString)
+	if err != nil {
+		// Never risk returning an unparsed string that may hold the password
+		return ""
+	}
+
+	return u.Redacted()
+}
+
+// effectiveConfig describes the connection settings d was configured with.
+func (d *ProviderData) effectiveConfig() (ProviderConfigDataSourceModel, error) {
+	if d.poolConfig == nil {
+		return ProviderConfigDataSourceModel{}, fmt.Errorf("the provider has no connection configuration")
+	}
+
+	connConfig := d.poolConfig.ConnConfig
+
+	queryExecMode := queryExecModeName(connConfig.DefaultQueryExecMode)
+	if d.simpleProtocol.Load() {
+		queryExecMode = "simple"
+	}
+
+	model := ProviderConfigDataSourceModel{
+		Host:             types.StringValue(connConfig.Host),
+		Port:             types.Int64Value(int64(connConfig.Port)),
+		Database:         types.StringValue(connConfig.Database),
+		User:             types.StringValue(connConfig.User),
+		PasswordSet:      types.BoolValue(connConfig.Password != ""),
+		ConnectionString: types.StringValue(redactConnectionString(d.poolConfig.ConnString())),
+		ApplicationName:  types.StringNull(),
+		QueryExecMode:    types.StringValue(queryExecMode),
+		PoolerMode:       types.StringNull(),
+		ReadOnly:         types.BoolValue(d.ReadOnly),
+		CorrelationID:    types.StringValue(d.CorrelationID),
+	}
+
+	if applicationName, ok := connConfig.RuntimeParams["application_name"]; ok {
+		model.ApplicationName = types.StringValue(applicationName)
+	}
+	if poolerMode := detectPoolerMode(connConfig.Host, connConfig.Port); poolerMode != "" {
+		model.PoolerMode = types.StringValue(poolerMode)
+	}
+
+	return model, nil
+}
+
+func (d *ProviderConfigDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	data, err := d.providerData.effectiveConfig()
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to read provider configuration",
+			err.Error(),
+		)
+		return
+	}
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
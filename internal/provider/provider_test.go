@@ -4,10 +4,18 @@
 package provider
 
 import (
+	"context"
+	"fmt"
+	"os"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-testing/echoprovider"
 )
 
@@ -22,8 +30,6 @@ var testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServe
 // It allows for testing assertions on data returned by an ephemeral resource during Open.
 // The echoprovider is used to arrange tests by echoing ephemeral data into the Terraform state.
 // This lets the data be referenced in test assertions with state checks.
-//
-//nolint:unused // Reserved for future tests that need the echo provider.
 var testAccProtoV6ProviderFactoriesWithEcho = map[string]func() (tfprotov6.ProviderServer, error){
 	"supabase-vault": providerserver.NewProtocol6WithError(New("test")()),
 	"echo":           echoprovider.NewProviderServer(),
@@ -34,3 +40,520 @@ func testAccPreCheck(t *testing.T) {
 	// about the appropriate environment variables being set are common to see in a pre-check
 	// function.
 }
+
+// testAccProviderConfig returns a provider block configured from the SUPABASE_* environment variables.
+func testAccProviderConfig() string {
+	return testAccProviderConfigForRole(os.Getenv("SUPABASE_USER"), os.Getenv("SUPABASE_PASSWORD"))
+}
+
+// testAccProviderConfigForRole returns a provider block configured from the
+// SUPABASE_* environment variables that connects as user instead.
+func testAccProviderConfigForRole(user, password string) string {
+	config := fmt.Sprintf(`
+provider "supabase-vault" {
+  host     = %q
+  password = %q
+`, os.Getenv("SUPABASE_HOST"), password)
+
+	if port := os.Getenv("SUPABASE_PORT"); port != "" {
+		config += fmt.Sprintf(`  port     = %s
+`, port)
+	}
+	if database := os.Getenv("SUPABASE_DATABASE"); database != "" {
+		config += fmt.Sprintf(`  database = %q
+`, database)
+	}
+	if user != "" {
+		config += fmt.Sprintf(`  user     = %q
+`, user)
+	}
+	if sslmode := os.Getenv("SUPABASE_SSLMODE"); sslmode != "" {
+		config += fmt.Sprintf(`  sslmode  = %q
+`, sslmode)
+	}
+
+	return config + "}\n"
+}
+
+func TestProviderValidateConfig(t *testing.T) {
+	testCases := map[string]struct {
+		config    map[string]tftypes.Value
+		env       map[string]string
+		expectErr bool
+	}{
+		"host": {
+			config: map[string]tftypes.Value{
+				"host":     tftypes.NewValue(tftypes.String, "db.example.supabase.co"),
+				"password": tftypes.NewValue(tftypes.String, "secret"),
+				"sslmode":  tftypes.NewValue(tftypes.String, "require"),
+			},
+		},
+		"endpoint": {
+			config: map[string]tftypes.Value{
+				"endpoint": tftypes.NewValue(tftypes.String, "replica.example.supabase.co:5432"),
+				"password": tftypes.NewValue(tftypes.String, "secret"),
+			},
+		},
+		"unknown host": {
+			config: map[string]tftypes.Value{
+				"host":     tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+				"password": tftypes.NewValue(tftypes.String, "secret"),
+			},
+		},
+		"host and endpoint": {
+			config: map[string]tftypes.Value{
+				"host":     tftypes.NewValue(tftypes.String, "db.example.supabase.co"),
+				"endpoint": tftypes.NewValue(tftypes.String, "replica.example.supabase.co:5432"),
+				"password": tftypes.NewValue(tftypes.String, "secret"),
+			},
+			expectErr: true,
+		},
+		"endpoint and port": {
+			config: map[string]tftypes.Value{
+				"endpoint": tftypes.NewValue(tftypes.String, "replica.example.supabase.co:5432"),
+				"port":     tftypes.NewValue(tftypes.Number, 6543),
+				"password": tftypes.NewValue(tftypes.String, "secret"),
+			},
+			expectErr: true,
+		},
+		"neither host nor endpoint": {
+			config: map[string]tftypes.Value{
+				"password": tftypes.NewValue(tftypes.String, "secret"),
+			},
+			expectErr: true,
+		},
+		"empty host": {
+			config: map[string]tftypes.Value{
+				"host":     tftypes.NewValue(tftypes.String, " "),
+				"password": tftypes.NewValue(tftypes.String, "secret"),
+			},
+			expectErr: true,
+		},
+		"invalid sslmode": {
+			config: map[string]tftypes.Value{
+				"host":     tftypes.NewValue(tftypes.String, "db.example.supabase.co"),
+				"password": tftypes.NewValue(tftypes.String, "secret"),
+				"sslmode":  tftypes.NewValue(tftypes.String, "required"),
+			},
+			expectErr: true,
+		},
+		"channel binding": {
+			config: map[string]tftypes.Value{
+				"host":            tftypes.NewValue(tftypes.String, "db.example.supabase.co"),
+				"password":        tftypes.NewValue(tftypes.String, "secret"),
+				"sslmode":         tftypes.NewValue(tftypes.String, "verify-full"),
+				"channel_binding": tftypes.NewValue(tftypes.String, "require"),
+			},
+		},
+		"invalid channel binding": {
+			config: map[string]tftypes.Value{
+				"host":            tftypes.NewValue(tftypes.String, "db.example.supabase.co"),
+				"password":        tftypes.NewValue(tftypes.String, "secret"),
+				"channel_binding": tftypes.NewValue(tftypes.String, "required"),
+			},
+			expectErr: true,
+		},
+		"channel binding without TLS": {
+			config: map[string]tftypes.Value{
+				"host":            tftypes.NewValue(tftypes.String, "db.example.supabase.co"),
+				"password":        tftypes.NewValue(tftypes.String, "secret"),
+				"sslmode":         tftypes.NewValue(tftypes.String, "disable"),
+				"channel_binding": tftypes.NewValue(tftypes.String, "require"),
+			},
+			expectErr: true,
+		},
+		"timestamp timezone": {
+			config: map[string]tftypes.Value{
+				"host":               tftypes.NewValue(tftypes.String, "db.example.supabase.co"),
+				"password":           tftypes.NewValue(tftypes.String, "secret"),
+				"timestamp_timezone": tftypes.NewValue(tftypes.String, "Europe/Berlin"),
+			},
+		},
+		"invalid timestamp timezone": {
+			config: map[string]tftypes.Value{
+				"host":               tftypes.NewValue(tftypes.String, "db.example.supabase.co"),
+				"password":           tftypes.NewValue(tftypes.String, "secret"),
+				"timestamp_timezone": tftypes.NewValue(tftypes.String, "Mars/Olympus_Mons"),
+			},
+			expectErr: true,
+		},
+		"direct ssl negotiation": {
+			config: map[string]tftypes.Value{
+				"host":            tftypes.NewValue(tftypes.String, "db.example.supabase.co"),
+				"password":        tftypes.NewValue(tftypes.String, "secret"),
+				"ssl_negotiation": tftypes.NewValue(tftypes.String, "direct"),
+			},
+		},
+		"invalid ssl negotiation": {
+			config: map[string]tftypes.Value{
+				"host":            tftypes.NewValue(tftypes.String, "db.example.supabase.co"),
+				"password":        tftypes.NewValue(tftypes.String, "secret"),
+				"ssl_negotiation": tftypes.NewValue(tftypes.String, "tls"),
+			},
+			expectErr: true,
+		},
+		"direct ssl negotiation without TLS": {
+			config: map[string]tftypes.Value{
+				"host":            tftypes.NewValue(tftypes.String, "db.example.supabase.co"),
+				"password":        tftypes.NewValue(tftypes.String, "secret"),
+				"sslmode":         tftypes.NewValue(tftypes.String, "disable"),
+				"ssl_negotiation": tftypes.NewValue(tftypes.String, "direct"),
+			},
+			expectErr: true,
+		},
+		"invalid port": {
+			config: map[string]tftypes.Value{
+				"host":     tftypes.NewValue(tftypes.String, "db.example.supabase.co"),
+				"port":     tftypes.NewValue(tftypes.Number, 70000),
+				"password": tftypes.NewValue(tftypes.String, "secret"),
+			},
+			expectErr: true,
+		},
+		"invalid pool acquire timeout": {
+			config: map[string]tftypes.Value{
+				"host":                 tftypes.NewValue(tftypes.String, "db.example.supabase.co"),
+				"password":             tftypes.NewValue(tftypes.String, "secret"),
+				"pool_acquire_timeout": tftypes.NewValue(tftypes.String, "soon"),
+			},
+			expectErr: true,
+		},
+		"connect jitter": {
+			config: map[string]tftypes.Value{
+				"host":           tftypes.NewValue(tftypes.String, "db.example.supabase.co"),
+				"password":       tftypes.NewValue(tftypes.String, "secret"),
+				"connect_jitter": tftypes.NewValue(tftypes.String, "5s"),
+			},
+		},
+		"negative connect jitter": {
+			config: map[string]tftypes.Value{
+				"host":           tftypes.NewValue(tftypes.String, "db.example.supabase.co"),
+				"password":       tftypes.NewValue(tftypes.String, "secret"),
+				"connect_jitter": tftypes.NewValue(tftypes.String, "-5s"),
+			},
+			expectErr: true,
+		},
+		"session sql": {
+			config: map[string]tftypes.Value{
+				"host":     tftypes.NewValue(tftypes.String, "db.example.supabase.co"),
+				"password": tftypes.NewValue(tftypes.String, "secret"),
+				"session_sql": tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{
+					tftypes.NewValue(tftypes.String, "SET statement_timeout = '30s'"),
+					tftypes.NewValue(tftypes.String, "SET search_path = vault, public"),
+				}),
+			},
+		},
+		"destructive session sql": {
+			config: map[string]tftypes.Value{
+				"host":     tftypes.NewValue(tftypes.String, "db.example.supabase.co"),
+				"password": tftypes.NewValue(tftypes.String, "secret"),
+				"session_sql": tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{
+					tftypes.NewValue(tftypes.String, "DROP TABLE vault.secrets"),
+				}),
+			},
+			expectErr: true,
+		},
+		"correlation id in comment": {
+			config: map[string]tftypes.Value{
+				"host":           tftypes.NewValue(tftypes.String, "db.example.supabase.co"),
+				"password":       tftypes.NewValue(tftypes.String, "secret"),
+				"correlation_id": tftypes.NewValue(tftypes.String, "run */ DROP TABLE vault.secrets; /*"),
+			},
+			expectErr: true,
+		},
+		"tcp keepalive": {
+			config: map[string]tftypes.Value{
+				"host":                   tftypes.NewValue(tftypes.String, "db.example.supabase.co"),
+				"password":               tftypes.NewValue(tftypes.String, "secret"),
+				"tcp_keepalive":          tftypes.NewValue(tftypes.String, "60s"),
+				"tcp_keepalive_interval": tftypes.NewValue(tftypes.String, "15s"),
+			},
+		},
+		"invalid query exec mode": {
+			config: map[string]tftypes.Value{
+				"host":            tftypes.NewValue(tftypes.String, "db.example.supabase.co"),
+				"password":        tftypes.NewValue(tftypes.String, "secret"),
+				"query_exec_mode": tftypes.NewValue(tftypes.String, "prepared"),
+			},
+			expectErr: true,
+		},
+		"invalid tcp keepalive": {
+			config: map[string]tftypes.Value{
+				"host":          tftypes.NewValue(tftypes.String, "db.example.supabase.co"),
+				"password":      tftypes.NewValue(tftypes.String, "secret"),
+				"tcp_keepalive": tftypes.NewValue(tftypes.String, "0s"),
+			},
+			expectErr: true,
+		},
+		"invalid tcp keepalive interval": {
+			config: map[string]tftypes.Value{
+				"host":                   tftypes.NewValue(tftypes.String, "db.example.supabase.co"),
+				"password":               tftypes.NewValue(tftypes.String, "secret"),
+				"tcp_keepalive_interval": tftypes.NewValue(tftypes.String, "often"),
+			},
+			expectErr: true,
+		},
+		"default description with footer": {
+			config: map[string]tftypes.Value{
+				"host":                tftypes.NewValue(tftypes.String, "db.example.supabase.co"),
+				"password":            tftypes.NewValue(tftypes.String, "secret"),
+				"default_description": tftypes.NewValue(tftypes.String, appendManagedByFooter("Provisioned via platform IaC", defaultFooterSeparator, "1.0.0")),
+			},
+			expectErr: true,
+		},
+		"footer separator": {
+			config: map[string]tftypes.Value{
+				"host":             tftypes.NewValue(tftypes.String, "db.example.supabase.co"),
+				"password":         tftypes.NewValue(tftypes.String, "secret"),
+				"footer_separator": tftypes.NewValue(tftypes.String, "\n\n<!-- terraform -->\n"),
+			},
+		},
+		"blank footer separator": {
+			config: map[string]tftypes.Value{
+				"host":             tftypes.NewValue(tftypes.String, "db.example.supabase.co"),
+				"password":         tftypes.NewValue(tftypes.String, "secret"),
+				"footer_separator": tftypes.NewValue(tftypes.String, "\n\n"),
+			},
+			expectErr: true,
+		},
+		"max value bytes": {
+			config: map[string]tftypes.Value{
+				"host":            tftypes.NewValue(tftypes.String, "db.example.supabase.co"),
+				"password":        tftypes.NewValue(tftypes.String, "secret"),
+				"max_value_bytes": tftypes.NewValue(tftypes.Number, 65536),
+			},
+		},
+		"invalid max value bytes": {
+			config: map[string]tftypes.Value{
+				"host":            tftypes.NewValue(tftypes.String, "db.example.supabase.co"),
+				"password":        tftypes.NewValue(tftypes.String, "secret"),
+				"max_value_bytes": tftypes.NewValue(tftypes.Number, 0),
+			},
+			expectErr: true,
+		},
+		"passfile": {
+			config: map[string]tftypes.Value{
+				"host":     tftypes.NewValue(tftypes.String, "db.example.supabase.co"),
+				"passfile": tftypes.NewValue(tftypes.String, "/home/terraform/.pgpass"),
+			},
+		},
+		"password and passfile": {
+			config: map[string]tftypes.Value{
+				"host":     tftypes.NewValue(tftypes.String, "db.example.supabase.co"),
+				"password": tftypes.NewValue(tftypes.String, "secret"),
+				"passfile": tftypes.NewValue(tftypes.String, "/home/terraform/.pgpass"),
+			},
+			expectErr: true,
+		},
+		"service": {
+			config: map[string]tftypes.Value{
+				"service": tftypes.NewValue(tftypes.String, "supabase"),
+			},
+		},
+		"no connection": {
+			config: map[string]tftypes.Value{
+				"password": tftypes.NewValue(tftypes.String, "secret"),
+			},
+			expectErr: true,
+		},
+		"PGHOST": {
+			config: map[string]tftypes.Value{
+				"password": tftypes.NewValue(tftypes.String, "secret"),
+			},
+			env: map[string]string{"PGHOST": "db.example.supabase.co"},
+		},
+		"service with overrides": {
+			config: map[string]tftypes.Value{
+				"service":  tftypes.NewValue(tftypes.String, "supabase"),
+				"host":     tftypes.NewValue(tftypes.String, "db.example.supabase.co"),
+				"user":     tftypes.NewValue(tftypes.String, "vault_admin"),
+				"password": tftypes.NewValue(tftypes.String, "secret"),
+			},
+		},
+		"empty service": {
+			config: map[string]tftypes.Value{
+				"service": tftypes.NewValue(tftypes.String, " "),
+			},
+			expectErr: true,
+		},
+		"service with management token": {
+			config: map[string]tftypes.Value{
+				"service":          tftypes.NewValue(tftypes.String, "supabase"),
+				"management_token": tftypes.NewValue(tftypes.String, "sbp_test"),
+				"project_ref":      tftypes.NewValue(tftypes.String, "abcdefghijklmnopqrst"),
+			},
+			expectErr: true,
+		},
+		"environment naming": {
+			config: map[string]tftypes.Value{
+				"host":               tftypes.NewValue(tftypes.String, "db.example.supabase.co"),
+				"environment_naming": tftypes.NewValue(tftypes.String, "label"),
+			},
+		},
+		"invalid environment naming": {
+			config: map[string]tftypes.Value{
+				"host":               tftypes.NewValue(tftypes.String, "db.example.supabase.co"),
+				"environment_naming": tftypes.NewValue(tftypes.String, "suffix"),
+			},
+			expectErr: true,
+		},
+		"max concurrent operations": {
+			config: map[string]tftypes.Value{
+				"host":                      tftypes.NewValue(tftypes.String, "db.example.supabase.co"),
+				"password":                  tftypes.NewValue(tftypes.String, "secret"),
+				"max_concurrent_operations": tftypes.NewValue(tftypes.Number, 4),
+			},
+		},
+		"invalid max concurrent operations": {
+			config: map[string]tftypes.Value{
+				"host":                      tftypes.NewValue(tftypes.String, "db.example.supabase.co"),
+				"password":                  tftypes.NewValue(tftypes.String, "secret"),
+				"max_concurrent_operations": tftypes.NewValue(tftypes.Number, 0),
+			},
+			expectErr: true,
+		},
+		"management token": {
+			config: map[string]tftypes.Value{
+				"management_token": tftypes.NewValue(tftypes.String, "sbp_test"),
+				"project_ref":      tftypes.NewValue(tftypes.String, "abcdefghijklmnopqrst"),
+			},
+		},
+		"management token with host": {
+			config: map[string]tftypes.Value{
+				"host":             tftypes.NewValue(tftypes.String, "db.example.supabase.co"),
+				"management_token": tftypes.NewValue(tftypes.String, "sbp_test"),
+				"project_ref":      tftypes.NewValue(tftypes.String, "abcdefghijklmnopqrst"),
+			},
+			expectErr: true,
+		},
+		"management token without project ref": {
+			config: map[string]tftypes.Value{
+				"management_token": tftypes.NewValue(tftypes.String, "sbp_test"),
+			},
+			expectErr: true,
+		},
+		"invalid project ref": {
+			config: map[string]tftypes.Value{
+				"management_token": tftypes.NewValue(tftypes.String, "sbp_test"),
+				"project_ref":      tftypes.NewValue(tftypes.String, "https://abcdefghijklmnopqrst.supabase.co"),
+			},
+			expectErr: true,
+		},
+		"invalid name pattern": {
+			config: map[string]tftypes.Value{
+				"host":     tftypes.NewValue(tftypes.String, "db.example.supabase.co"),
+				"password": tftypes.NewValue(tftypes.String, "secret"),
+				"allowed_name_patterns": tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{
+					tftypes.NewValue(tftypes.String, "^[A-Z"),
+				}),
+			},
+			expectErr: true,
+		},
+		"reserved connection param": {
+			config: map[string]tftypes.Value{
+				"host":     tftypes.NewValue(tftypes.String, "db.example.supabase.co"),
+				"password": tftypes.NewValue(tftypes.String, "secret"),
+				"connection_params": tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, map[string]tftypes.Value{
+					"user": tftypes.NewValue(tftypes.String, "admin"),
+				}),
+			},
+			expectErr: true,
+		},
+		"connection query": {
+			config: map[string]tftypes.Value{
+				"host":             tftypes.NewValue(tftypes.String, "db.example.supabase.co"),
+				"password":         tftypes.NewValue(tftypes.String, "secret"),
+				"connection_query": tftypes.NewValue(tftypes.String, "connect_timeout=5&target_session_attrs=read-write"),
+			},
+		},
+		"reserved connection query param": {
+			config: map[string]tftypes.Value{
+				"host":             tftypes.NewValue(tftypes.String, "db.example.supabase.co"),
+				"password":         tftypes.NewValue(tftypes.String, "secret"),
+				"connection_query": tftypes.NewValue(tftypes.String, "password=override"),
+			},
+			expectErr: true,
+		},
+		"connection query param also in connection_params": {
+			config: map[string]tftypes.Value{
+				"host":             tftypes.NewValue(tftypes.String, "db.example.supabase.co"),
+				"password":         tftypes.NewValue(tftypes.String, "secret"),
+				"connection_query": tftypes.NewValue(tftypes.String, "connect_timeout=5"),
+				"connection_params": tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, map[string]tftypes.Value{
+					"connect_timeout": tftypes.NewValue(tftypes.String, "10"),
+				}),
+			},
+			expectErr: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("PGHOST", "")
+			t.Setenv("PGSERVICE", "")
+			for key, value := range testCase.env {
+				t.Setenv(key, value)
+			}
+
+			ctx := context.Background()
+			p := New("test")().(*SupabaseVaultProvider)
+
+			req := provider.ValidateConfigRequest{Config: testProviderConfig(t, p, testCase.config)}
+			resp := &provider.ValidateConfigResponse{}
+			p.ValidateConfig(ctx, req, resp)
+
+			if testCase.expectErr && !resp.Diagnostics.HasError() {
+				t.Fatal("expected an error, got none")
+			}
+			if !testCase.expectErr && resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected error: %v", resp.Diagnostics)
+			}
+		})
+	}
+}
+
+// testProviderConfig builds a provider configuration from values, leaving
+// every attribute not in values null.
+func testProviderConfig(t *testing.T, p *SupabaseVaultProvider, values map[string]tftypes.Value) tfsdk.Config {
+	t.Helper()
+
+	schemaResp := &provider.SchemaResponse{}
+	p.Schema(context.Background(), provider.SchemaRequest{}, schemaResp)
+
+	return tfsdk.Config{
+		Schema: schemaResp.Schema,
+		Raw:    testConfigValue(t, schemaResp.Schema.Type(), values),
+	}
+}
+
+// testConfigValue builds an object of schemaType from values, leaving every
+// attribute not in values null.
+func testConfigValue(t *testing.T, schemaType attr.Type, values map[string]tftypes.Value) tftypes.Value {
+	t.Helper()
+
+	objectType, ok := schemaType.TerraformType(context.Background()).(tftypes.Object)
+	if !ok {
+		t.Fatal("expected the schema to be an object")
+	}
+
+	attributes := make(map[string]tftypes.Value, len(objectType.AttributeTypes))
+	for name, attributeType := range objectType.AttributeTypes {
+		attributes[name] = tftypes.NewValue(attributeType, nil)
+		if value, ok := values[name]; ok {
+			attributes[name] = value
+		}
+	}
+
+	return tftypes.NewValue(objectType, attributes)
+}
+
+func TestAllowMissing(t *testing.T) {
+	var diags diag.Diagnostics
+
+	if !(&ProviderData{}).allowMissing(&diags, `secret "db-password"`) || diags.HasError() {
+		t.Fatalf("expected a missing secret to be removed by default, got: %v", diags)
+	}
+
+	if (&ProviderData{ErrorOnMissing: true}).allowMissing(&diags, `secret "db-password"`) || !diags.HasError() {
+		t.Fatal("expected an error with error_on_missing")
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/tracelog"
+)
+
+// redactedArgs replaces bind parameters in query logs. Parameters routinely
+// carry secret values, so they are never written to the log.
+const redactedArgs = "[REDACTED]"
+
+// newQueryTracer returns a pgx query tracer that routes query logs through tflog.
+func newQueryTracer() pgx.QueryTracer {
+	return &tracelog.TraceLog{
+		Logger:   tracelog.LoggerFunc(logQuery),
+		LogLevel: tracelog.LogLevelTrace,
+	}
+}
+
+// logQuery writes a single pgx log entry to tflog with bind parameters redacted.
+func logQuery(ctx context.Context, level tracelog.LogLevel, msg string, data map[string]interface{}) {
+	fields := redactQueryLogData(data)
+	msg = fmt.Sprintf("pgx: %s", msg)
+
+	switch level {
+	case tracelog.LogLevelTrace:
+		tflog.Trace(ctx, msg, fields)
+	case tracelog.LogLevelDebug:
+		tflog.Debug(ctx, msg, fields)
+	case tracelog.LogLevelInfo:
+		tflog.Info(ctx, msg, fields)
+	case tracelog.LogLevelWarn:
+		tflog.Warn(ctx, msg, fields)
+	default:
+		tflog.Error(ctx, msg, fields)
+	}
+}
+
+// redactQueryLogData returns a copy of data with any bind parameters redacted.
+func redactQueryLogData(data map[string]interface{}) map[string]interface{} {
+	fields := make(map[string]interface{}, len(data))
+
+	for key, value := range data {
+		if key == "args" {
+			value = redactedArgs
+		}
+		fields[key] = value
+	}
+
+	return fields
+}
@@ -0,0 +1,27 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+)
+
+func TestRedactQueryLogData(t *testing.T) {
+	data := map[string]interface{}{
+		"sql":  "SELECT vault.create_secret($1, $2, $3)",
+		"args": []interface{}{"super-secret-value", "name", "description"},
+	}
+
+	fields := redactQueryLogData(data)
+
+	if fields["args"] != redactedArgs {
+		t.Errorf("expected args to be redacted, got: %v", fields["args"])
+	}
+	if fields["sql"] != data["sql"] {
+		t.Errorf("expected sql to be preserved, got: %v", fields["sql"])
+	}
+	if _, ok := data["args"].([]interface{}); !ok {
+		t.Error("expected original log data to be left unmodified")
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// reconnectTimeout bounds creating and pinging a replacement pool.
+const reconnectTimeout = 10 * time.Second
+
+// pool returns the connection pool, which reconnect may replace.
+func (d *ProviderData) pool() *pgxpool.Pool {
+	d.poolMu.RLock()
+	defer d.poolMu.RUnlock()
+
+	return d.Pool
+}
+
+// isConnectionLostError reports whether err means the database connection
+// was lost before the statement could take effect, so running it again on a
+// new connection is safe. Cancellation and timeouts don't count.
+func isConnectionLostError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		// 57P01: admin_shutdown, 57P02: crash_shutdown, 57P03: cannot_connect_now
+		return pgErr.Code == "57P01" || pgErr.Code == "57P02" || pgErr.Code == "57P03"
+	}
+
+	var connectErr *pgconn.ConnectError
+	if errors.As(err, &connectErr) {
+		return true
+	}
+
+	return pgconn.SafeToRetry(err)
+}
+
+// reconnecting calls fn and, with auto_reconnect, calls it once more on a
+// re-created pool if it failed because the database connection was lost,
+// e.g. while Supabase restarted the database.
+func (d *ProviderData) reconnecting(ctx context.Context, fn func() error) error {
+	if !d.AutoReconnect {
+		return fn()
+	}
+
+	failed := d.pool()
+	err := fn()
+	if !isConnectionLostError(err) {
+		return err
+	}
+
+	history := retryHistory{operation: "database operation"}
+	history.attempt(err)
+
+	tflog.Warn(ctx, "Lost the database connection, re-creating the connection pool", map[string]interface{}{
+		"error": err.Error(),
+	})
+
+	if reconnectErr := d.reconnect(ctx, failed); reconnectErr != nil {
+		history.step("reconnecting", reconnectErr)
+		return history.err()
+	}
+
+	// Any other outcome is the operation's own, e.g. pgx.ErrNoRows, which
+	// callers compare against directly
+	err = fn()
+	if !isConnectionLostError(err) {
+		return err
+	}
+
+	history.attempt(err)
+
+	return history.err()
+}
+
+// reconnect replaces failed with a new pool created from the provider's
+// configuration. Concurrent operations that lost their connections at the
+// same time share a single replacement.
+func (d *ProviderData) reconnect(ctx context.Context, failed *pgxpool.Pool) error {
+	d.poolMu.Lock()
+	defer d.poolMu.Unlock()
+
+	// Another operation already replaced the pool
+	if d.Pool != failed {
+		return nil
+	}
+
+	if d.poolConfig == nil {
+		return fmt.Errorf("no pool configuration to reconnect with")
+	}
+
+	reconnectCtx, cancel := context.WithTimeout(ctx, reconnectTimeout)
+	defer cancel()
+
+	pool, err := pgxpool.NewWithConfig(reconnectCtx, d.poolConfig.Copy())
+	if err != nil {
+		return err
+	}
+
+	if !d.SkipPing {
+		if err := pool.Ping(reconnectCtx); err != nil {
+			pool.Close()
+			return err
+		}
+	}
+
+	d.Pool = pool
+
+	// Close waits for connections still checked out of the old pool, so it
+	// mustn't hold up the operation that reconnected
+	if failed != nil {
+		go failed.Close()
+	}
+
+	tflog.Info(ctx, "Re-created the connection pool")
+
+	return nil
+}
+
+// reconnectingRow runs its query when scanned, like the row queryRow
+// returns, reconnecting once if the connection was lost.
+type reconnectingRow struct {
+	data *ProviderData
+	ctx  context.Context
+	sql  string
+	args []any
+}
+
+func (r reconnectingRow) Scan(dest ...any) error {
+	return r.data.reconnecting(r.ctx, func() error {
+		return r.data.queryRowOnce(r.ctx, r.sql, r.args...).Scan(dest...)
+	})
+}
@@ -0,0 +1,97 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func TestIsConnectionLostError(t *testing.T) {
+	testCases := map[string]struct {
+		err      error
+		expected bool
+	}{
+		"nil":             {err: nil},
+		"admin shutdown":  {err: &pgconn.PgError{Code: "57P01"}, expected: true},
+		"starting up":     {err: &pgconn.PgError{Code: "57P03"}, expected: true},
+		"wrapped":         {err: fmt.Errorf("calling vault.create_secret: %w", &pgconn.PgError{Code: "57P01"}), expected: true},
+		"connect error":   {err: &pgconn.ConnectError{}, expected: true},
+		"privilege error": {err: &pgconn.PgError{Code: "42501"}},
+		"canceled":        {err: context.Canceled},
+		"deadline":        {err: fmt.Errorf("acquiring: %w", context.DeadlineExceeded)},
+		"unrelated error": {err: errors.New("boom")},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if lost := isConnectionLostError(testCase.err); lost != testCase.expected {
+				t.Errorf("expected %t, got %t", testCase.expected, lost)
+			}
+		})
+	}
+}
+
+func TestReconnecting(t *testing.T) {
+	lost := &pgconn.PgError{Code: "57P01"}
+
+	testCases := map[string]struct {
+		autoReconnect bool
+		err           error
+		expectedCalls int
+	}{
+		"disabled":           {err: lost, expectedCalls: 1},
+		"success":            {autoReconnect: true, expectedCalls: 1},
+		"other error":        {autoReconnect: true, err: errors.New("boom"), expectedCalls: 1},
+		"reconnecting fails": {autoReconnect: true, err: lost, expectedCalls: 1},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			// Without a pool configuration reconnecting always fails
+			d := &ProviderData{AutoReconnect: testCase.autoReconnect}
+
+			calls := 0
+			err := d.reconnecting(context.Background(), func() error {
+				calls++
+				return testCase.err
+			})
+
+			if calls != testCase.expectedCalls {
+				t.Errorf("expected %d calls, got %d", testCase.expectedCalls, calls)
+			}
+			if !errors.Is(err, testCase.err) {
+				t.Errorf("expected %v, got %v", testCase.err, err)
+			}
+		})
+	}
+}
+
+func TestReconnectSkipPing(t *testing.T) {
+	// Nothing listens on port 1, so only a pool that isn't pinged can be
+	// created
+	poolConfig, err := pgxpool.ParseConfig("postgres://postgres@127.0.0.1:1/postgres?connect_timeout=1")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	for _, skipPing := range []bool{false, true} {
+		t.Run(fmt.Sprintf("skip_ping=%t", skipPing), func(t *testing.T) {
+			d := &ProviderData{poolConfig: poolConfig, SkipPing: skipPing}
+
+			err := d.reconnect(context.Background(), nil)
+			if skipPing != (err == nil) {
+				t.Errorf("unexpected result with skip_ping = %t: %v", skipPing, err)
+			}
+			if pool := d.pool(); pool != nil {
+				pool.Close()
+			}
+		})
+	}
+}
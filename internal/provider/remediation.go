@@ -0,0 +1,80 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Remediation hints appended to error details, kept here so every
+// diagnostic suggests the same fix for the same failure.
+const (
+	hintConnection = "Check host, port and that the database accepts connections from this network. " +
+		"Supabase direct connections (db.<project-ref>.supabase.co:5432) are IPv6-only unless the IPv4 add-on is enabled; " +
+		"on IPv4-only networks connect through the pooler instead. " +
+		"See https://supabase.com/docs/guides/database/connecting-to-postgres"
+
+	hintAuthentication = "Check user and password. The database password is set under Project Settings > Database in the Supabase dashboard, " +
+		"where it can also be reset. Through the pooler the user is postgres.<project-ref> rather than postgres. " +
+		"See https://supabase.com/docs/guides/database/connecting-to-postgres"
+
+	hintVaultMissing = "Vault isn't installed in this database. Enable it under Integrations > Vault in the Supabase dashboard, or run " +
+		"CREATE EXTENSION IF NOT EXISTS supabase_vault CASCADE; as a superuser. " +
+		"For local development and test databases, set auto_create_extensions = true. " +
+		"See https://supabase.com/docs/guides/database/vault"
+
+	hintPrivilege = "The connecting role lacks privileges on the vault schema. Connect as postgres, or grant them with " +
+		"GRANT USAGE ON SCHEMA vault TO <role>; GRANT EXECUTE ON ALL FUNCTIONS IN SCHEMA vault TO <role>; " +
+		"and, to read values, GRANT SELECT ON vault.decrypted_secrets TO <role>;"
+
+	hintDuplicateName = "A secret with this name already exists. Import it with terraform import, or set adopt_existing = true to take it over."
+
+	hintTooManyConnections = "The database has no connection slots left. Close idle connections, or connect through the Supabase pooler, " +
+		"which shares fewer database connections between its clients."
+)
+
+// remediationHint returns the hint for the failure err describes, or an
+// empty string when there is none.
+func remediationHint(err error) string {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		// 28P01: invalid_password, 28000: invalid_authorization_specification
+		case "28P01", "28000":
+			return hintAuthentication
+		// 3F000: invalid_schema_name, 42P01: undefined_table, 42883: undefined_function
+		case "3F000", "42P01", "42883":
+			return hintVaultMissing
+		// 42501: insufficient_privilege
+		case "42501":
+			return hintPrivilege
+		// 23505: unique_violation
+		case "23505":
+			return hintDuplicateName
+		// 53300: too_many_connections
+		case "53300":
+			return hintTooManyConnections
+		}
+		return ""
+	}
+
+	var connectErr *pgconn.ConnectError
+	if errors.As(err, &connectErr) {
+		return hintConnection
+	}
+
+	return ""
+}
+
+// withRemediation appends the remediation hint for err to detail, if any.
+func withRemediation(detail string, err error) string {
+	hint := remediationHint(err)
+	if hint == "" {
+		return detail
+	}
+
+	return detail + "\n\n" + hint
+}
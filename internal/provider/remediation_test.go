@@ -0,0 +1,48 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestRemediationHint(t *testing.T) {
+	testCases := map[string]struct {
+		err      error
+		expected string
+	}{
+		"wrong password":         {err: &pgconn.PgError{Code: "28P01"}, expected: hintAuthentication},
+		"missing schema":         {err: &pgconn.PgError{Code: "3F000"}, expected: hintVaultMissing},
+		"missing function":       {err: &pgconn.PgError{Code: "42883"}, expected: hintVaultMissing},
+		"insufficient privilege": {err: &pgconn.PgError{Code: "42501"}, expected: hintPrivilege},
+		"duplicate name":         {err: &pgconn.PgError{Code: "23505"}, expected: hintDuplicateName},
+		"too many connections":   {err: &pgconn.PgError{Code: "53300"}, expected: hintTooManyConnections},
+		"wrapped":                {err: fmt.Errorf("calling vault.create_secret: %w", &pgconn.PgError{Code: "42501"}), expected: hintPrivilege},
+		"other database error":   {err: &pgconn.PgError{Code: "22P02"}},
+		"unrelated error":        {err: errors.New("boom")},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if hint := remediationHint(testCase.err); hint != testCase.expected {
+				t.Errorf("expected %q, got %q", testCase.expected, hint)
+			}
+		})
+	}
+}
+
+func TestWithRemediation(t *testing.T) {
+	err := &pgconn.PgError{Code: "42501"}
+	if detail := withRemediation("Error deleting secret", err); detail != "Error deleting secret\n\n"+hintPrivilege {
+		t.Errorf("expected the privilege hint to be appended, got %q", detail)
+	}
+
+	if detail := withRemediation("Error deleting secret", errors.New("boom")); detail != "Error deleting secret" {
+		t.Errorf("expected the detail to be unchanged, got %q", detail)
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"strings"
+)
+
+// retryHistory accumulates the failures of an operation that is retried, so
+// that when it finally gives up the error shows how it got there rather than
+// only the last failure.
+type retryHistory struct {
+	operation string
+	attempts  int
+	failures  []retryFailure
+}
+
+// retryFailure is one failed step of a retried operation.
+type retryFailure struct {
+	step string
+	err  error
+}
+
+// attempt records that the operation itself failed with err.
+func (h *retryHistory) attempt(err error) {
+	h.attempts++
+	h.failures = append(h.failures, retryFailure{step: fmt.Sprintf("attempt %d", h.attempts), err: err})
+}
+
+// step records that a step between attempts, such as reconnecting, failed
+// with err.
+func (h *retryHistory) step(step string, err error) {
+	h.failures = append(h.failures, retryFailure{step: step, err: err})
+}
+
+// err returns the error summarizing every recorded failure.
+func (h *retryHistory) err() error {
+	return &retryError{operation: h.operation, attempts: h.attempts, failures: h.failures}
+}
+
+// retryError is returned when a retried operation gives up. It wraps every
+// failure, so errors.Is and errors.As see each of them.
+type retryError struct {
+	operation string
+	attempts  int
+	failures  []retryFailure
+}
+
+func (e *retryError) Error() string {
+	var b strings.Builder
+
+	attempts := "attempts"
+	if e.attempts == 1 {
+		attempts = "attempt"
+	}
+	fmt.Fprintf(&b, "%s failed after %d %s:", e.operation, e.attempts, attempts)
+
+	for _, failure := range e.failures {
+		fmt.Fprintf(&b, "\n  - %s: %s", failure.step, failure.err)
+	}
+
+	return b.String()
+}
+
+func (e *retryError) Unwrap() []error {
+	errs := make([]error, len(e.failures))
+	for i, failure := range e.failures {
+		errs[i] = failure.err
+	}
+
+	return errs
+}
@@ -0,0 +1,35 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestRetryHistory(t *testing.T) {
+	lost := &pgconn.PgError{Severity: "FATAL", Code: "57P01", Message: "terminating connection due to administrator command"}
+	refused := errors.New("connection refused")
+
+	history := retryHistory{operation: "database operation"}
+	history.attempt(lost)
+	history.step("reconnecting", refused)
+	history.attempt(lost)
+
+	err := history.err()
+
+	expected := "database operation failed after 2 attempts:\n" +
+		"  - attempt 1: FATAL: terminating connection due to administrator command (SQLSTATE 57P01)\n" +
+		"  - reconnecting: connection refused\n" +
+		"  - attempt 2: FATAL: terminating connection due to administrator command (SQLSTATE 57P01)"
+	if err.Error() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, err)
+	}
+
+	if !errors.Is(err, lost) || !errors.Is(err, refused) {
+		t.Errorf("expected every failure to be wrapped, got: %v", err)
+	}
+}
@@ -0,0 +1,367 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// errNoDirectConnection is returned by operations that need the connection
+// pool when the provider talks to the Supabase Management API instead.
+var errNoDirectConnection = errors.New("this operation needs a direct database connection, but the provider is configured with management_token, " +
+	"which only supports creating, reading, updating, deleting and adopting supabase-vault_secret secrets")
+
+// storedSecret is a secret's row in vault.secrets, without its value.
+type storedSecret struct {
+	ID          string
+	Name        string
+	Description string
+	KeyID       sql.NullString
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+
+	// CreatedBy is the role that created the secret, where vault.secrets
+	// records it
+	CreatedBy sql.NullString
+}
+
+// secretBackend stores the secrets of supabase-vault_secret. Both
+// implementations run the same statements, either on the connection pool or
+// through the Supabase Management API.
+type secretBackend interface {
+	// createSecret calls vault.create_secret and returns the new secret's
+	// id, which a failing trigger can make NULL.
+	createSecret(ctx context.Context, value, name, description string, keyID any) (sql.NullString, error)
+
+	// readSecret returns the secret with the given id, or pgx.ErrNoRows.
+	readSecret(ctx context.Context, id string) (storedSecret, error)
+
+	// lookupSecretID returns the id of the secret stored under name, if any.
+	lookupSecretID(ctx context.Context, name string) (string, bool, error)
+
+	// updateSecret calls vault.update_secret.
+	updateSecret(ctx context.Context, id, value, name, description string, keyID any) error
+
+	// updateDescription sets only the description column. On releases
+	// without vault.update_secret the value is re-encrypted in the database,
+	// as the description is bound to its ciphertext.
+	updateDescription(ctx context.Context, id, description string) error
+
+	// updateMetadata sets the name and description columns, leaving the
+	// stored value unchanged, re-encrypted like updateDescription where
+	// needed.
+	updateMetadata(ctx context.Context, id, name, description string) error
+
+	// reencryptSecret re-encrypts the stored value under keyID without the
+	// value leaving the database.
+	reencryptSecret(ctx context.Context, id, name, description string, keyID any) error
+
+	// deleteSecret deletes the secret with the given id.
+	deleteSecret(ctx context.Context, id string) error
+}
+
+// secrets returns the backend supabase-vault_secret stores secrets with.
+func (d *ProviderData) secrets() secretBackend {
+	if d.managementAPI != nil {
+		return apiSecretBackend{client: d.managementAPI, data: d}
+	}
+
+	return sqlSecretBackend{data: d}
+}
+
+const (
+	createSecretQuery      = "SELECT vault.create_secret($1, $2, $3, $4) AS id"
+	lookupSecretIDQuery    = "SELECT id FROM vault.secrets WHERE name = $1"
+	updateSecretQuery      = "SELECT vault.update_secret($1, $2, $3, $4, $5)"
+	updateDescriptionQuery = "UPDATE vault.secrets SET description = $2 WHERE id = $1"
+	updateMetadataQuery    = "UPDATE vault.secrets SET name = $2, description = $3 WHERE id = $1"
+	deleteSecretQuery      = "DELETE FROM vault.secrets WHERE id = $1"
+
+	// The decrypted value is fed straight back into vault.update_secret()
+	// within a single statement
+	reencryptSecretQuery = `
+		SELECT vault.update_secret(id, decrypted_secret, $2, $3, $4)
+		FROM vault.decrypted_secrets
+		WHERE id = $1
+	`
+)
+
+// createdBySelect returns the select list item reading the role that
+// created a secret as created_by, NULL where vault.secrets has no readable
+// column recording it. The column is detected once with the extension, and
+// it is informational, so a failed detection reads NULL too.
+func (d *ProviderData) createdBySelect(ctx context.Context) string {
+	if d == nil {
+		return "NULL::text AS created_by"
+	}
+
+	ext, err := d.vaultExtension(ctx)
+	if err != nil {
+		tflog.Debug(ctx, "Unable to detect the column recording who created a secret", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return "NULL::text AS created_by"
+	}
+
+	// Only the detected names are ever interpolated
+	switch ext.createdByColumn {
+	case "created_by":
+		return "created_by::text AS created_by"
+	case "owner":
+		return "owner::text AS created_by"
+	}
+
+	return "NULL::text AS created_by"
+}
+
+// sqlSecretBackend runs statements on the provider's connection pool.
+type sqlSecretBackend struct {
+	data *ProviderData
+}
+
+func (b sqlSecretBackend) createSecret(ctx context.Context, value, name, description string, keyID any) (sql.NullString, error) {
+	var secretID sql.NullString
+	err := b.data.queryRow(ctx, createSecretQuery, value, name, description, keyID).Scan(&secretID)
+
+	return secretID, err
+}
+
+func (b sqlSecretBackend) readSecret(ctx context.Context, id string) (storedSecret, error) {
+	var secret storedSecret
+	query := "SELECT id, name, description, key_id, created_at, updated_at, " + b.data.createdBySelect(ctx) + " FROM vault.secrets WHERE id = $1"
+	err := b.data.queryRow(ctx, query, id).Scan(
+		&secret.ID, &secret.Name, &secret.Description, &secret.KeyID, &secret.CreatedAt, &secret.UpdatedAt, &secret.CreatedBy,
+	)
+
+	return secret, err
+}
+
+func (b sqlSecretBackend) lookupSecretID(ctx context.Context, name string) (string, bool, error) {
+	var id string
+	err := b.data.queryRow(ctx, lookupSecretIDQuery, name).Scan(&id)
+
+	if err == pgx.ErrNoRows {
+		return "", false, nil
+	}
+
+	if err != nil {
+		return "", false, err
+	}
+
+	return id, true, nil
+}
+
+func (b sqlSecretBackend) updateSecret(ctx context.Context, id, value, name, description string, keyID any) error {
+	if !b.data.legacyUpdate(ctx) {
+		_, err := b.data.exec(ctx, updateSecretQuery, id, value, name, description, keyID)
+		if !b.data.fallBackToLegacyUpdate(ctx, err) {
+			return err
+		}
+	}
+
+	_, err := b.legacyUpdate(ctx, legacyUpdateSecretQuery, id, value, name, description, keyID)
+
+	return err
+}
+
+// legacyUpdate runs one of the direct UPDATE statements used where
+// vault.update_secret doesn't exist, after checking in the same transaction
+// that the trigger encrypting the written value is in place.
+func (b sqlSecretBackend) legacyUpdate(ctx context.Context, query string, args ...any) (pgconn.CommandTag, error) {
+	var tag pgconn.CommandTag
+	err := b.data.withTx(ctx, func(tx pgx.Tx) error {
+		if err := checkEncryptionTrigger(ctx, tx); err != nil {
+			return err
+		}
+
+		var err error
+		tag, err = tx.Exec(ctx, query, args...)
+		return err
+	})
+
+	return tag, err
+}
+
+func (b sqlSecretBackend) updateDescription(ctx context.Context, id, description string) error {
+	var err error
+	if b.data.legacyUpdate(ctx) {
+		_, err = b.legacyUpdate(ctx, legacyReencryptSecretQuery, id, nil, description, nil)
+	} else {
+		_, err = b.data.exec(ctx, updateDescriptionQuery, id, description)
+	}
+
+	return err
+}
+
+func (b sqlSecretBackend) updateMetadata(ctx context.Context, id, name, description string) error {
+	var tag pgconn.CommandTag
+	var err error
+	if b.data.legacyUpdate(ctx) {
+		tag, err = b.legacyUpdate(ctx, legacyReencryptSecretQuery, id, name, description, nil)
+	} else {
+		tag, err = b.data.exec(ctx, updateMetadataQuery, id, name, description)
+	}
+	if err == nil && tag.RowsAffected() == 0 {
+		err = fmt.Errorf("secret %s no longer exists", id)
+	}
+
+	return err
+}
+
+func (b sqlSecretBackend) reencryptSecret(ctx context.Context, id, name, description string, keyID any) error {
+	var tag pgconn.CommandTag
+	var err error
+	if !b.data.legacyUpdate(ctx) {
+		tag, err = b.data.exec(ctx, reencryptSecretQuery, id, name, description, keyID)
+	}
+	if b.data.legacyUpdate(ctx) || b.data.fallBackToLegacyUpdate(ctx, err) {
+		tag, err = b.legacyUpdate(ctx, legacyReencryptSecretQuery, id, name, description, keyID)
+	}
+	if err == nil && tag.RowsAffected() == 0 {
+		err = fmt.Errorf("secret %s no longer exists", id)
+	}
+
+	return err
+}
+
+func (b sqlSecretBackend) deleteSecret(ctx context.Context, id string) error {
+	_, err := b.data.exec(ctx, deleteSecretQuery, id)
+
+	return err
+}
+
+// apiSecretBackend runs statements through the Supabase Management API.
+// Statements that change rows return them, as the API reports no row counts.
+type apiSecretBackend struct {
+	client *managementAPIClient
+	data   *ProviderData
+}
+
+func (b apiSecretBackend) createSecret(ctx context.Context, value, name, description string, keyID any) (sql.NullString, error) {
+	rows, err := b.client.query(ctx, createSecretQuery, value, name, description, keyID)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+
+	if len(rows) == 0 {
+		return sql.NullString{}, pgx.ErrNoRows
+	}
+
+	return nullStringColumn(rows[0], "id")
+}
+
+func (b apiSecretBackend) readSecret(ctx context.Context, id string) (storedSecret, error) {
+	// Timestamps are formatted in the database, as the API's JSON encoding
+	// of timestamptz isn't specified
+	query := `
+		SELECT id, name, description, key_id,
+			to_json(created_at) #>> '{}' AS created_at,
+			to_json(updated_at) #>> '{}' AS updated_at,
+			` + b.data.createdBySelect(ctx) + `
+		FROM vault.secrets
+		WHERE id = $1
+	`
+	rows, err := b.client.query(ctx, query, id)
+	if err != nil {
+		return storedSecret{}, err
+	}
+
+	if len(rows) == 0 {
+		return storedSecret{}, pgx.ErrNoRows
+	}
+
+	row := rows[0]
+	var secret storedSecret
+	var createdAt, updatedAt string
+	for column, dest := range map[string]*string{"id": &secret.ID, "name": &secret.Name, "description": &secret.Description, "created_at": &createdAt, "updated_at": &updatedAt} {
+		value, err := nullStringColumn(row, column)
+		if err != nil {
+			return storedSecret{}, err
+		}
+		*dest = value.String
+	}
+
+	if secret.KeyID, err = nullStringColumn(row, "key_id"); err != nil {
+		return storedSecret{}, err
+	}
+	if secret.CreatedBy, err = nullStringColumn(row, "created_by"); err != nil {
+		return storedSecret{}, err
+	}
+	if secret.CreatedAt, err = time.Parse(time.RFC3339Nano, createdAt); err != nil {
+		return storedSecret{}, fmt.Errorf("parsing created_at: %w", err)
+	}
+	if secret.UpdatedAt, err = time.Parse(time.RFC3339Nano, updatedAt); err != nil {
+		return storedSecret{}, fmt.Errorf("parsing updated_at: %w", err)
+	}
+
+	return secret, nil
+}
+
+func (b apiSecretBackend) lookupSecretID(ctx context.Context, name string) (string, bool, error) {
+	rows, err := b.client.query(ctx, lookupSecretIDQuery, name)
+	if err != nil || len(rows) == 0 {
+		return "", false, err
+	}
+
+	id, err := nullStringColumn(rows[0], "id")
+
+	return id.String, err == nil, err
+}
+
+func (b apiSecretBackend) updateSecret(ctx context.Context, id, value, name, description string, keyID any) error {
+	_, err := b.client.query(ctx, updateSecretQuery, id, value, name, description, keyID)
+
+	return err
+}
+
+func (b apiSecretBackend) updateDescription(ctx context.Context, id, description string) error {
+	_, err := b.client.query(ctx, updateDescriptionQuery, id, description)
+
+	return err
+}
+
+func (b apiSecretBackend) updateMetadata(ctx context.Context, id, name, description string) error {
+	rows, err := b.client.query(ctx, updateMetadataQuery+" RETURNING id", id, name, description)
+	if err == nil && len(rows) == 0 {
+		err = fmt.Errorf("secret %s no longer exists", id)
+	}
+
+	return err
+}
+
+func (b apiSecretBackend) reencryptSecret(ctx context.Context, id, name, description string, keyID any) error {
+	rows, err := b.client.query(ctx, reencryptSecretQuery, id, name, description, keyID)
+	if err == nil && len(rows) == 0 {
+		err = fmt.Errorf("secret %s no longer exists", id)
+	}
+
+	return err
+}
+
+func (b apiSecretBackend) deleteSecret(ctx context.Context, id string) error {
+	_, err := b.client.query(ctx, deleteSecretQuery, id)
+
+	return err
+}
+
+// nullStringColumn returns column of a Management API result row, which
+// must be a string or null.
+func nullStringColumn(row map[string]any, column string) (sql.NullString, error) {
+	switch value := row[column].(type) {
+	case nil:
+		return sql.NullString{}, nil
+	case string:
+		return sql.NullString{String: value, Valid: true}, nil
+	default:
+		return sql.NullString{}, fmt.Errorf("unexpected %T for column %s", value, column)
+	}
+}
@@ -0,0 +1,225 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestAPISecretBackendReadSecret(t *testing.T) {
+	found := true
+	backend := apiSecretBackend{client: testManagementAPI(t, func(statement string) (int, any) {
+		// Only the detected column is read, never the whole row
+		if !strings.Contains(statement, "created_by::text AS created_by") || strings.Contains(statement, "to_jsonb") {
+			t.Errorf("expected only the created_by column to be read, got: %s", statement)
+		}
+		if !found {
+			return http.StatusCreated, []map[string]any{}
+		}
+
+		return http.StatusCreated, []map[string]any{{
+			"id":          "4c1f2a3b-5d6e-4f70-8a9b-0c1d2e3f4a5b",
+			"name":        "api_key",
+			"description": "API key",
+			"key_id":      nil,
+			"created_at":  "2026-01-02T03:04:05.123456+00:00",
+			"updated_at":  "2026-01-02T03:04:05.123456+00:00",
+			"created_by":  "vault_admin",
+		}}
+	}), data: &ProviderData{vaultExt: &vaultExtension{createdByColumn: "created_by"}}}
+
+	secret, err := backend.readSecret(context.Background(), "4c1f2a3b-5d6e-4f70-8a9b-0c1d2e3f4a5b")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	createdAt := time.Date(2026, 1, 2, 3, 4, 5, 123456000, time.UTC)
+	if secret.Name != "api_key" || secret.Description != "API key" || secret.KeyID.Valid || !secret.CreatedAt.Equal(createdAt) || secret.CreatedBy.String != "vault_admin" {
+		t.Errorf("unexpected secret %+v", secret)
+	}
+
+	found = false
+	if _, err := backend.readSecret(context.Background(), "4c1f2a3b-5d6e-4f70-8a9b-0c1d2e3f4a5b"); !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("expected pgx.ErrNoRows for a missing secret, got: %v", err)
+	}
+}
+
+func TestCreatedBySelect(t *testing.T) {
+	testCases := map[string]struct {
+		data     *ProviderData
+		expected string
+	}{
+		"created_by": {
+			data:     &ProviderData{vaultExt: &vaultExtension{createdByColumn: "created_by"}},
+			expected: "created_by::text AS created_by",
+		},
+		"owner": {
+			data:     &ProviderData{vaultExt: &vaultExtension{createdByColumn: "owner"}},
+			expected: "owner::text AS created_by",
+		},
+		"no readable column": {
+			data:     &ProviderData{vaultExt: &vaultExtension{}},
+			expected: "NULL::text AS created_by",
+		},
+		"no provider data": {
+			expected: "NULL::text AS created_by",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if selected := testCase.data.createdBySelect(context.Background()); selected != testCase.expected {
+				t.Errorf("expected %q, got %q", testCase.expected, selected)
+			}
+		})
+	}
+}
+
+func TestAPISecretBackendUpdateMetadata(t *testing.T) {
+	backend := apiSecretBackend{client: testManagementAPI(t, func(statement string) (int, any) {
+		if !strings.HasSuffix(statement, "RETURNING id") {
+			t.Errorf("expected the updated rows to be returned, got: %s", statement)
+		}
+
+		return http.StatusCreated, []map[string]any{}
+	})}
+
+	if err := backend.updateMetadata(context.Background(), "4c1f2a3b-5d6e-4f70-8a9b-0c1d2e3f4a5b", "api_key", ""); err == nil {
+		t.Error("expected an error when no secret was updated")
+	}
+}
+
+func TestSecretsWithoutConnection(t *testing.T) {
+	d := &ProviderData{managementAPI: newManagementAPIClient(defaultManagementAPIURL, "sbp_test", testProjectRef)}
+
+	if _, ok := d.secrets().(apiSecretBackend); !ok {
+		t.Errorf("expected the Management API backend, got %T", d.secrets())
+	}
+
+	if _, err := d.exec(context.Background(), "SELECT 1"); !errors.Is(err, errNoDirectConnection) {
+		t.Errorf("expected errNoDirectConnection, got: %v", err)
+	}
+}
+
+// recordingTx is an operation transaction recording the statements run in
+// it. Its only query is the encryption trigger check, answered with
+// triggerExists.
+type recordingTx struct {
+	pgx.Tx
+	triggerExists bool
+	statements    []string
+	args          [][]any
+}
+
+func (tx *recordingTx) Begin(ctx context.Context) (pgx.Tx, error) { return tx, nil }
+func (tx *recordingTx) Commit(ctx context.Context) error          { return nil }
+func (tx *recordingTx) Rollback(ctx context.Context) error        { return nil }
+
+func (tx *recordingTx) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	tx.statements = append(tx.statements, sql)
+	tx.args = append(tx.args, args)
+	return pgconn.NewCommandTag("UPDATE 1"), nil
+}
+
+func (tx *recordingTx) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	tx.statements = append(tx.statements, sql)
+	tx.args = append(tx.args, args)
+	return triggerRow{exists: tx.triggerExists}
+}
+
+type triggerRow struct {
+	exists bool
+}
+
+func (r triggerRow) Scan(dest ...any) error {
+	*dest[0].(*bool) = r.exists
+	return nil
+}
+
+func TestSQLSecretBackendMetadataUpdates(t *testing.T) {
+	const id = "4c1f2a3b-5d6e-4f70-8a9b-0c1d2e3f4a5b"
+
+	testCases := map[string]struct {
+		ext           vaultExtension
+		triggerExists bool
+		update        func(ctx context.Context, backend sqlSecretBackend) error
+		expected      []string
+		expectedArgs  []any
+		expectErr     bool
+	}{
+		"description": {
+			ext: vaultExtension{hasUpdateSecret: true},
+			update: func(ctx context.Context, backend sqlSecretBackend) error {
+				return backend.updateDescription(ctx, id, "API key")
+			},
+			expected:     []string{updateDescriptionQuery},
+			expectedArgs: []any{id, "API key"},
+		},
+		"metadata": {
+			ext: vaultExtension{hasUpdateSecret: true},
+			update: func(ctx context.Context, backend sqlSecretBackend) error {
+				return backend.updateMetadata(ctx, id, "api_key", "API key")
+			},
+			expected:     []string{updateMetadataQuery},
+			expectedArgs: []any{id, "api_key", "API key"},
+		},
+		// The pgsodium trigger binds the description to the ciphertext, so
+		// legacy releases re-encrypt the value with the new metadata
+		"legacy description": {
+			triggerExists: true,
+			update: func(ctx context.Context, backend sqlSecretBackend) error {
+				return backend.updateDescription(ctx, id, "API key")
+			},
+			expected:     []string{encryptionTriggerQuery, legacyReencryptSecretQuery},
+			expectedArgs: []any{id, nil, "API key", nil},
+		},
+		"legacy metadata": {
+			triggerExists: true,
+			update: func(ctx context.Context, backend sqlSecretBackend) error {
+				return backend.updateMetadata(ctx, id, "api_key", "API key")
+			},
+			expected:     []string{encryptionTriggerQuery, legacyReencryptSecretQuery},
+			expectedArgs: []any{id, "api_key", "API key", nil},
+		},
+		"legacy without trigger": {
+			update: func(ctx context.Context, backend sqlSecretBackend) error {
+				return backend.updateDescription(ctx, id, "API key")
+			},
+			expected:  []string{encryptionTriggerQuery},
+			expectErr: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			tx := &recordingTx{triggerExists: testCase.triggerExists}
+			ctx := context.WithValue(context.Background(), operationTxKey{}, pgx.Tx(tx))
+			backend := sqlSecretBackend{data: &ProviderData{vaultExt: &testCase.ext}}
+
+			err := testCase.update(ctx, backend)
+			if testCase.expectErr && err == nil {
+				t.Fatal("expected error, got none")
+			}
+			if !testCase.expectErr && err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !reflect.DeepEqual(tx.statements, testCase.expected) {
+				t.Fatalf("expected statements %q, got %q", testCase.expected, tx.statements)
+			}
+			if testCase.expectedArgs != nil && !reflect.DeepEqual(tx.args[len(tx.args)-1], testCase.expectedArgs) {
+				t.Errorf("expected arguments %v, got %v", testCase.expectedArgs, tx.args[len(tx.args)-1])
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &SecretHashFunction{}
+
+func NewSecretHashFunction() function.Function {
+	return &SecretHashFunction{}
+}
+
+// SecretHashFunction hashes a value the way value_hash does.
+type SecretHashFunction struct{}
+
+func (f *SecretHashFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "secret_hash"
+}
+
+func (f *SecretHashFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Hash a value like value_hash",
+		MarkdownDescription: "Returns the SHA-256 hex digest of a value, computed exactly like the `value_hash` attribute of `supabase-vault_secret`, " +
+			"e.g. to assert in a `postcondition` that the expected value is stored. No database connection is used.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "value",
+				MarkdownDescription: "Value to hash",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *SecretHashFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var value string
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &value))
+	if resp.Error != nil {
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, hashSecretValue(value)))
+}
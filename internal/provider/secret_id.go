@@ -0,0 +1,21 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// uuidPattern matches the canonical textual form of a UUID.
+var uuidPattern = regexp.MustCompile(`^(?i)[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+
+// validateSecretID returns an error if id is not a well-formed Vault secret UUID.
+func validateSecretID(id string) error {
+	if !uuidPattern.MatchString(id) {
+		return fmt.Errorf("%q is not a valid UUID (expected the form xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx)", id)
+	}
+
+	return nil
+}
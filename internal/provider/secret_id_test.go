@@ -0,0 +1,39 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+)
+
+func TestValidateSecretID(t *testing.T) {
+	testCases := map[string]struct {
+		id        string
+		expectErr bool
+	}{
+		"lowercase":       {id: "6f1c1c0e-2b5a-4f8e-9d3c-1a2b3c4d5e6f"},
+		"uppercase":       {id: "6F1C1C0E-2B5A-4F8E-9D3C-1A2B3C4D5E6F"},
+		"empty":           {id: "", expectErr: true},
+		"name":            {id: "api_key", expectErr: true},
+		"no hyphens":      {id: "6f1c1c0e2b5a4f8e9d3c1a2b3c4d5e6f", expectErr: true},
+		"braces":          {id: "{6f1c1c0e-2b5a-4f8e-9d3c-1a2b3c4d5e6f}", expectErr: true},
+		"trailing data":   {id: "6f1c1c0e-2b5a-4f8e-9d3c-1a2b3c4d5e6f-extra", expectErr: true},
+		"non-hex":         {id: "6f1c1c0e-2b5a-4f8e-9d3c-1a2b3c4d5e6g", expectErr: true},
+		"surrounding ws":  {id: " 6f1c1c0e-2b5a-4f8e-9d3c-1a2b3c4d5e6f ", expectErr: true},
+		"short last part": {id: "6f1c1c0e-2b5a-4f8e-9d3c-1a2b3c4d5e6", expectErr: true},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			err := validateSecretID(testCase.id)
+
+			if testCase.expectErr && err == nil {
+				t.Errorf("expected error for %q, got none", testCase.id)
+			}
+			if !testCase.expectErr && err != nil {
+				t.Errorf("unexpected error for %q: %s", testCase.id, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// checkSecretKeyID returns an error if the secret isn't encrypted with the
+// key expectedKeyID. Vault always decrypts a secret with the key it was
+// encrypted with, so the key can only be checked, not chosen.
+func checkSecretKeyID(keyID sql.NullString, expectedKeyID string) error {
+	if keyID.Valid && strings.EqualFold(keyID.String, expectedKeyID) {
+		return nil
+	}
+
+	actual := "no key"
+	if keyID.Valid {
+		actual = keyID.String
+	}
+
+	return fmt.Errorf("the secret is encrypted with %s, but key_id %s was expected", actual, expectedKeyID)
+}
+
+// keyExistsQuery checks for a pgsodium key by id. The id is compared as
+// text so a malformed key_id is reported as missing rather than failing to
+// cast.
+const keyExistsQuery = `SELECT EXISTS (SELECT 1 FROM pgsodium.key WHERE id::text = $1)`
+
+// keyExists reports whether the pgsodium key keyID exists. Results are cached
+// for the provider's database, as many secrets usually share a few keys.
+func (d *ProviderData) keyExists(ctx context.Context, keyID string) (bool, error) {
+	keyID = strings.ToLower(keyID)
+
+	d.keysMu.Lock()
+	defer d.keysMu.Unlock()
+
+	if exists, ok := d.keys[keyID]; ok {
+		return exists, nil
+	}
+
+	var exists bool
+	if err := d.queryRow(ctx, keyExistsQuery, keyID).Scan(&exists); err != nil {
+		return false, err
+	}
+
+	if d.keys == nil {
+		d.keys = map[string]bool{}
+	}
+	d.keys[keyID] = exists
+
+	return exists, nil
+}
+
+// isKeyCheckUnavailable reports whether err means pgsodium keys can't be
+// looked up at all: the pgsodium schema is missing, as on Vault releases
+// that no longer use it, or the role may not read it.
+func isKeyCheckUnavailable(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+
+	// 3F000: invalid_schema_name, 42P01: undefined_table, 42501: insufficient_privilege
+	return pgErr.Code == "3F000" || pgErr.Code == "42P01" || pgErr.Code == "42501"
+}
+
+// keyIDReadFailed handles failing to read a secret's key_id and timestamps
+// back after operation, which leaves them null in state. With
+// strict_key_id_read that is an error, so Terraform taints a created secret
+// and replaces it on the next apply; otherwise it is only logged.
+func (d *ProviderData) keyIDReadFailed(ctx context.Context, operation string, err error, diags *diag.Diagnostics) {
+	if d.StrictKeyIDRead {
+		diags.AddError(
+			"Unable to read key_id",
+			withRemediation(fmt.Sprintf("Error reading key_id and timestamps after the %s: %s. Unset strict_key_id_read to store them as null instead.", operation, err), err),
+		)
+		return
+	}
+
+	tflog.Warn(ctx, "Unable to read key_id and timestamps after "+operation+", setting to null", map[string]interface{}{
+		"error": err,
+	})
+}
+
+const keyStatusQuery = "SELECT status::text FROM pgsodium.key WHERE id = $1"
+
+// keyStatus returns the status of the pgsodium key a secret is encrypted
+// with: default, valid, invalid or expired. It is null when the secret has
+// no key, the key doesn't exist or pgsodium keys can't be read, which
+// includes the Management API.
+func (d *ProviderData) keyStatus(ctx context.Context, keyID sql.NullString) (types.String, error) {
+	if !keyID.Valid || d.managementAPI != nil {
+		return types.StringNull(), nil
+	}
+
+	// Checked up front, as a failing query would abort a use_transactions
+	// transaction
+	ext, err := d.vaultExtension(ctx)
+	if err != nil || !ext.hasKeyTable {
+		return types.StringNull(), err
+	}
+
+	var status sql.NullString
+	err = d.queryRow(ctx, keyStatusQuery, keyID.String).Scan(&status)
+	if err == pgx.ErrNoRows {
+		return types.StringNull(), nil
+	}
+	if err != nil {
+		return types.StringNull(), err
+	}
+
+	return types.StringPointerValue(nullStringPointer(status)), nil
+}
+
+// keyStatusInvalid reports whether a key with status can no longer be
+// relied on, so secrets encrypted with it need re-encrypting.
+func keyStatusInvalid(status types.String) bool {
+	return status.ValueString() == "invalid" || status.ValueString() == "expired"
+}
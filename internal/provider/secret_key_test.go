@@ -0,0 +1,195 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestCheckSecretKeyID(t *testing.T) {
+	const expectedKeyID = "4c1f2a3b-5d6e-4f70-8a9b-0c1d2e3f4a5b"
+
+	testCases := map[string]struct {
+		keyID     sql.NullString
+		expectErr bool
+	}{
+		"same key": {
+			keyID: sql.NullString{String: expectedKeyID, Valid: true},
+		},
+		"same key different case": {
+			keyID: sql.NullString{String: "4C1F2A3B-5D6E-4F70-8A9B-0C1D2E3F4A5B", Valid: true},
+		},
+		"different key": {
+			keyID:     sql.NullString{String: "9e8d7c6b-5a49-4382-b1a0-f9e8d7c6b5a4", Valid: true},
+			expectErr: true,
+		},
+		"no key": {
+			keyID:     sql.NullString{},
+			expectErr: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			err := checkSecretKeyID(testCase.keyID, expectedKeyID)
+
+			if testCase.expectErr && err == nil {
+				t.Fatal("expected an error, got none")
+			}
+			if !testCase.expectErr && err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+		})
+	}
+}
+
+func TestKeyExistsCached(t *testing.T) {
+	// Without a pool any query fails, so only cached keys can be answered
+	d := &ProviderData{keys: map[string]bool{
+		"4c1f2a3b-5d6e-4f70-8a9b-0c1d2e3f4a5b": true,
+		"9e8d7c6b-5a49-4382-b1a0-f9e8d7c6b5a4": false,
+	}}
+
+	testCases := map[string]struct {
+		keyID     string
+		expected  bool
+		expectErr bool
+	}{
+		"existing key":         {keyID: "4c1f2a3b-5d6e-4f70-8a9b-0c1d2e3f4a5b", expected: true},
+		"different case":       {keyID: "4C1F2A3B-5D6E-4F70-8A9B-0C1D2E3F4A5B", expected: true},
+		"missing key":          {keyID: "9e8d7c6b-5a49-4382-b1a0-f9e8d7c6b5a4"},
+		"not looked up before": {keyID: "0a1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d", expectErr: true},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			exists, err := d.keyExists(context.Background(), testCase.keyID)
+
+			if testCase.expectErr {
+				if err == nil {
+					t.Fatal("expected an error, got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if exists != testCase.expected {
+				t.Errorf("expected %t, got %t", testCase.expected, exists)
+			}
+		})
+	}
+}
+
+func TestIsKeyCheckUnavailable(t *testing.T) {
+	testCases := map[string]struct {
+		err      error
+		expected bool
+	}{
+		"missing schema":    {err: &pgconn.PgError{Code: "3F000"}, expected: true},
+		"missing table":     {err: &pgconn.PgError{Code: "42P01"}, expected: true},
+		"permission denied": {err: fmt.Errorf("query: %w", &pgconn.PgError{Code: "42501"}), expected: true},
+		"connection error":  {err: errors.New("connection refused")},
+		"other error":       {err: &pgconn.PgError{Code: "57014"}},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if unavailable := isKeyCheckUnavailable(testCase.err); unavailable != testCase.expected {
+				t.Errorf("expected %t, got %t", testCase.expected, unavailable)
+			}
+		})
+	}
+}
+
+func TestKeyIDReadFailed(t *testing.T) {
+	testCases := map[string]struct {
+		strict    bool
+		expectErr bool
+	}{
+		"lenient": {},
+		"strict":  {strict: true, expectErr: true},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			d := &ProviderData{StrictKeyIDRead: testCase.strict}
+			var diags diag.Diagnostics
+
+			d.keyIDReadFailed(context.Background(), "creation", errors.New("connection reset"), &diags)
+
+			if testCase.expectErr && !diags.HasError() {
+				t.Fatal("expected an error, got none")
+			}
+			if !testCase.expectErr && diags.HasError() {
+				t.Fatalf("unexpected error: %v", diags)
+			}
+		})
+	}
+}
+
+func TestKeyStatusUnavailable(t *testing.T) {
+	keyID := sql.NullString{String: "4c1f2a3b-5d6e-4f70-8a9b-0c1d2e3f4a5b", Valid: true}
+
+	// None of these may query the database, which the tests don't have
+	testCases := map[string]struct {
+		data  *ProviderData
+		keyID sql.NullString
+	}{
+		"no key": {
+			data: &ProviderData{vaultExt: &vaultExtension{hasKeyTable: true}},
+		},
+		"no key table": {
+			data:  &ProviderData{vaultExt: &vaultExtension{}},
+			keyID: keyID,
+		},
+		"management API": {
+			data:  &ProviderData{managementAPI: &managementAPIClient{}},
+			keyID: keyID,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			status, err := testCase.data.keyStatus(context.Background(), testCase.keyID)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if !status.IsNull() {
+				t.Errorf("expected a null status, got %s", status)
+			}
+		})
+	}
+}
+
+func TestKeyStatusInvalid(t *testing.T) {
+	testCases := map[string]struct {
+		status   types.String
+		expected bool
+	}{
+		"default": {status: types.StringValue("default")},
+		"valid":   {status: types.StringValue("valid")},
+		"invalid": {status: types.StringValue("invalid"), expected: true},
+		"expired": {status: types.StringValue("expired"), expected: true},
+		"unknown": {status: types.StringNull()},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if invalid := keyStatusInvalid(testCase.status); invalid != testCase.expected {
+				t.Errorf("expected %t, got %t", testCase.expected, invalid)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/base64"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// base64NamePrefix marks a stored secret name that was base64-wrapped because
+// the configured name wasn't valid UTF-8.
+const base64NamePrefix = "base64:"
+
+// maxSecretNameLength is the longest secret name the provider accepts, in bytes.
+const maxSecretNameLength = 255
+
+// secretNameSeparator joins the components of a secret name, both those the
+// secret_name function is given and the environment environment_naming
+// prefixes a name with.
+const secretNameSeparator = "_"
+
+// joinSecretName joins the components of a secret name, each trimmed of
+// surrounding whitespace, and checks the result against the provider's
+// naming rules. The position of an unusable component is returned with the
+// error, or -1 when the joined name is at fault.
+func joinSecretName(parts []string) (string, int, error) {
+	if len(parts) == 0 {
+		return "", -1, fmt.Errorf("at least one name component is required")
+	}
+
+	trimmed := make([]string, len(parts))
+	for i, part := range parts {
+		trimmed[i] = strings.TrimSpace(part)
+		if trimmed[i] == "" {
+			return "", i, fmt.Errorf("name component %d must not be empty", i+1)
+		}
+	}
+
+	name := strings.Join(trimmed, secretNameSeparator)
+	if err := validateSecretName(name); err != nil {
+		return "", -1, err
+	}
+
+	return name, -1, nil
+}
+
+// encodeSecretName returns the name to store in Vault. Names that aren't
+// valid UTF-8 are rejected, or base64-wrapped when allowInvalidUTF8 is set,
+// as Postgres text columns can't hold them.
+func encodeSecretName(name string, allowInvalidUTF8 bool) (string, error) {
+	if utf8.ValidString(name) {
+		return name, nil
+	}
+
+	if !allowInvalidUTF8 {
+		return "", fmt.Errorf("secret name is not valid UTF-8; set the provider's allow_invalid_utf8_names to store it base64-encoded")
+	}
+
+	return base64NamePrefix + base64.StdEncoding.EncodeToString([]byte(name)), nil
+}
+
+// decodeSecretName reverses encodeSecretName for a name read from Vault. Only
+// names that decode to invalid UTF-8 are unwrapped, so a regular name that
+// happens to start with the prefix is left untouched.
+func decodeSecretName(stored string, allowInvalidUTF8 bool) string {
+	if !allowInvalidUTF8 || !strings.HasPrefix(stored, base64NamePrefix) {
+		return stored
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(stored, base64NamePrefix))
+	if err != nil || utf8.Valid(decoded) {
+		return stored
+	}
+
+	return string(decoded)
+}
+
+// validateSecretName returns an error if name breaks the provider's naming
+// rules: it must be non-empty, at most maxSecretNameLength bytes and free of
+// NUL characters, which Postgres text can't hold.
+func validateSecretName(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("secret name must not be empty")
+	}
+	if len(name) > maxSecretNameLength {
+		return fmt.Errorf("secret name is %d bytes long, the maximum is %d", len(name), maxSecretNameLength)
+	}
+	if strings.ContainsRune(name, 0) {
+		return fmt.Errorf("secret name must not contain NUL characters")
+	}
+
+	return nil
+}
+
+// checkAllowedName returns an error naming every pattern tried if name
+// matches none of patterns. Any name is allowed when there are no patterns.
+func checkAllowedName(name string, patterns []*regexp.Regexp) error {
+	if len(patterns) == 0 {
+		return nil
+	}
+
+	tried := make([]string, 0, len(patterns))
+	for _, pattern := range patterns {
+		if pattern.MatchString(name) {
+			return nil
+		}
+		tried = append(tried, fmt.Sprintf("%q", pattern.String()))
+	}
+
+	return fmt.Errorf("secret name %q doesn't match any of the provider's allowed_name_patterns: %s", name, strings.Join(tried, ", "))
+}
@@ -0,0 +1,59 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &SecretNameFunction{}
+
+func NewSecretNameFunction() function.Function {
+	return &SecretNameFunction{}
+}
+
+// SecretNameFunction builds a secret name from its components.
+type SecretNameFunction struct{}
+
+func (f *SecretNameFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "secret_name"
+}
+
+func (f *SecretNameFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Build a secret name from components",
+		MarkdownDescription: "Joins name components with `_`, e.g. `secret_name(\"prod\", \"stripe\", \"key\")` returns `prod_stripe_key`, the separator `environment_naming = \"prefix\"` also uses. " +
+			"Components are trimmed of surrounding whitespace and must not be empty, and the result is checked against the naming rules of `supabase-vault_secret`. " +
+			"The provider's `allowed_name_patterns` aren't applied, as functions don't see the provider configuration. No database connection is used.",
+		VariadicParameter: function.StringParameter{
+			Name:                "parts",
+			MarkdownDescription: "Name components, in order",
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *SecretNameFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var parts []string
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &parts))
+	if resp.Error != nil {
+		return
+	}
+
+	name, position, err := joinSecretName(parts)
+	if err != nil && position >= 0 {
+		resp.Error = function.NewArgumentFuncError(int64(position), err.Error())
+		return
+	}
+	if err != nil {
+		resp.Error = function.NewFuncError(err.Error())
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, name))
+}
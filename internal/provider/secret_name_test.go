@@ -0,0 +1,185 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestEncodeSecretName(t *testing.T) {
+	invalid := "api\xff\xfekey"
+
+	testCases := map[string]struct {
+		name             string
+		allowInvalidUTF8 bool
+		expected         string
+		expectErr        bool
+	}{
+		"valid ascii": {
+			name:     "api_key",
+			expected: "api_key",
+		},
+		"valid multibyte": {
+			name:     "clé_api",
+			expected: "clé_api",
+		},
+		"invalid rejected": {
+			name:      invalid,
+			expectErr: true,
+		},
+		"truncated sequence rejected": {
+			name:      "api\xe2\x82",
+			expectErr: true,
+		},
+		"invalid wrapped": {
+			name:             invalid,
+			allowInvalidUTF8: true,
+			expected:         "base64:YXBp//5rZXk=",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			encoded, err := encodeSecretName(testCase.name, testCase.allowInvalidUTF8)
+
+			if testCase.expectErr {
+				if err == nil {
+					t.Fatalf("expected error, got %q", encoded)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if encoded != testCase.expected {
+				t.Errorf("expected %q, got %q", testCase.expected, encoded)
+			}
+		})
+	}
+}
+
+func TestDecodeSecretName(t *testing.T) {
+	invalid := "api\xff\xfekey"
+
+	encoded, err := encodeSecretName(invalid, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if decoded := decodeSecretName(encoded, true); decoded != invalid {
+		t.Errorf("expected round trip to %q, got %q", invalid, decoded)
+	}
+
+	// Valid names that happen to look wrapped are left alone
+	if decoded := decodeSecretName("base64:YXBpX2tleQ==", true); decoded != "base64:YXBpX2tleQ==" {
+		t.Errorf("expected valid UTF-8 payload to be left wrapped, got %q", decoded)
+	}
+	if decoded := decodeSecretName(encoded, false); decoded != encoded {
+		t.Errorf("expected no decoding when disabled, got %q", decoded)
+	}
+}
+
+func TestCheckAllowedName(t *testing.T) {
+	patterns := []*regexp.Regexp{
+		regexp.MustCompile(`^[A-Z][A-Z0-9_]+$`),
+		regexp.MustCompile(`^legacy-`),
+	}
+
+	testCases := map[string]struct {
+		name      string
+		patterns  []*regexp.Regexp
+		expectErr bool
+	}{
+		"no patterns":    {name: "anything goes"},
+		"first pattern":  {name: "API_KEY", patterns: patterns},
+		"second pattern": {name: "legacy-api-key", patterns: patterns},
+		"no match":       {name: "api_key", patterns: patterns, expectErr: true},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			err := checkAllowedName(testCase.name, testCase.patterns)
+
+			if testCase.expectErr {
+				if err == nil {
+					t.Fatalf("expected error for %q, got none", testCase.name)
+				}
+				// The diagnostic names every pattern that was tried
+				for _, pattern := range testCase.patterns {
+					if !strings.Contains(err.Error(), pattern.String()) {
+						t.Errorf("expected error to mention pattern %q, got: %s", pattern, err)
+					}
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error for %q: %s", testCase.name, err)
+			}
+		})
+	}
+}
+
+func TestJoinSecretName(t *testing.T) {
+	testCases := map[string]struct {
+		parts            []string
+		expected         string
+		expectedPosition int
+		expectErr        bool
+	}{
+		"components": {
+			parts:    []string{"prod", "stripe", "key"},
+			expected: "prod_stripe_key",
+		},
+		"single component": {
+			parts:    []string{"api_key"},
+			expected: "api_key",
+		},
+		"trimmed": {
+			parts:    []string{" prod", "stripe\n"},
+			expected: "prod_stripe",
+		},
+		"no components": {
+			expectedPosition: -1,
+			expectErr:        true,
+		},
+		"empty component": {
+			parts:            []string{"prod", " ", "key"},
+			expectedPosition: 1,
+			expectErr:        true,
+		},
+		"too long": {
+			parts:            []string{"prod", strings.Repeat("a", maxSecretNameLength)},
+			expectedPosition: -1,
+			expectErr:        true,
+		},
+		"NUL character": {
+			parts:            []string{"prod", "api\x00key"},
+			expectedPosition: -1,
+			expectErr:        true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			joined, position, err := joinSecretName(testCase.parts)
+
+			if testCase.expectErr {
+				if err == nil {
+					t.Fatalf("expected error, got %q", joined)
+				}
+				if position != testCase.expectedPosition {
+					t.Errorf("expected position %d, got %d", testCase.expectedPosition, position)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if joined != testCase.expected {
+				t.Errorf("expected %q, got %q", testCase.expected, joined)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// secretsFile is a parsed manifest of secrets.
+type secretsFile struct {
+	// Secrets holds the secret values keyed by name.
+	Secrets map[string]string
+
+	// Hash is the SHA-256 hex digest of the file's content.
+	Hash string
+}
+
+// loadSecretsFile reads and parses the JSON or YAML manifest at path.
+func loadSecretsFile(path string) (secretsFile, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return secretsFile{}, fmt.Errorf("reading secrets file: %w", err)
+	}
+
+	secrets, err := parseSecretsFile(path, content)
+	if err != nil {
+		return secretsFile{}, err
+	}
+
+	sum := sha256.Sum256(content)
+
+	return secretsFile{Secrets: secrets, Hash: hex.EncodeToString(sum[:])}, nil
+}
+
+// parseSecretsFile parses content as a flat object of secret names to
+// string values, as JSON or YAML depending on the extension of path. YAML
+// scalars such as numbers are read as strings; null values and nested
+// objects are rejected.
+func parseSecretsFile(path string, content []byte) (map[string]string, error) {
+	var values map[string]*string
+
+	switch extension := strings.ToLower(filepath.Ext(path)); extension {
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(content))
+		if err := decoder.Decode(&values); err != nil {
+			return nil, fmt.Errorf("parsing %s as JSON: %w", filepath.Base(path), err)
+		}
+		if decoder.More() {
+			return nil, fmt.Errorf("parsing %s as JSON: unexpected data after the top-level object", filepath.Base(path))
+		}
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(content, &values); err != nil {
+			return nil, fmt.Errorf("parsing %s as YAML: %w", filepath.Base(path), err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported secrets file extension %q, must be .json, .yaml or .yml", extension)
+	}
+
+	secrets := make(map[string]string, len(values))
+	for name, value := range values {
+		if value == nil {
+			return nil, fmt.Errorf("secret %q in %s has no value", name, filepath.Base(path))
+		}
+		secrets[name] = *value
+	}
+
+	return secrets, nil
+}
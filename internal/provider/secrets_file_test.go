@@ -0,0 +1,115 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseSecretsFile(t *testing.T) {
+	testCases := map[string]struct {
+		path        string
+		content     string
+		expected    map[string]string
+		expectError bool
+	}{
+		"json": {
+			path:     "secrets.json",
+			content:  `{"api_key": "s3cr3t", "db_password": "hunter2"}`,
+			expected: map[string]string{"api_key": "s3cr3t", "db_password": "hunter2"},
+		},
+		"json with a number": {
+			path:        "secrets.json",
+			content:     `{"port": 5432}`,
+			expectError: true,
+		},
+		"json with trailing data": {
+			path:        "secrets.json",
+			content:     `{"api_key": "s3cr3t"} {}`,
+			expectError: true,
+		},
+		"yaml": {
+			path:     "secrets.yaml",
+			content:  "api_key: s3cr3t\ndb_password: hunter2\n",
+			expected: map[string]string{"api_key": "s3cr3t", "db_password": "hunter2"},
+		},
+		"yml with a number": {
+			path:     "secrets.yml",
+			content:  "port: 5432\n",
+			expected: map[string]string{"port": "5432"},
+		},
+		"yaml with a null value": {
+			path:        "secrets.yaml",
+			content:     "api_key: ~\n",
+			expectError: true,
+		},
+		"yaml with a nested object": {
+			path:        "secrets.yaml",
+			content:     "api:\n  key: s3cr3t\n",
+			expectError: true,
+		},
+		"yaml with a duplicate name": {
+			path:        "secrets.yaml",
+			content:     "api_key: one\napi_key: two\n",
+			expectError: true,
+		},
+		"empty yaml": {
+			path:     "secrets.yaml",
+			content:  "",
+			expected: map[string]string{},
+		},
+		"unsupported extension": {
+			path:        "secrets.toml",
+			content:     `api_key = "s3cr3t"`,
+			expectError: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			secrets, err := parseSecretsFile(testCase.path, []byte(testCase.content))
+
+			if testCase.expectError {
+				if err == nil {
+					t.Fatalf("expected an error, got: %v", secrets)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if !reflect.DeepEqual(secrets, testCase.expected) {
+				t.Errorf("expected %v, got: %v", testCase.expected, secrets)
+			}
+		})
+	}
+}
+
+func TestLoadSecretsFileHash(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.json")
+
+	if err := os.WriteFile(path, []byte(`{"api_key": "one"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	first, err := loadSecretsFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"api_key": "two"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	second, err := loadSecretsFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if first.Hash == second.Hash {
+		t.Error("expected the hash to change with the file's content")
+	}
+}
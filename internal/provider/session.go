@@ -0,0 +1,170 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/jackc/pgx/v5"
+)
+
+// sessionSQLStatementPattern matches the statements session_sql accepts:
+// SET of a single setting, optionally SESSION scoped, to a value. Postgres
+// only accepts literals, identifiers and DEFAULT as SET values, so nothing
+// else can run through them.
+var sessionSQLStatementPattern = regexp.MustCompile(`(?is)^SET\s+(?:SESSION\s+)?([a-z_][a-z0-9_$]*(?:\.[a-z_][a-z0-9_$]*)?)\s*(?:=|\sTO\s)`)
+
+// sessionSQLDeniedSettings are the settings session_sql refuses to set,
+// as they change the privileges the provider runs with or, like
+// session_replication_role, skip the trigger encrypting secrets.
+var sessionSQLDeniedSettings = map[string]bool{
+	"role":                     true,
+	"session_authorization":    true,
+	"session_replication_role": true,
+}
+
+// validateSessionLabel returns an error if label can't be used as a session
+// label. Postgres truncates application_name to the identifier length, after
+// which sessions would no longer match the label.
+func validateSessionLabel(label string) error {
+	if label == "" {
+		return fmt.Errorf("session label must not be empty")
+	}
+	if len(label) > maxIdentifierLength {
+		return fmt.Errorf("session label %q is longer than %d bytes", label, maxIdentifierLength)
+	}
+	for _, r := range label {
+		if r < 0x20 || r > 0x7e {
+			return fmt.Errorf("session label %q must only contain printable ASCII characters", label)
+		}
+	}
+
+	return nil
+}
+
+// validateSessionStatement returns an error if statement can't be run as
+// session_sql: it must be a single SET of a setting that isn't one of
+// sessionSQLDeniedSettings, optionally preceded by comments.
+func validateSessionStatement(statement string) error {
+	trimmed := strings.TrimSuffix(strings.TrimSpace(statement), ";")
+	if trimmed == "" {
+		return fmt.Errorf("session_sql statement must not be empty")
+	}
+	if strings.Contains(trimmed, ";") {
+		return fmt.Errorf("session_sql statement %q must be a single statement without semicolons", statement)
+	}
+
+	match := sessionSQLStatementPattern.FindStringSubmatch(skipLeadingComments(trimmed))
+	if match == nil {
+		return fmt.Errorf("session_sql statement %q must be of the form SET <setting> = <value> or SET <setting> TO <value>", statement)
+	}
+	if setting := strings.ToLower(match[1]); sessionSQLDeniedSettings[setting] {
+		return fmt.Errorf("session_sql statement %q must not set %s", statement, setting)
+	}
+
+	return nil
+}
+
+// skipLeadingComments returns statement without the comments and white
+// space it starts with.
+func skipLeadingComments(statement string) string {
+	for {
+		statement = strings.TrimSpace(statement)
+
+		switch {
+		case strings.HasPrefix(statement, "--"):
+			_, statement, _ = strings.Cut(statement, "\n")
+		case strings.HasPrefix(statement, "/*"):
+			_, statement, _ = strings.Cut(statement, "*/")
+		default:
+			return statement
+		}
+	}
+}
+
+// sessionSQLAfterConnect returns an AfterConnect hook running statements in
+// order on every new connection, nil when there are none. A failing
+// statement fails the connection, so no session runs without its setup.
+func sessionSQLAfterConnect(statements []string) func(context.Context, *pgx.Conn) error {
+	if len(statements) == 0 {
+		return nil
+	}
+
+	return func(ctx context.Context, conn *pgx.Conn) error {
+		for i, statement := range statements {
+			if _, err := conn.Exec(ctx, statement, pgx.QueryExecModeSimpleProtocol); err != nil {
+				tflog.Error(ctx, "A session_sql statement failed on a new connection", map[string]interface{}{
+					"index":     i,
+					"statement": statement,
+					"pid":       conn.PgConn().PID(),
+					"error":     err.Error(),
+				})
+				return fmt.Errorf("running session_sql[%d] %q: %w", i, statement, err)
+			}
+		}
+
+		return nil
+	}
+}
+
+// chainAfterConnect returns an AfterConnect hook running first and then
+// next, either of which may be nil.
+func chainAfterConnect(first, next func(context.Context, *pgx.Conn) error) func(context.Context, *pgx.Conn) error {
+	if first == nil {
+		return next
+	}
+	if next == nil {
+		return first
+	}
+
+	return func(ctx context.Context, conn *pgx.Conn) error {
+		if err := first(ctx, conn); err != nil {
+			return err
+		}
+
+		return next(ctx, conn)
+	}
+}
+
+// trackSession records the backend PID of a connection opened by this
+// provider's pool.
+func (d *ProviderData) trackSession(pid uint32) {
+	d.sessions.Store(pid, struct{}{})
+}
+
+// untrackSession forgets a connection closed by this provider's pool.
+func (d *ProviderData) untrackSession(pid uint32) {
+	d.sessions.Delete(pid)
+}
+
+// trackConnection is an AfterConnect hook recording conn as one of the
+// provider's own.
+func (d *ProviderData) trackConnection(ctx context.Context, conn *pgx.Conn) error {
+	d.trackSession(conn.PgConn().PID())
+	return nil
+}
+
+// untrackConnection is a BeforeClose hook forgetting conn.
+func (d *ProviderData) untrackConnection(conn *pgx.Conn) {
+	d.untrackSession(conn.PgConn().PID())
+}
+
+// ownSessionPIDs returns the backend PIDs of the connections this provider's
+// pools currently hold, override pools included, which session cleanup must
+// never terminate.
+func (d *ProviderData) ownSessionPIDs() []int32 {
+	// Never nil: a NULL array would make "pid <> ALL(...)" match nothing
+	pids := []int32{}
+
+	d.sessions.Range(func(key, value any) bool {
+		pids = append(pids, int32(key.(uint32)))
+		return true
+	})
+
+	return pids
+}
@@ -0,0 +1,244 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &SessionCleanupResource{}
+var _ resource.ResourceWithValidateConfig = &SessionCleanupResource{}
+
+// defaultSessionCleanupMinIdle is how long a session must have been idle
+// before cleanup terminates it, unless min_idle is set.
+const defaultSessionCleanupMinIdle = 5 * time.Minute
+
+// terminateStaleSessionsQuery terminates the idle sessions labelled $1 that
+// are neither this connection nor in the $2 array of the provider's own, and
+// that haven't changed state for $3 seconds. Sessions whose state the
+// connecting role may not see are never terminated.
+const terminateStaleSessionsQuery = `
+	SELECT count(*) FILTER (WHERE pg_terminate_backend(pid))
+	FROM pg_stat_activity
+	WHERE application_name = $1
+	  AND pid <> pg_backend_pid()
+	  AND pid <> ALL($2::int4[])
+	  AND state IN ('idle', 'idle in transaction', 'idle in transaction (aborted)')
+	  AND state_change < now() - make_interval(secs => $3)
+`
+
+func NewSessionCleanupResource() resource.Resource {
+	return &SessionCleanupResource{}
+}
+
+// SessionCleanupResource defines the resource implementation.
+type SessionCleanupResource struct {
+	providerData *ProviderData
+}
+
+// SessionCleanupResourceModel describes the resource data model.
+type SessionCleanupResourceModel struct {
+	ID         types.String `tfsdk:"id"`
+	MinIdle    types.String `tfsdk:"min_idle"`
+	Triggers   types.Map    `tfsdk:"triggers"`
+	Terminated types.Int64  `tfsdk:"terminated"`
+}
+
+// parseMinIdle parses min_idle, which must not be negative.
+func parseMinIdle(value string) (time.Duration, error) {
+	minIdle, err := time.ParseDuration(value)
+	if err != nil || minIdle < 0 {
+		return 0, fmt.Errorf("expected a duration of zero or more such as \"5m\", got: %q", value)
+	}
+
+	return minIdle, nil
+}
+
+func (r *SessionCleanupResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_session_cleanup"
+}
+
+func (r *SessionCleanupResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Terminates idle database sessions left behind by earlier runs when it is created, matched by the provider's `session_label` as their `application_name`. " +
+			"Change `triggers` to run the cleanup again. Connections held by the running provider, including those opened for connection overrides, are never terminated, " +
+			"and sessions of concurrent runs sharing the label are spared as long as they were active within `min_idle`. " +
+			"Requires the provider's `session_label` and is refused by a `read_only` provider. The connecting role needs permission to signal the sessions, e.g. membership in `pg_signal_backend`, " +
+			"and to see their state, e.g. membership in `pg_read_all_stats`. Destroying this resource does nothing.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Session label the cleanup matched",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"min_idle": schema.StringAttribute{
+				MarkdownDescription: "Go duration string (e.g. `15m`) a session must have been idle for to be terminated. Defaults to `5m`.",
+				Optional:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"triggers": schema.MapAttribute{
+				MarkdownDescription: "Arbitrary values that run the cleanup again when they change, e.g. `{ run = timestamp() }` to clean up on every apply.",
+				ElementType:         types.StringType,
+				Optional:            true,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+			"terminated": schema.Int64Attribute{
+				MarkdownDescription: "Number of sessions terminated",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (r *SessionCleanupResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.providerData = providerData
+}
+
+func (r *SessionCleanupResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data SessionCleanupResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if isKnown(data.MinIdle) {
+		if _, err := parseMinIdle(data.MinIdle.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("min_idle"),
+				"Invalid min_idle",
+				err.Error(),
+			)
+		}
+	}
+}
+
+func (r *SessionCleanupResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.providerData.logContext(ctx)
+
+	release, ok := r.providerData.beginOperation(ctx, &resp.Diagnostics)
+	if !ok {
+		return
+	}
+	defer release()
+
+	if !r.providerData.checkWritable(&resp.Diagnostics, "terminate database sessions") {
+		return
+	}
+
+	var data SessionCleanupResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Only the provider's own label guarantees its connections are tracked
+	// and spared
+	label := r.providerData.SessionLabel
+	if label == "" {
+		resp.Diagnostics.AddError(
+			"Missing session label",
+			"Session cleanup only terminates sessions labelled with the provider's session_label. Set session_label in the provider configuration.",
+		)
+		return
+	}
+
+	minIdle := defaultSessionCleanupMinIdle
+	if !data.MinIdle.IsNull() {
+		var err error
+		minIdle, err = parseMinIdle(data.MinIdle.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("min_idle"),
+				"Invalid min_idle",
+				err.Error(),
+			)
+			return
+		}
+	}
+
+	var terminated int64
+	err := r.providerData.queryRow(ctx, terminateStaleSessionsQuery, label, r.providerData.ownSessionPIDs(), minIdle.Seconds()).Scan(&terminated)
+
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to terminate sessions",
+			withRemediation(fmt.Sprintf("Error terminating sessions labelled %q: %s", label, err), err),
+		)
+		return
+	}
+
+	data.ID = types.StringValue(label)
+	data.Terminated = types.Int64Value(terminated)
+
+	tflog.Debug(ctx, "terminated stale sessions", map[string]interface{}{
+		"label":      label,
+		"min_idle":   minIdle.String(),
+		"terminated": terminated,
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SessionCleanupResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data SessionCleanupResourceModel
+
+	// The cleanup only acts on create, so there is nothing to refresh
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SessionCleanupResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data SessionCleanupResourceModel
+
+	// Every configurable attribute requires replacement, so there is nothing
+	// to change in the database
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SessionCleanupResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Terminated sessions can't be restored, so removing the resource from
+	// state is all there is to do
+}
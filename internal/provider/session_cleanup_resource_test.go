@@ -0,0 +1,94 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestParseMinIdle(t *testing.T) {
+	testCases := map[string]struct {
+		value     string
+		expected  time.Duration
+		expectErr bool
+	}{
+		"minutes":  {value: "15m", expected: 15 * time.Minute},
+		"zero":     {value: "0s", expected: 0},
+		"negative": {value: "-1m", expectErr: true},
+		"no unit":  {value: "15", expectErr: true},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			minIdle, err := parseMinIdle(testCase.value)
+
+			if testCase.expectErr {
+				if err == nil {
+					t.Fatalf("expected error for %q, got none", testCase.value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error for %q: %s", testCase.value, err)
+			}
+			if minIdle != testCase.expected {
+				t.Errorf("expected %s, got %s", testCase.expected, minIdle)
+			}
+		})
+	}
+}
+
+func TestAccSessionCleanupResource(t *testing.T) {
+	// Skip if TF_ACC is not set
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests skipped unless env 'TF_ACC' set")
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Cleanup only matches the provider's own session label
+			{
+				Config: testAccProviderConfig() + `
+resource "supabase-vault_session_cleanup" "test" {}
+`,
+				ExpectError: regexp.MustCompile(`Missing session label`),
+			},
+			// Even with no idle time required, the provider's own sessions
+			// are spared
+			{
+				Config: testAccSessionCleanupResourceConfig("terraform-provider-supabase-vault-acc-cleanup"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"supabase-vault_session_cleanup.test",
+						tfjsonpath.New("terminated"),
+						knownvalue.Int64Exact(0),
+					),
+				},
+			},
+		},
+	})
+}
+
+// testAccSessionCleanupResourceConfig returns a provider block labelling its
+// sessions with label and a cleanup of its stale sessions.
+func testAccSessionCleanupResourceConfig(label string) string {
+	return strings.TrimSuffix(testAccProviderConfig(), "}\n") + `  session_label = "` + label + `"
+}
+
+resource "supabase-vault_session_cleanup" "test" {
+  min_idle = "0s"
+}
+`
+}
@@ -0,0 +1,139 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func TestValidateSessionLabel(t *testing.T) {
+	testCases := map[string]struct {
+		label     string
+		expectErr bool
+	}{
+		"simple":    {label: "terraform-ci"},
+		"with run":  {label: "terraform-ci/run-1234"},
+		"empty":     {label: "", expectErr: true},
+		"too long":  {label: strings.Repeat("a", maxIdentifierLength+1), expectErr: true},
+		"non-ASCII": {label: "terraform-ç", expectErr: true},
+		"newline":   {label: "terraform\nci", expectErr: true},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			err := validateSessionLabel(testCase.label)
+
+			if testCase.expectErr && err == nil {
+				t.Fatalf("expected error for %q, got none", testCase.label)
+			}
+			if !testCase.expectErr && err != nil {
+				t.Fatalf("unexpected error for %q: %s", testCase.label, err)
+			}
+		})
+	}
+}
+
+func TestValidateSessionStatement(t *testing.T) {
+	testCases := map[string]struct {
+		statement string
+		expectErr bool
+	}{
+		"set":                   {statement: "SET statement_timeout = '30s'"},
+		"set to":                {statement: "SET lock_timeout TO 5000"},
+		"trailing semicolon":    {statement: "SET search_path = vault, public;"},
+		"lower case":            {statement: "set lock_timeout = '5s'"},
+		"session":               {statement: "SET SESSION idle_in_transaction_session_timeout = '1min'"},
+		"custom setting":        {statement: "SET app.team = 'platform'"},
+		"after comment":         {statement: "/* timeouts */ SET statement_timeout = '30s'"},
+		"empty":                 {statement: " ; ", expectErr: true},
+		"two statements":        {statement: "SET statement_timeout = 0; DROP TABLE vault.secrets", expectErr: true},
+		"select":                {statement: "SELECT set_config('app.team', 'platform', false)", expectErr: true},
+		"volatile function":     {statement: "SELECT vault.create_secret('value')", expectErr: true},
+		"set role":              {statement: "SET ROLE postgres", expectErr: true},
+		"set role equals":       {statement: "SET role = postgres", expectErr: true},
+		"set session role":      {statement: "SET SESSION ROLE postgres", expectErr: true},
+		"session authorization": {statement: "SET SESSION AUTHORIZATION postgres", expectErr: true},
+		"authorization setting": {statement: "SET session_authorization = postgres", expectErr: true},
+		"replication role":      {statement: "SET session_replication_role = replica", expectErr: true},
+		"set local":             {statement: "SET LOCAL statement_timeout = '30s'", expectErr: true},
+		"quoted setting":        {statement: "SET \"role\" = postgres", expectErr: true},
+		"drop":                  {statement: "DROP TABLE vault.secrets", expectErr: true},
+		"begin":                 {statement: "BEGIN", expectErr: true},
+		"after line comment":    {statement: "-- cleanup\nTRUNCATE vault.secrets", expectErr: true},
+		"after block comment":   {statement: "/* cleanup */ TRUNCATE vault.secrets", expectErr: true},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			err := validateSessionStatement(testCase.statement)
+
+			if testCase.expectErr && err == nil {
+				t.Fatalf("expected error for %q, got none", testCase.statement)
+			}
+			if !testCase.expectErr && err != nil {
+				t.Fatalf("unexpected error for %q: %s", testCase.statement, err)
+			}
+		})
+	}
+}
+
+func TestSessionSQLAfterConnectWithoutStatements(t *testing.T) {
+	if sessionSQLAfterConnect(nil) != nil {
+		t.Error("expected no hook without statements")
+	}
+}
+
+func TestChainAfterConnect(t *testing.T) {
+	var calls []string
+	hook := func(name string, err error) func(context.Context, *pgx.Conn) error {
+		return func(ctx context.Context, conn *pgx.Conn) error {
+			calls = append(calls, name)
+			return err
+		}
+	}
+
+	if err := chainAfterConnect(hook("session_sql", nil), hook("track", nil))(t.Context(), nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if expected := []string{"session_sql", "track"}; !reflect.DeepEqual(calls, expected) {
+		t.Errorf("expected calls %v, got %v", expected, calls)
+	}
+
+	// A failed setup must not track the connection
+	calls = nil
+	failed := errors.New("permission denied")
+	if err := chainAfterConnect(hook("session_sql", failed), hook("track", nil))(t.Context(), nil); !errors.Is(err, failed) {
+		t.Fatalf("expected %v, got %v", failed, err)
+	}
+	if expected := []string{"session_sql"}; !reflect.DeepEqual(calls, expected) {
+		t.Errorf("expected calls %v, got %v", expected, calls)
+	}
+
+	if chainAfterConnect(nil, nil) != nil {
+		t.Error("expected no hook when chaining none")
+	}
+}
+
+func TestOwnSessionPIDs(t *testing.T) {
+	data := &ProviderData{}
+
+	if pids := data.ownSessionPIDs(); pids == nil || len(pids) != 0 {
+		t.Fatalf("expected an empty, non-nil slice, got %#v", pids)
+	}
+
+	data.trackSession(101)
+	data.trackSession(102)
+	data.untrackSession(101)
+
+	pids := data.ownSessionPIDs()
+	if len(pids) != 1 || pids[0] != 102 {
+		t.Errorf("expected only PID 102 to be tracked, got %v", pids)
+	}
+}
@@ -0,0 +1,268 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/jackc/pgx/v5"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &SessionsDataSource{}
+
+func NewSessionsDataSource() datasource.DataSource {
+	return &SessionsDataSource{}
+}
+
+// SessionsDataSource defines the data source implementation.
+type SessionsDataSource struct {
+	providerData *ProviderData
+}
+
+// SessionsDataSourceModel describes the data source data model.
+type SessionsDataSourceModel struct {
+	ApplicationName   types.String   `tfsdk:"application_name"`
+	Sessions          []SessionModel `tfsdk:"sessions"`
+	Total             types.Int64    `tfsdk:"total"`
+	Active            types.Int64    `tfsdk:"active"`
+	Idle              types.Int64    `tfsdk:"idle"`
+	IdleInTransaction types.Int64    `tfsdk:"idle_in_transaction"`
+	Hidden            types.Int64    `tfsdk:"hidden"`
+}
+
+// SessionModel describes a database session.
+type SessionModel struct {
+	PID          types.Int64  `tfsdk:"pid"`
+	State        types.String `tfsdk:"state"`
+	BackendStart types.String `tfsdk:"backend_start"`
+	StateChange  types.String `tfsdk:"state_change"`
+}
+
+// sessionRow is a row of pg_stat_activity. state is NULL for sessions of
+// other roles unless the connecting role may read all statistics.
+type sessionRow struct {
+	PID          int32      `db:"pid"`
+	State        *string    `db:"state"`
+	BackendStart *time.Time `db:"backend_start"`
+	StateChange  *time.Time `db:"state_change"`
+}
+
+const listSessionsQuery = `
+	SELECT pid, state, backend_start, state_change
+	FROM pg_stat_activity
+	WHERE application_name = $1
+	ORDER BY backend_start, pid
+`
+
+// sessionCounts are the number of sessions in each state of interest.
+type sessionCounts struct {
+	active, idle, idleInTransaction, hidden int64
+}
+
+// countSessions counts rows by state. Sessions whose state can't be seen
+// are counted as hidden.
+func countSessions(rows []sessionRow) sessionCounts {
+	var counts sessionCounts
+
+	for _, row := range rows {
+		if row.State == nil {
+			counts.hidden++
+			continue
+		}
+
+		switch *row.State {
+		case "active":
+			counts.active++
+		case "idle":
+			counts.idle++
+		case "idle in transaction", "idle in transaction (aborted)":
+			counts.idleInTransaction++
+		}
+	}
+
+	return counts
+}
+
+// applicationName returns the application_name the provider's connections
+// report, empty when none is set.
+func (d *ProviderData) applicationName() string {
+	if d.poolConfig == nil {
+		return ""
+	}
+
+	return d.poolConfig.ConnConfig.RuntimeParams["application_name"]
+}
+
+func (d *SessionsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_sessions"
+}
+
+func (d *SessionsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists the database sessions of the provider, matched by `application_name` in `pg_stat_activity`, to troubleshoot connection exhaustion. " +
+			"The count includes the connection running the query. The state of other roles' sessions is only visible to roles with `pg_read_all_stats`.",
+
+		Attributes: map[string]schema.Attribute{
+			"application_name": schema.StringAttribute{
+				MarkdownDescription: "Application name to match. Defaults to the `application_name` the provider's connections report.",
+				Optional:            true,
+				Computed:            true,
+			},
+			"sessions": schema.ListNestedAttribute{
+				MarkdownDescription: "Matching sessions, oldest first",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"pid": schema.Int64Attribute{
+							MarkdownDescription: "Backend process ID",
+							Computed:            true,
+						},
+						"state": schema.StringAttribute{
+							MarkdownDescription: "Session state, e.g. `active`, `idle` or `idle in transaction`. Null when the connecting role may not see it.",
+							Computed:            true,
+						},
+						"backend_start": schema.StringAttribute{
+							MarkdownDescription: "When the session was opened, as an RFC 3339 timestamp in the provider's `timestamp_timezone`",
+							Computed:            true,
+						},
+						"state_change": schema.StringAttribute{
+							MarkdownDescription: "When the session last changed state, as an RFC 3339 timestamp in the provider's `timestamp_timezone`",
+							Computed:            true,
+						},
+					},
+				},
+			},
+			"total": schema.Int64Attribute{
+				MarkdownDescription: "Number of matching sessions",
+				Computed:            true,
+			},
+			"active": schema.Int64Attribute{
+				MarkdownDescription: "Number of sessions running a statement",
+				Computed:            true,
+			},
+			"idle": schema.Int64Attribute{
+				MarkdownDescription: "Number of idle sessions",
+				Computed:            true,
+			},
+			"idle_in_transaction": schema.Int64Attribute{
+				MarkdownDescription: "Number of sessions idle inside a transaction, including aborted ones",
+				Computed:            true,
+			},
+			"hidden": schema.Int64Attribute{
+				MarkdownDescription: "Number of sessions whose state the connecting role may not see",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *SessionsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.providerData = providerData
+}
+
+func (d *SessionsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data SessionsDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	applicationName := d.providerData.applicationName()
+	if !data.ApplicationName.IsNull() {
+		applicationName = data.ApplicationName.ValueString()
+	}
+
+	if applicationName == "" {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("application_name"),
+			"Missing application name",
+			"The provider's connections don't report an application_name, so set application_name to the sessions to list.",
+		)
+		return
+	}
+
+	rows, err := collectRows(ctx, d.providerData, pgx.RowToStructByName[sessionRow], listSessionsQuery, applicationName)
+	if err != nil && isInsufficientPrivilegeError(err) {
+		resp.Diagnostics.AddError(
+			"Unable to list sessions",
+			fmt.Sprintf("The connecting role may not read pg_stat_activity: %s. Grant it SELECT on pg_stat_activity, or membership in pg_read_all_stats.", err),
+		)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to list sessions",
+			withRemediation(fmt.Sprintf("Error listing sessions with application_name %q: %s", applicationName, err), err),
+		)
+		return
+	}
+
+	data.ApplicationName = types.StringValue(applicationName)
+	data.Sessions = make([]SessionModel, 0, len(rows))
+	for _, row := range rows {
+		session := SessionModel{
+			PID:          types.Int64Value(int64(row.PID)),
+			State:        types.StringPointerValue(row.State),
+			BackendStart: types.StringNull(),
+			StateChange:  types.StringNull(),
+		}
+		if row.BackendStart != nil {
+			session.BackendStart = d.providerData.timestampValue(*row.BackendStart)
+		}
+		if row.StateChange != nil {
+			session.StateChange = d.providerData.timestampValue(*row.StateChange)
+		}
+		data.Sessions = append(data.Sessions, session)
+	}
+
+	counts := countSessions(rows)
+	data.Total = types.Int64Value(int64(len(rows)))
+	data.Active = types.Int64Value(counts.active)
+	data.Idle = types.Int64Value(counts.idle)
+	data.IdleInTransaction = types.Int64Value(counts.idleInTransaction)
+	data.Hidden = types.Int64Value(counts.hidden)
+
+	if counts.hidden > 0 {
+		resp.Diagnostics.AddWarning(
+			"Some session states are hidden",
+			fmt.Sprintf("The connecting role may not see the state of %d of the %d sessions. Grant it membership in pg_read_all_stats to see them.", counts.hidden, len(rows)),
+		)
+	}
+
+	tflog.Debug(ctx, "listed sessions", map[string]interface{}{
+		"application_name": applicationName,
+		"total":            len(rows),
+		"hidden":           counts.hidden,
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
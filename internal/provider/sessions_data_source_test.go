@@ -0,0 +1,68 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestAccSessionsDataSource(t *testing.T) {
+	// Skip if TF_ACC is not set
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests skipped unless env 'TF_ACC' set")
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// The connection running the query is one of the provider's own
+			{
+				Config: testAccProviderConfig() + `
+data "supabase-vault_sessions" "test" {}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.supabase-vault_sessions.test",
+						tfjsonpath.New("active"),
+						knownvalue.Int64Func(func(v int64) error {
+							if v < 1 {
+								return fmt.Errorf("expected at least one active session, got %d", v)
+							}
+							return nil
+						}),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestCountSessions(t *testing.T) {
+	state := func(state string) *string {
+		return &state
+	}
+
+	rows := []sessionRow{
+		{PID: 1, State: state("active")},
+		{PID: 2, State: state("idle")},
+		{PID: 3, State: state("idle")},
+		{PID: 4, State: state("idle in transaction")},
+		{PID: 5, State: state("idle in transaction (aborted)")},
+		{PID: 6, State: state("fastpath function call")},
+		{PID: 7},
+	}
+
+	expected := sessionCounts{active: 1, idle: 2, idleInTransaction: 2, hidden: 1}
+	if counts := countSessions(rows); counts != expected {
+		t.Errorf("expected %+v, got %+v", expected, counts)
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the instrumentation scope of the provider's spans.
+const tracerName = "github.com/TheCodedCloud/terraform-provider-supabase-vault"
+
+// tracingServiceName is the default service.name of exported spans,
+// overridable with OTEL_SERVICE_NAME.
+const tracingServiceName = "terraform-provider-supabase-vault"
+
+// tracingEnabled reports whether the standard OpenTelemetry environment
+// variables read through getenv configure an OTLP trace exporter.
+func tracingEnabled(getenv func(string) string) bool {
+	if strings.EqualFold(getenv("OTEL_SDK_DISABLED"), "true") {
+		return false
+	}
+
+	switch exporter := getenv("OTEL_TRACES_EXPORTER"); exporter {
+	case "otlp":
+		return true
+	case "":
+		return getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" || getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != ""
+	default:
+		// "none", or an exporter the provider doesn't ship
+		return false
+	}
+}
+
+// tracingProtocol returns the OTLP protocol to export spans with, defaulting
+// to http/protobuf as the specification does.
+func tracingProtocol(getenv func(string) string) string {
+	if protocol := getenv("OTEL_EXPORTER_OTLP_TRACES_PROTOCOL"); protocol != "" {
+		return protocol
+	}
+	if protocol := getenv("OTEL_EXPORTER_OTLP_PROTOCOL"); protocol != "" {
+		return protocol
+	}
+
+	return "http/protobuf"
+}
+
+// InitTracing installs a global tracer provider that exports the provider's
+// spans over OTLP, when the standard OTEL_* environment variables configure
+// an exporter. Spans are otherwise dropped. The returned function flushes
+// any buffered spans and must be called before the process exits.
+func InitTracing(ctx context.Context, version string) (func(context.Context) error, error) {
+	noop := func(context.Context) error { return nil }
+
+	if !tracingEnabled(os.Getenv) {
+		return noop, nil
+	}
+
+	var client otlptrace.Client
+	switch protocol := tracingProtocol(os.Getenv); protocol {
+	case "grpc":
+		client = otlptracegrpc.NewClient()
+	case "http/protobuf":
+		client = otlptracehttp.NewClient()
+	default:
+		return noop, fmt.Errorf("unsupported OTLP protocol %q, must be grpc or http/protobuf", protocol)
+	}
+
+	exporter, err := otlptrace.New(ctx, client)
+	if err != nil {
+		return noop, fmt.Errorf("creating OTLP trace exporter: %w", err)
+	}
+
+	// Attributes from OTEL_RESOURCE_ATTRIBUTES and OTEL_SERVICE_NAME win
+	res, err := resource.New(ctx,
+		resource.WithTelemetrySDK(),
+		resource.WithAttributes(
+			attribute.String("service.name", tracingServiceName),
+			attribute.String("service.version", version),
+		),
+		resource.WithFromEnv(),
+	)
+	if err != nil {
+		return noop, fmt.Errorf("creating OpenTelemetry resource: %w", err)
+	}
+
+	tracerProvider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(tracerProvider)
+
+	return tracerProvider.Shutdown, nil
+}
+
+// operationSpan is a span around one provider operation.
+type operationSpan struct {
+	span  trace.Span
+	start time.Time
+}
+
+// startSpan starts a span for operation. It is a no-op unless InitTracing
+// installed an exporter.
+func startSpan(ctx context.Context, operation string, attributes ...attribute.KeyValue) (context.Context, *operationSpan) {
+	attributes = append([]attribute.KeyValue{attribute.String("supabase_vault.operation", operation)}, attributes...)
+
+	ctx, span := otel.Tracer(tracerName).Start(ctx, operation, trace.WithAttributes(attributes...))
+
+	return ctx, &operationSpan{span: span, start: time.Now()}
+}
+
+// startSpan starts a span for an operation on the named secret. The name is
+// only recorded as a hash, so spans can be correlated without revealing it,
+// and no secret value is ever recorded.
+func (d *ProviderData) startSpan(ctx context.Context, operation string, name types.String) (context.Context, *operationSpan) {
+	var attributes []attribute.KeyValue
+
+	if isKnown(name) {
+		attributes = append(attributes, attribute.String("supabase_vault.secret_name_hash", hashSecretValue(name.ValueString())))
+	}
+	if d != nil && d.CorrelationID != "" {
+		attributes = append(attributes, attribute.String("supabase_vault.correlation_id", d.CorrelationID))
+	}
+
+	return startSpan(ctx, operation, attributes...)
+}
+
+// end ends the span, marking it failed when diags has an error. Only the
+// error summaries are recorded, as details may quote configuration.
+func (s *operationSpan) end(diags *diag.Diagnostics) {
+	s.span.SetAttributes(attribute.Int64("supabase_vault.duration_ms", time.Since(s.start).Milliseconds()))
+
+	if errs := diags.Errors(); len(errs) > 0 {
+		s.span.SetStatus(codes.Error, errs[0].Summary())
+	}
+
+	s.span.End()
+}
@@ -0,0 +1,142 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracingEnabled(t *testing.T) {
+	testCases := map[string]struct {
+		env      map[string]string
+		expected bool
+	}{
+		"no environment": {
+			env: map[string]string{},
+		},
+		"endpoint": {
+			env:      map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "http://localhost:4318"},
+			expected: true,
+		},
+		"traces endpoint": {
+			env:      map[string]string{"OTEL_EXPORTER_OTLP_TRACES_ENDPOINT": "http://localhost:4318/v1/traces"},
+			expected: true,
+		},
+		"otlp exporter": {
+			env:      map[string]string{"OTEL_TRACES_EXPORTER": "otlp"},
+			expected: true,
+		},
+		"none exporter": {
+			env: map[string]string{
+				"OTEL_TRACES_EXPORTER":        "none",
+				"OTEL_EXPORTER_OTLP_ENDPOINT": "http://localhost:4318",
+			},
+		},
+		"sdk disabled": {
+			env: map[string]string{
+				"OTEL_SDK_DISABLED":           "true",
+				"OTEL_EXPORTER_OTLP_ENDPOINT": "http://localhost:4318",
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			getenv := func(key string) string { return testCase.env[key] }
+
+			if enabled := tracingEnabled(getenv); enabled != testCase.expected {
+				t.Errorf("expected %t, got: %t", testCase.expected, enabled)
+			}
+		})
+	}
+}
+
+func TestTracingProtocol(t *testing.T) {
+	testCases := map[string]struct {
+		env      map[string]string
+		expected string
+	}{
+		"default": {
+			env:      map[string]string{},
+			expected: "http/protobuf",
+		},
+		"protocol": {
+			env:      map[string]string{"OTEL_EXPORTER_OTLP_PROTOCOL": "grpc"},
+			expected: "grpc",
+		},
+		"traces protocol wins": {
+			env: map[string]string{
+				"OTEL_EXPORTER_OTLP_PROTOCOL":        "grpc",
+				"OTEL_EXPORTER_OTLP_TRACES_PROTOCOL": "http/protobuf",
+			},
+			expected: "http/protobuf",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			getenv := func(key string) string { return testCase.env[key] }
+
+			if protocol := tracingProtocol(getenv); protocol != testCase.expected {
+				t.Errorf("expected %q, got: %q", testCase.expected, protocol)
+			}
+		})
+	}
+}
+
+func TestProviderDataStartSpan(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	d := &ProviderData{CorrelationID: "run-1"}
+
+	var diags diag.Diagnostics
+	_, span := d.startSpan(context.Background(), "vault_secret.create", types.StringValue("api_key"))
+	diags.AddError("Unable to create vault secret", "Error calling vault.create_secret: boom")
+	span.end(&diags)
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got: %d", len(spans))
+	}
+
+	attributes := map[attribute.Key]attribute.Value{}
+	for _, kv := range spans[0].Attributes() {
+		attributes[kv.Key] = kv.Value
+	}
+
+	if got := attributes["supabase_vault.operation"].AsString(); got != "vault_secret.create" {
+		t.Errorf("expected operation vault_secret.create, got: %q", got)
+	}
+	if got := attributes["supabase_vault.secret_name_hash"].AsString(); got != hashSecretValue("api_key") {
+		t.Errorf("expected the hashed secret name, got: %q", got)
+	}
+	if got := attributes["supabase_vault.correlation_id"].AsString(); got != "run-1" {
+		t.Errorf("expected correlation ID run-1, got: %q", got)
+	}
+	if _, ok := attributes["supabase_vault.duration_ms"]; !ok {
+		t.Error("expected a duration attribute")
+	}
+	for key, value := range attributes {
+		if value.AsString() == "api_key" {
+			t.Errorf("expected the secret name not to be recorded, found it in %s", key)
+		}
+	}
+
+	if status := spans[0].Status(); status.Code != codes.Error || status.Description != "Unable to create vault secret" {
+		t.Errorf("expected an error status with the diagnostic summary, got: %+v", status)
+	}
+}
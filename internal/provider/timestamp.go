@@ -0,0 +1,41 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"time"
+
+	// Embed the time zone database so timestamp_timezone works on hosts
+	// without one, e.g. Windows or minimal CI images.
+	_ "time/tzdata"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// parseTimestampTimezone returns the location named by timezone, an IANA
+// time zone name such as "Europe/Berlin", "UTC" or "Local".
+func parseTimestampTimezone(timezone string) (*time.Location, error) {
+	if timezone == "" {
+		return nil, fmt.Errorf("timestamp_timezone must not be empty")
+	}
+
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("unknown time zone %q, expected an IANA name such as UTC, Local or Europe/Berlin", timezone)
+	}
+
+	return location, nil
+}
+
+// timestampValue formats t as RFC 3339 in the configured timestamp_timezone,
+// UTC when none is configured.
+func (d *ProviderData) timestampValue(t time.Time) types.String {
+	location := d.TimestampLocation
+	if location == nil {
+		location = time.UTC
+	}
+
+	return types.StringValue(t.In(location).Format(time.RFC3339))
+}
@@ -0,0 +1,64 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTimestampTimezone(t *testing.T) {
+	testCases := map[string]struct {
+		timezone  string
+		expectErr bool
+	}{
+		"utc":     {timezone: "UTC"},
+		"local":   {timezone: "Local"},
+		"iana":    {timezone: "Europe/Berlin"},
+		"empty":   {timezone: "", expectErr: true},
+		"unknown": {timezone: "Mars/Olympus_Mons", expectErr: true},
+		"offset":  {timezone: "+02:00", expectErr: true},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := parseTimestampTimezone(testCase.timezone)
+
+			if testCase.expectErr && err == nil {
+				t.Errorf("expected error for %q, got none", testCase.timezone)
+			}
+			if !testCase.expectErr && err != nil {
+				t.Errorf("unexpected error for %q: %s", testCase.timezone, err)
+			}
+		})
+	}
+}
+
+func TestTimestampValue(t *testing.T) {
+	berlin, err := parseTimestampTimezone("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	timestamp := time.Date(2025, time.June, 1, 12, 30, 0, 0, time.UTC)
+
+	testCases := map[string]struct {
+		location *time.Location
+		expected string
+	}{
+		"default": {expected: "2025-06-01T12:30:00Z"},
+		"utc":     {location: time.UTC, expected: "2025-06-01T12:30:00Z"},
+		"berlin":  {location: berlin, expected: "2025-06-01T14:30:00+02:00"},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			d := &ProviderData{TimestampLocation: testCase.location}
+
+			if value := d.timestampValue(timestamp).ValueString(); value != testCase.expected {
+				t.Errorf("expected %s, got %s", testCase.expected, value)
+			}
+		})
+	}
+}
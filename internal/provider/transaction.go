@@ -0,0 +1,118 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// operationTxKey is the context key of the transaction set by
+// beginOperationTx.
+type operationTxKey struct{}
+
+// operationTx is the transaction a resource operation runs in with
+// use_transactions. A nil operationTx stands for no transaction, so callers
+// don't need to check whether one was begun.
+type operationTx struct {
+	conn *pgxpool.Conn
+	tx   pgx.Tx
+	done bool
+}
+
+// beginOperationTx begins the transaction a resource operation runs in when
+// use_transactions is set, and returns ctx routing every statement run
+// through d to it. Statements that run their own transaction use a
+// savepoint instead. The caller must commit or roll back the transaction.
+func (d *ProviderData) beginOperationTx(ctx context.Context, diags *diag.Diagnostics) (context.Context, *operationTx, bool) {
+	// The Management API runs every statement in a request of its own
+	if !d.UseTransactions || d.managementAPI != nil {
+		return ctx, nil, true
+	}
+
+	conn, err := d.acquire(ctx)
+	if err != nil {
+		diags.AddError(
+			"Unable to begin transaction",
+			withRemediation(err.Error(), err),
+		)
+		return ctx, nil, false
+	}
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		conn.Release()
+		diags.AddError(
+			"Unable to begin transaction",
+			withRemediation(fmt.Sprintf("Error beginning transaction: %s", err), err),
+		)
+		return ctx, nil, false
+	}
+
+	return context.WithValue(ctx, operationTxKey{}, annotatedTx{Tx: tx, data: d}), &operationTx{conn: conn, tx: tx}, true
+}
+
+// commit commits the operation's transaction, adding an error to diags if
+// that fails.
+func (t *operationTx) commit(ctx context.Context, diags *diag.Diagnostics) bool {
+	if t == nil || t.done {
+		return true
+	}
+	t.done = true
+	defer t.conn.Release()
+
+	if err := t.tx.Commit(ctx); err != nil {
+		diags.AddError(
+			"Unable to commit transaction",
+			withRemediation(fmt.Sprintf("Error committing the operation's transaction, so none of its changes were kept: %s", err), err),
+		)
+		return false
+	}
+
+	return true
+}
+
+// rollback rolls the operation's transaction back unless it was committed.
+func (t *operationTx) rollback(ctx context.Context) {
+	if t == nil || t.done {
+		return
+	}
+	t.done = true
+	defer t.conn.Release()
+
+	// The operation's own error is more useful than any rollback failure
+	_ = t.tx.Rollback(ctx)
+}
+
+// txFromContext returns the operation transaction ctx routes statements to,
+// if any.
+func txFromContext(ctx context.Context) (pgx.Tx, bool) {
+	tx, ok := ctx.Value(operationTxKey{}).(pgx.Tx)
+	return tx, ok
+}
+
+// withSavepoint runs fn in a savepoint of tx, which is released if fn
+// succeeds and rolled back otherwise, or always when rollback is set.
+func (d *ProviderData) withSavepoint(ctx context.Context, tx pgx.Tx, rollback bool, fn func(tx pgx.Tx) error) error {
+	savepoint, err := tx.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("creating savepoint: %w", err)
+	}
+
+	if err := fn(annotatedTx{Tx: savepoint, data: d}); err != nil || rollback {
+		// The original error is more useful than any rollback failure
+		_ = savepoint.Rollback(ctx)
+		return err
+	}
+
+	if err := savepoint.Commit(ctx); err != nil {
+		return fmt.Errorf("releasing savepoint: %w", err)
+	}
+
+	return nil
+}
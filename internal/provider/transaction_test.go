@@ -0,0 +1,52 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+func TestBeginOperationTxDisabled(t *testing.T) {
+	testCases := map[string]*ProviderData{
+		"use_transactions unset": {},
+		"management API":         {UseTransactions: true, managementAPI: &managementAPIClient{}},
+	}
+
+	for name, d := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			var diags diag.Diagnostics
+
+			// Without a pool, beginning a real transaction would fail
+			txCtx, tx, ok := d.beginOperationTx(ctx, &diags)
+			if !ok || diags.HasError() {
+				t.Fatalf("unexpected error: %v", diags)
+			}
+			if tx != nil {
+				t.Fatal("expected no transaction")
+			}
+			if _, ok := txFromContext(txCtx); ok {
+				t.Error("expected statements not to be routed to a transaction")
+			}
+
+			// Statements run on their own, so there is nothing to commit
+			if !tx.commit(ctx, &diags) || diags.HasError() {
+				t.Errorf("unexpected error: %v", diags)
+			}
+			tx.rollback(ctx)
+		})
+	}
+}
+
+func TestBeginOperationTxWithoutConnection(t *testing.T) {
+	d := &ProviderData{UseTransactions: true}
+	var diags diag.Diagnostics
+
+	if _, _, ok := d.beginOperationTx(context.Background(), &diags); ok || !diags.HasError() {
+		t.Fatal("expected an error without a connection pool")
+	}
+}
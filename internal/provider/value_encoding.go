@@ -0,0 +1,63 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// valueEncodings are the encodings supported by the encode_secret and
+// decode_secret functions.
+var valueEncodings = []string{"base64", "base64url", "hex"}
+
+// encodeValue encodes value with the named encoding.
+func encodeValue(value, encoding string) (string, error) {
+	switch encoding {
+	case "base64":
+		return base64.StdEncoding.EncodeToString([]byte(value)), nil
+	case "base64url":
+		return base64.URLEncoding.EncodeToString([]byte(value)), nil
+	case "hex":
+		return hex.EncodeToString([]byte(value)), nil
+	}
+
+	return "", unsupportedEncodingError(encoding)
+}
+
+// decodeValue decodes value with the named encoding. Base64 input is
+// accepted with or without padding. The decoded bytes must be valid UTF-8,
+// as Terraform strings can't hold anything else.
+func decodeValue(value, encoding string) (string, error) {
+	var decoded []byte
+	var err error
+
+	switch encoding {
+	case "base64":
+		decoded, err = base64.RawStdEncoding.DecodeString(strings.TrimRight(value, "="))
+	case "base64url":
+		decoded, err = base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+	case "hex":
+		decoded, err = hex.DecodeString(value)
+	default:
+		return "", unsupportedEncodingError(encoding)
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("value is not valid %s: %w", encoding, err)
+	}
+
+	if !utf8.Valid(decoded) {
+		return "", fmt.Errorf("decoded value is not valid UTF-8")
+	}
+
+	return string(decoded), nil
+}
+
+func unsupportedEncodingError(encoding string) error {
+	return fmt.Errorf("unsupported encoding %q, must be one of: %s", encoding, strings.Join(valueEncodings, ", "))
+}
@@ -0,0 +1,138 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+)
+
+func TestEncodeValue(t *testing.T) {
+	testCases := map[string]struct {
+		value       string
+		encoding    string
+		expected    string
+		expectError bool
+	}{
+		"base64": {
+			value:    "s3cr3t?>",
+			encoding: "base64",
+			expected: "czNjcjN0Pz4=",
+		},
+		"base64url": {
+			value:    "s3cr3t?>",
+			encoding: "base64url",
+			expected: "czNjcjN0Pz4=",
+		},
+		"base64url uses the URL alphabet": {
+			value:    "\xfb\xff",
+			encoding: "base64url",
+			expected: "-_8=",
+		},
+		"hex": {
+			value:    "s3cr3t",
+			encoding: "hex",
+			expected: "733363723374",
+		},
+		"empty value": {
+			value:    "",
+			encoding: "base64",
+			expected: "",
+		},
+		"unsupported encoding": {
+			value:       "s3cr3t",
+			encoding:    "base32",
+			expectError: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			encoded, err := encodeValue(testCase.value, testCase.encoding)
+
+			if testCase.expectError {
+				if err == nil {
+					t.Fatalf("expected an error, got: %q", encoded)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if encoded != testCase.expected {
+				t.Errorf("expected %q, got: %q", testCase.expected, encoded)
+			}
+		})
+	}
+}
+
+func TestDecodeValue(t *testing.T) {
+	testCases := map[string]struct {
+		value       string
+		encoding    string
+		expected    string
+		expectError bool
+	}{
+		"base64": {
+			value:    "czNjcjN0Pz4=",
+			encoding: "base64",
+			expected: "s3cr3t?>",
+		},
+		"base64 without padding": {
+			value:    "czNjcjN0Pz4",
+			encoding: "base64",
+			expected: "s3cr3t?>",
+		},
+		"base64url": {
+			value:    "aGk_",
+			encoding: "base64url",
+			expected: "hi?",
+		},
+		"base64url rejects the standard alphabet": {
+			value:       "aGk/",
+			encoding:    "base64url",
+			expectError: true,
+		},
+		"hex": {
+			value:    "733363723374",
+			encoding: "hex",
+			expected: "s3cr3t",
+		},
+		"hex with an odd length": {
+			value:       "73336",
+			encoding:    "hex",
+			expectError: true,
+		},
+		"invalid UTF-8": {
+			value:       "ff",
+			encoding:    "hex",
+			expectError: true,
+		},
+		"unsupported encoding": {
+			value:       "s3cr3t",
+			encoding:    "base32",
+			expectError: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			decoded, err := decodeValue(testCase.value, testCase.encoding)
+
+			if testCase.expectError {
+				if err == nil {
+					t.Fatalf("expected an error, got: %q", decoded)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if decoded != testCase.expected {
+				t.Errorf("expected %q, got: %q", testCase.expected, decoded)
+			}
+		})
+	}
+}
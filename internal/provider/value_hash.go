@@ -0,0 +1,82 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// hashSecretValue returns the SHA-256 hex digest of a secret value.
+func hashSecretValue(value string) string {
+	sum := sha256.Sum256([]byte(value))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// valueHash returns the value_hash to store for value. Write-only values
+// aren't hashed, so nothing derived from them reaches the state.
+func (m VaultSecretModel) valueHash(value string) types.String {
+	if m.Value.IsNull() {
+		return types.StringNull()
+	}
+
+	return types.StringValue(hashSecretValue(value))
+}
+
+// valueHashPlanModifier plans value_hash from the value that will be stored.
+// When the stored value is unchanged the planned hash matches state, so
+// equivalent values (e.g. a template rendering to the same output) don't
+// show up as a change.
+type valueHashPlanModifier struct{}
+
+func (m valueHashPlanModifier) Description(ctx context.Context) string {
+	return "Plans the hash of the value that will be stored in Vault."
+}
+
+func (m valueHashPlanModifier) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (m valueHashPlanModifier) PlanModifyString(ctx context.Context, req planmodifier.StringRequest, resp *planmodifier.StringResponse) {
+	// Nothing to plan when the resource is being destroyed
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan VaultSecretModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// value is null when the write-only value_wo is used instead
+	if plan.Value.IsNull() {
+		resp.PlanValue = types.StringNull()
+		return
+	}
+
+	// The hash can only be known once everything the value depends on is known
+	if plan.Value.IsUnknown() || plan.Vars.IsUnknown() {
+		return
+	}
+	for _, element := range plan.Vars.Elements() {
+		if element.IsUnknown() {
+			return
+		}
+	}
+
+	value, diags := plan.secretValue(ctx)
+	if diags.HasError() {
+		// Rendering errors are reported by ValidateConfig; leave the hash unknown
+		return
+	}
+
+	resp.PlanValue = types.StringValue(hashSecretValue(value))
+}
@@ -0,0 +1,20 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+)
+
+func TestHashSecretValue(t *testing.T) {
+	// echo -n "my-secret-value" | sha256sum
+	expected := "be22cbae9c1585c7b61a92fdb75afd10babd535fb9b317f90ac9a9ca896d02d7"
+
+	if hash := hashSecretValue("my-secret-value"); hash != expected {
+		t.Errorf("expected %s, got %s", expected, hash)
+	}
+	if hashSecretValue("a") == hashSecretValue("b") {
+		t.Error("expected different values to hash differently")
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+// compileValuePattern compiles a value_pattern.
+func compileValuePattern(pattern string) (*regexp.Regexp, error) {
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("unable to compile %q: %w", pattern, err)
+	}
+
+	return compiled, nil
+}
+
+// checkValuePattern adds an error to diags if value, the value rendered for
+// data, doesn't match data's value_pattern. The value is sensitive, so the
+// diagnostic only says that it didn't match, never what it was.
+func checkValuePattern(data VaultSecretModel, value string, diags *diag.Diagnostics) {
+	if !isKnown(data.ValuePattern) {
+		return
+	}
+
+	pattern, err := compileValuePattern(data.ValuePattern.ValueString())
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("value_pattern"),
+			"Invalid value_pattern",
+			err.Error(),
+		)
+		return
+	}
+
+	if pattern.MatchString(value) {
+		return
+	}
+
+	valuePath := path.Root("value")
+	if !data.ValueWO.IsNull() {
+		valuePath = path.Root("value_wo")
+	}
+
+	diags.AddAttributeError(
+		valuePath,
+		"Secret value doesn't match value_pattern",
+		fmt.Sprintf("The value of secret %q doesn't match value_pattern %q. The value isn't shown, as it is sensitive.", data.Name.ValueString(), pattern),
+	)
+}
+
+// valueKnown reports whether the value data would store is known, so it can
+// be checked at plan time. An omitted value isn't.
+func (m VaultSecretModel) valueKnown() bool {
+	if m.Value.IsUnknown() || m.ValueWO.IsUnknown() || (m.Value.IsNull() && m.ValueWO.IsNull()) {
+		return false
+	}
+
+	if !m.ValueTemplate.ValueBool() {
+		return true
+	}
+
+	if m.Vars.IsUnknown() {
+		return false
+	}
+	for _, element := range m.Vars.Elements() {
+		if element.IsUnknown() {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,72 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestCheckValuePattern(t *testing.T) {
+	const value = "sk_test_4eC39HqLyjWDarjtT1zdp7dc"
+
+	testCases := map[string]struct {
+		pattern      types.String
+		writeOnly    bool
+		expectedPath path.Path
+		expectErr    bool
+	}{
+		"no pattern":         {pattern: types.StringNull()},
+		"matching":           {pattern: types.StringValue(`^sk_test_[A-Za-z0-9]{24}$`)},
+		"unanchored":         {pattern: types.StringValue(`test`)},
+		"not matching":       {pattern: types.StringValue(`^sk_live_`), expectedPath: path.Root("value"), expectErr: true},
+		"write-only value":   {pattern: types.StringValue(`^sk_live_`), writeOnly: true, expectedPath: path.Root("value_wo"), expectErr: true},
+		"invalid pattern":    {pattern: types.StringValue(`^sk_(live`), expectedPath: path.Root("value_pattern"), expectErr: true},
+		"unknown pattern":    {pattern: types.StringUnknown()},
+		"case sensitive":     {pattern: types.StringValue(`^SK_TEST_`), expectedPath: path.Root("value"), expectErr: true},
+		"case insensitive":   {pattern: types.StringValue(`(?i)^SK_TEST_`)},
+		"whole value needed": {pattern: types.StringValue(`^sk_test_[A-Za-z0-9]{8}$`), expectedPath: path.Root("value"), expectErr: true},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			data := VaultSecretModel{
+				Name:         types.StringValue("stripe_key"),
+				ValueWO:      types.StringNull(),
+				ValuePattern: testCase.pattern,
+			}
+			if testCase.writeOnly {
+				data.ValueWO = types.StringValue(value)
+			}
+
+			var diags diag.Diagnostics
+			checkValuePattern(data, value, &diags)
+
+			if !testCase.expectErr {
+				if diags.HasError() {
+					t.Fatalf("unexpected error: %v", diags)
+				}
+				return
+			}
+
+			if diags.ErrorsCount() != 1 {
+				t.Fatalf("expected one error, got: %v", diags)
+			}
+
+			withPath, ok := diags.Errors()[0].(diag.DiagnosticWithPath)
+			if !ok || !withPath.Path().Equal(testCase.expectedPath) {
+				t.Errorf("expected an error on %s, got: %v", testCase.expectedPath, diags)
+			}
+
+			// The value is sensitive and must never be echoed
+			if strings.Contains(diags.Errors()[0].Detail(), value) {
+				t.Errorf("error detail includes the value: %s", diags.Errors()[0].Detail())
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+// defaultMaxValueBytes bounds secret values unless max_value_bytes is
+// configured. It is well above any credential or certificate bundle, but
+// keeps multi-megabyte blobs from failing obscurely during encryption.
+const defaultMaxValueBytes = 1 << 20
+
+// maxValueBytes returns the configured max_value_bytes, or the default
+// when none is configured.
+func (d *ProviderData) maxValueBytes() int64 {
+	if d.MaxValueBytes == 0 {
+		return defaultMaxValueBytes
+	}
+
+	return d.MaxValueBytes
+}
+
+// checkValueSize adds an error to diags if value, the value rendered for
+// data, is larger than max_value_bytes. The diagnostic never includes the
+// value itself.
+func (d *ProviderData) checkValueSize(data VaultSecretModel, value string, diags *diag.Diagnostics) {
+	limit := d.maxValueBytes()
+	if int64(len(value)) <= limit {
+		return
+	}
+
+	valuePath := path.Root("value")
+	if !data.ValueWO.IsNull() {
+		valuePath = path.Root("value_wo")
+	}
+
+	diags.AddAttributeError(
+		valuePath,
+		"Secret value too large",
+		fmt.Sprintf("The value of secret %q is %d bytes, more than the %d bytes allowed by max_value_bytes. Store large payloads elsewhere and keep a reference in Vault, or raise max_value_bytes.", data.Name.ValueString(), len(value), limit),
+	)
+}
+
+// checkEmptyValue adds an error to diags if value, the value rendered for
+// data, is empty and the provider isn't configured with allow_empty_value.
+func (d *ProviderData) checkEmptyValue(data VaultSecretModel, value string, diags *diag.Diagnostics) {
+	if value != "" || d.AllowEmptyValue {
+		return
+	}
+
+	valuePath := path.Root("value")
+	if !data.ValueWO.IsNull() {
+		valuePath = path.Root("value_wo")
+	}
+
+	diags.AddAttributeError(
+		valuePath,
+		"Empty secret value",
+		fmt.Sprintf("The value of secret %q is empty, which usually means an unset variable was passed as \"\". Set a value, or set the provider's allow_empty_value to store an empty one.", data.Name.ValueString()),
+	)
+}
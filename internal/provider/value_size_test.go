@@ -0,0 +1,114 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestCheckValueSize(t *testing.T) {
+	testCases := map[string]struct {
+		maxValueBytes int64
+		value         string
+		writeOnly     bool
+		expectErr     bool
+	}{
+		"default limit":         {value: strings.Repeat("a", defaultMaxValueBytes)},
+		"over default limit":    {value: strings.Repeat("a", defaultMaxValueBytes+1), expectErr: true},
+		"configured limit":      {maxValueBytes: 8, value: "password"},
+		"over configured limit": {maxValueBytes: 8, value: "password1", expectErr: true},
+		"multibyte":             {maxValueBytes: 8, value: "pässwort", expectErr: true},
+		"write-only over limit": {maxValueBytes: 8, value: "password1", writeOnly: true, expectErr: true},
+		"empty":                 {maxValueBytes: 1, value: ""},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			d := &ProviderData{MaxValueBytes: testCase.maxValueBytes}
+			data := VaultSecretModel{
+				Name:    types.StringValue("api_key"),
+				ValueWO: types.StringNull(),
+			}
+			expectedPath := path.Root("value")
+			if testCase.writeOnly {
+				data.ValueWO = types.StringValue(testCase.value)
+				expectedPath = path.Root("value_wo")
+			}
+
+			var diags diag.Diagnostics
+			d.checkValueSize(data, testCase.value, &diags)
+
+			if !testCase.expectErr {
+				if diags.HasError() {
+					t.Fatalf("unexpected error: %v", diags)
+				}
+				return
+			}
+
+			if !diags.HasError() {
+				t.Fatal("expected an error, got none")
+			}
+			withPath, ok := diags.Errors()[0].(diag.DiagnosticWithPath)
+			if !ok || !withPath.Path().Equal(expectedPath) {
+				t.Errorf("expected the error on %s, got %v", expectedPath, diags)
+			}
+			if strings.Contains(diags.Errors()[0].Detail(), testCase.value) {
+				t.Error("expected the error not to include the value")
+			}
+		})
+	}
+}
+
+func TestCheckEmptyValue(t *testing.T) {
+	testCases := map[string]struct {
+		allowEmptyValue bool
+		value           string
+		writeOnly       bool
+		expectErr       bool
+	}{
+		"value":                {value: "password"},
+		"empty":                {expectErr: true},
+		"write-only empty":     {writeOnly: true, expectErr: true},
+		"empty allowed":        {allowEmptyValue: true},
+		"whitespace not empty": {value: " "},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			d := &ProviderData{AllowEmptyValue: testCase.allowEmptyValue}
+			data := VaultSecretModel{
+				Name:    types.StringValue("api_key"),
+				ValueWO: types.StringNull(),
+			}
+			expectedPath := path.Root("value")
+			if testCase.writeOnly {
+				data.ValueWO = types.StringValue(testCase.value)
+				expectedPath = path.Root("value_wo")
+			}
+
+			var diags diag.Diagnostics
+			d.checkEmptyValue(data, testCase.value, &diags)
+
+			if !testCase.expectErr {
+				if diags.HasError() {
+					t.Fatalf("unexpected error: %v", diags)
+				}
+				return
+			}
+
+			if !diags.HasError() {
+				t.Fatal("expected an error, got none")
+			}
+			withPath, ok := diags.Errors()[0].(diag.DiagnosticWithPath)
+			if !ok || !withPath.Path().Equal(expectedPath) {
+				t.Errorf("expected the error on %s, got %v", expectedPath, diags)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+// templateErrorPosition matches the line and, for execution errors, the
+// column text/template puts in front of its error messages.
+var templateErrorPosition = regexp.MustCompile(`^template: value:(\d+)(?::(\d+))?:`)
+
+// templatePosition returns where in the template err occurred, e.g.
+// "line 2, column 5", or "" when err doesn't say.
+func templatePosition(err error) string {
+	match := templateErrorPosition.FindStringSubmatch(err.Error())
+	if match == nil {
+		return ""
+	}
+	if match[2] == "" {
+		return "line " + match[1]
+	}
+
+	return "line " + match[1] + ", column " + match[2]
+}
+
+// renderValueTemplate renders value as a Go text/template against vars.
+// Referencing a variable that isn't defined is an error rather than "<no value>".
+// text/template errors quote the template, which is the sensitive value, so
+// the errors returned only give the position of the problem.
+func renderValueTemplate(value string, vars map[string]string) (string, error) {
+	tmpl, err := template.New("value").Option("missingkey=error").Parse(value)
+	if err != nil {
+		return "", templateError("template could not be parsed", err)
+	}
+
+	var rendered strings.Builder
+	if err := tmpl.Execute(&rendered, vars); err != nil {
+		return "", templateError("template could not be executed; check that every variable it references is set in vars", err)
+	}
+
+	return rendered.String(), nil
+}
+
+// templateError returns an error with message and the position of err, but
+// none of err's text.
+func templateError(message string, err error) error {
+	if position := templatePosition(err); position != "" {
+		return fmt.Errorf("%s (at %s)", message, position)
+	}
+
+	return errors.New(message)
+}
+
+// secretValue returns the value to store in Vault, taken from value_wo when
+// set and value otherwise, rendering it against vars when value_template is
+// enabled.
+func (m VaultSecretModel) secretValue(ctx context.Context) (string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	value, valuePath := m.Value, path.Root("value")
+	if !m.ValueWO.IsNull() {
+		value, valuePath = m.ValueWO, path.Root("value_wo")
+	}
+
+	if !m.ValueTemplate.ValueBool() {
+		return value.ValueString(), diags
+	}
+
+	vars := map[string]string{}
+	if !m.Vars.IsNull() {
+		diags.Append(m.Vars.ElementsAs(ctx, &vars, false)...)
+		if diags.HasError() {
+			return "", diags
+		}
+	}
+
+	rendered, err := renderValueTemplate(value.ValueString(), vars)
+	if err != nil {
+		// The error only gives the template position, never any of its text
+		diags.AddAttributeError(
+			valuePath,
+			"Unable to render value template",
+			"The value could not be rendered: "+err.Error(),
+		)
+		return "", diags
+	}
+
+	return rendered, diags
+}
@@ -0,0 +1,99 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderValueTemplate(t *testing.T) {
+	testCases := map[string]struct {
+		value     string
+		vars      map[string]string
+		expected  string
+		expectErr bool
+	}{
+		"plain value": {
+			value:    "no-template-here",
+			expected: "no-template-here",
+		},
+		"interpolated": {
+			value:    "{{.user}}:{{.pass}}",
+			vars:     map[string]string{"user": "admin", "pass": "hunter2"},
+			expected: "admin:hunter2",
+		},
+		"missing var": {
+			value:     "{{.user}}:{{.pass}}",
+			vars:      map[string]string{"user": "admin"},
+			expectErr: true,
+		},
+		"invalid syntax": {
+			value:     "{{.user",
+			vars:      map[string]string{"user": "admin"},
+			expectErr: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			rendered, err := renderValueTemplate(testCase.value, testCase.vars)
+
+			if testCase.expectErr {
+				if err == nil {
+					t.Fatalf("expected error, got rendered value %q", rendered)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if rendered != testCase.expected {
+				t.Errorf("expected %q, got %q", testCase.expected, rendered)
+			}
+		})
+	}
+}
+
+func TestRenderValueTemplateErrorHidesValue(t *testing.T) {
+	testCases := map[string]struct {
+		value    string
+		vars     map[string]string
+		secret   string
+		position string
+	}{
+		"undefined function": {
+			value:    "prefix-{{hunter2}}",
+			secret:   "hunter2",
+			position: "line 1",
+		},
+		"unclosed action": {
+			value:    "hunter2\n{{.user",
+			secret:   "hunter2",
+			position: "line 2",
+		},
+		"missing var": {
+			value:    "{{.user}}:{{.hunter2}}",
+			vars:     map[string]string{"user": "admin"},
+			secret:   "hunter2",
+			position: "line 1, column 12",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := renderValueTemplate(testCase.value, testCase.vars)
+			if err == nil {
+				t.Fatal("expected error, got none")
+			}
+
+			if strings.Contains(err.Error(), testCase.secret) {
+				t.Errorf("expected the error not to quote the value, got %q", err)
+			}
+			if !strings.Contains(err.Error(), testCase.position) {
+				t.Errorf("expected the error to give position %q, got %q", testCase.position, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,608 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &VaultBulkSecretsResource{}
+var _ resource.ResourceWithValidateConfig = &VaultBulkSecretsResource{}
+var _ resource.ResourceWithModifyPlan = &VaultBulkSecretsResource{}
+
+// bulkDeleteQuery removes every secret whose id is in the $1 array in a single
+// round trip.
+const bulkDeleteQuery = "DELETE FROM vault.secrets WHERE id = ANY($1::uuid[])"
+
+func NewVaultBulkSecretsResource() resource.Resource {
+	return &VaultBulkSecretsResource{}
+}
+
+// VaultBulkSecretsResource defines the resource implementation.
+type VaultBulkSecretsResource struct {
+	providerData *ProviderData
+}
+
+// VaultBulkSecretsModel describes the resource data model.
+type VaultBulkSecretsModel struct {
+	Secrets     types.Map    `tfsdk:"secrets"`
+	Description types.String `tfsdk:"description"`
+	IDs         types.Map    `tfsdk:"ids"`
+
+	SecretsFile     types.String `tfsdk:"secrets_file"`
+	SecretsFileHash types.String `tfsdk:"secrets_file_hash"`
+}
+
+func (r *VaultBulkSecretsResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_bulk_secrets"
+}
+
+func (r *VaultBulkSecretsResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages a set of secrets in Supabase Vault as a single resource. " +
+			"Secrets are created in one statement and removed with a single `DELETE`, keeping applies and teardown fast for large secret sets. " +
+			"Every change to the set runs in one transaction: if any secret can't be created, none are, so bootstrapping never leaves the set partly created, and `ids` is only recorded once the transaction commits.",
+
+		Attributes: map[string]schema.Attribute{
+			"secrets": schema.MapAttribute{
+				MarkdownDescription: "Secret values to encrypt and store, keyed by secret name. Exactly one of `secrets` or `secrets_file` must be set; with `secrets_file` this holds the file's secrets.",
+				ElementType:         types.StringType,
+				Optional:            true,
+				Computed:            true,
+				Sensitive:           true,
+			},
+			"secrets_file": schema.StringAttribute{
+				MarkdownDescription: "Path to a JSON (`.json`) or YAML (`.yaml`, `.yml`) file holding a flat object of secret values keyed by secret name, e.g. `\"${path.module}/secrets.yaml\"`. The file is parsed at plan time, and apply fails if it changed since. Exactly one of `secrets` or `secrets_file` must be set.",
+				Optional:            true,
+			},
+			"secrets_file_hash": schema.StringAttribute{
+				MarkdownDescription: "SHA-256 hex digest of `secrets_file`'s content when it was last planned",
+				Computed:            true,
+			},
+			"description": schema.StringAttribute{
+				MarkdownDescription: "Optional description applied to every secret in the set",
+				Optional:            true,
+			},
+			"ids": schema.MapAttribute{
+				MarkdownDescription: "Secret UUIDs keyed by secret name",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (r *VaultBulkSecretsResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.providerData = providerData
+}
+
+func (r *VaultBulkSecretsResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data VaultBulkSecretsModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.Secrets.IsNull() == data.SecretsFile.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("secrets"),
+			"Invalid secrets source",
+			"Exactly one of secrets or secrets_file must be set.",
+		)
+	}
+}
+
+func (r *VaultBulkSecretsResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to plan when the resource is being destroyed
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var data VaultBulkSecretsModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.SecretsFile.IsNull() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("secrets_file_hash"), types.StringNull())...)
+		return
+	}
+
+	// The file can only be read once its path is known
+	if data.SecretsFile.IsUnknown() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("secrets"), types.MapUnknown(types.StringType))...)
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("secrets_file_hash"), types.StringUnknown())...)
+		return
+	}
+
+	file, err := loadSecretsFile(data.SecretsFile.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("secrets_file"), "Invalid secrets file", err.Error())
+		return
+	}
+
+	secrets, diags := types.MapValueFrom(ctx, types.StringType, file.Secrets)
+	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("secrets"), secrets)...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("secrets_file_hash"), file.Hash)...)
+
+	if req.State.Raw.IsNull() {
+		return
+	}
+
+	// The configuration is unchanged when only the file's content is, so
+	// the ids of added secrets aren't known yet either
+	var state VaultBulkSecretsModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if !secrets.Equal(state.Secrets) {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("ids"), types.MapUnknown(types.StringType))...)
+	}
+}
+
+// plannedSecrets returns the secrets to store for the planned data. Those
+// from secrets_file are read again, failing if the file changed since it was
+// planned, and recorded in data.
+func (r *VaultBulkSecretsResource) plannedSecrets(ctx context.Context, data *VaultBulkSecretsModel, diags *diag.Diagnostics) map[string]string {
+	var secrets map[string]string
+
+	if data.SecretsFile.IsNull() {
+		diags.Append(data.Secrets.ElementsAs(ctx, &secrets, false)...)
+		return secrets
+	}
+
+	file, err := loadSecretsFile(data.SecretsFile.ValueString())
+	if err != nil {
+		diags.AddAttributeError(path.Root("secrets_file"), "Invalid secrets file", err.Error())
+		return nil
+	}
+
+	if isKnown(data.SecretsFileHash) && file.Hash != data.SecretsFileHash.ValueString() {
+		diags.AddAttributeError(
+			path.Root("secrets_file"),
+			"Secrets file changed since plan",
+			fmt.Sprintf("The content of %s no longer matches the plan. Run terraform plan again to pick up the changes.", data.SecretsFile.ValueString()),
+		)
+		return nil
+	}
+
+	secretsValue, d := types.MapValueFrom(ctx, types.StringType, file.Secrets)
+	diags.Append(d...)
+	data.Secrets = secretsValue
+	data.SecretsFileHash = types.StringValue(file.Hash)
+
+	return file.Secrets
+}
+
+// bulkDescription returns the description to store on every secret in the
+// set, with the managed-by footer appended.
+func (r *VaultBulkSecretsResource) bulkDescription(data VaultBulkSecretsModel) string {
+	return r.providerData.storedDescription(data.Description, nil)
+}
+
+// createSecrets creates every secret in secrets with a single statement and
+// returns their ids keyed by name. Any failure is returned as an error so
+// that the caller's transaction rolls back and none of the secrets is kept.
+func (r *VaultBulkSecretsResource) createSecrets(ctx context.Context, tx pgx.Tx, secrets map[string]string, description string, diags *diag.Diagnostics) (map[string]string, error) {
+	names := make([]string, 0, len(secrets))
+	for name := range secrets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	storedNames := make([]string, len(names))
+	values := make([]string, len(names))
+	for i, name := range names {
+		if err := checkAllowedName(name, r.providerData.AllowedNamePatterns); err != nil {
+			diags.AddAttributeError(path.Root("secrets"), "Secret name not allowed", err.Error())
+			continue
+		}
+
+		storedName, err := encodeSecretName(name, r.providerData.AllowInvalidUTF8Names)
+		if err != nil {
+			diags.AddAttributeError(path.Root("secrets"), "Invalid secret name", err.Error())
+			continue
+		}
+		storedNames[i] = storedName
+		values[i] = secrets[name]
+	}
+
+	// Fail the statement so the surrounding transaction rolls back too
+	if diags.HasError() {
+		return nil, fmt.Errorf("one or more secret names were rejected")
+	}
+
+	if len(names) == 0 {
+		return map[string]string{}, nil
+	}
+
+	query := `
+		SELECT s.ord, vault.create_secret(s.value, s.name, $3)
+		FROM unnest($1::text[], $2::text[]) WITH ORDINALITY AS s(name, value, ord)
+	`
+	rows, err := tx.Query(ctx, query, storedNames, values, description)
+	if err != nil {
+		return nil, fmt.Errorf("calling vault.create_secret: %w", err)
+	}
+
+	ids := make(map[string]string, len(names))
+	var ord int64
+	var id sql.NullString
+	_, err = pgx.ForEachRow(rows, []any{&ord, &id}, func() error {
+		if !id.Valid {
+			return fmt.Errorf("vault.create_secret returned no id for %q; check triggers on vault.secrets and the connecting role's permissions", names[ord-1])
+		}
+		ids[names[ord-1]] = id.String
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// warnOnDeleteMismatch adds a warning to diags when a bulk delete removed a
+// different number of secrets than expected, e.g. because some were already
+// deleted outside Terraform.
+func warnOnDeleteMismatch(diags *diag.Diagnostics, expected int, tag pgconn.CommandTag) {
+	if tag.RowsAffected() == int64(expected) {
+		return
+	}
+
+	diags.AddWarning(
+		"Unexpected number of secrets deleted",
+		fmt.Sprintf("Expected to delete %d secrets but %d were deleted. Secrets may have been removed outside Terraform.", expected, tag.RowsAffected()),
+	)
+}
+
+func (r *VaultBulkSecretsResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.providerData.logContext(ctx)
+
+	release, ok := r.providerData.beginOperation(ctx, &resp.Diagnostics)
+	if !ok {
+		return
+	}
+	defer release()
+
+	if !r.providerData.checkWritable(&resp.Diagnostics, "create vault secrets") {
+		return
+	}
+
+	var data VaultBulkSecretsModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	secrets := r.plannedSecrets(ctx, &data, &resp.Diagnostics)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The ids only reach the state once the transaction commits, so a
+	// failure leaves neither secrets nor state behind
+	var ids map[string]string
+	err := r.providerData.withTx(ctx, func(tx pgx.Tx) error {
+		var err error
+		ids, err = r.createSecrets(ctx, tx, secrets, r.bulkDescription(data), &resp.Diagnostics)
+		return err
+	})
+
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to create vault secrets",
+			withRemediation(fmt.Sprintf("Error creating secrets, so none of them were created: %s", err), err),
+		)
+		return
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	idsValue, diags := types.MapValueFrom(ctx, types.StringType, ids)
+	resp.Diagnostics.Append(diags...)
+	data.IDs = idsValue
+
+	tflog.Trace(ctx, "created vault secrets", map[string]interface{}{
+		"count": len(ids),
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *VaultBulkSecretsResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = r.providerData.logContext(ctx)
+
+	release, ok := r.providerData.beginOperation(ctx, &resp.Diagnostics)
+	if !ok {
+		return
+	}
+	defer release()
+
+	var data VaultBulkSecretsModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	var ids, secrets map[string]string
+	resp.Diagnostics.Append(data.IDs.ElementsAs(ctx, &ids, false)...)
+	resp.Diagnostics.Append(data.Secrets.ElementsAs(ctx, &secrets, false)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	secretIDs := make([]string, 0, len(ids))
+	for _, id := range ids {
+		secretIDs = append(secretIDs, id)
+	}
+
+	query := "SELECT id::text FROM vault.secrets WHERE id = ANY($1::uuid[])"
+	existing, err := collectRows(ctx, r.providerData, pgx.RowTo[string], query, secretIDs)
+
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to read vault secrets",
+			withRemediation(fmt.Sprintf("Error reading secrets: %s", err), err),
+		)
+		return
+	}
+
+	found := make(map[string]bool, len(existing))
+	for _, id := range existing {
+		found[id] = true
+	}
+
+	var missing []string
+	for name, id := range ids {
+		if !found[id] {
+			missing = append(missing, fmt.Sprintf("%q", name))
+		}
+	}
+	sort.Strings(missing)
+
+	if len(missing) > 0 && !r.providerData.allowMissing(&resp.Diagnostics, fmt.Sprintf("secrets %s", strings.Join(missing, ", "))) {
+		return
+	}
+
+	// Drop secrets deleted outside Terraform so the next plan recreates them
+	for name, id := range ids {
+		if !found[id] {
+			delete(ids, name)
+			delete(secrets, name)
+		}
+	}
+
+	if len(ids) == 0 && len(secretIDs) > 0 {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	idsValue, diags := types.MapValueFrom(ctx, types.StringType, ids)
+	resp.Diagnostics.Append(diags...)
+	secretsValue, diags := types.MapValueFrom(ctx, types.StringType, secrets)
+	resp.Diagnostics.Append(diags...)
+	data.IDs = idsValue
+	data.Secrets = secretsValue
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *VaultBulkSecretsResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = r.providerData.logContext(ctx)
+
+	release, ok := r.providerData.beginOperation(ctx, &resp.Diagnostics)
+	if !ok {
+		return
+	}
+	defer release()
+
+	if !r.providerData.checkWritable(&resp.Diagnostics, "update vault secrets") {
+		return
+	}
+
+	var data VaultBulkSecretsModel
+	var state VaultBulkSecretsModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	planned := r.plannedSecrets(ctx, &data, &resp.Diagnostics)
+
+	var stored, ids map[string]string
+	resp.Diagnostics.Append(state.Secrets.ElementsAs(ctx, &stored, false)...)
+	resp.Diagnostics.Append(state.IDs.ElementsAs(ctx, &ids, false)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	description := r.bulkDescription(data)
+	descriptionChanged := !data.Description.Equal(state.Description)
+
+	var removed []string
+	for name, id := range ids {
+		if _, ok := planned[name]; !ok {
+			removed = append(removed, id)
+			delete(ids, name)
+		}
+	}
+
+	added := make(map[string]string)
+	for name, value := range planned {
+		if _, ok := ids[name]; !ok {
+			added[name] = value
+		}
+	}
+
+	updateStatement := r.providerData.updateSecretStatement(ctx)
+
+	// Apply every change together so a failure leaves the set as it was
+	err := r.providerData.withTx(ctx, func(tx pgx.Tx) error {
+		if len(removed) > 0 {
+			tag, err := tx.Exec(ctx, bulkDeleteQuery, removed)
+			if err != nil {
+				return fmt.Errorf("deleting removed secrets: %w", err)
+			}
+			warnOnDeleteMismatch(&resp.Diagnostics, len(removed), tag)
+		}
+
+		if updateStatement == legacyUpdateSecretQuery {
+			if err := checkEncryptionTrigger(ctx, tx); err != nil {
+				return err
+			}
+		}
+
+		for name, id := range ids {
+			if planned[name] == stored[name] && !descriptionChanged {
+				continue
+			}
+
+			storedName, err := encodeSecretName(name, r.providerData.AllowInvalidUTF8Names)
+			if err != nil {
+				return err
+			}
+
+			// A failed statement aborts the transaction, so the statement is
+			// chosen up front rather than falling back
+			if _, err := tx.Exec(ctx, updateStatement, id, planned[name], storedName, description, nil); err != nil {
+				return fmt.Errorf("updating secret %q: %w", name, err)
+			}
+		}
+
+		created, err := r.createSecrets(ctx, tx, added, description, &resp.Diagnostics)
+		if err != nil {
+			return err
+		}
+		for name, id := range created {
+			ids[name] = id
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to update vault secrets",
+			withRemediation(fmt.Sprintf("Error updating secrets, so the set was left unchanged: %s", err), err),
+		)
+		return
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	idsValue, diags := types.MapValueFrom(ctx, types.StringType, ids)
+	resp.Diagnostics.Append(diags...)
+	data.IDs = idsValue
+
+	tflog.Trace(ctx, "updated vault secrets", map[string]interface{}{
+		"added":   len(added),
+		"removed": len(removed),
+	})
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *VaultBulkSecretsResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = r.providerData.logContext(ctx)
+
+	release, ok := r.providerData.beginOperation(ctx, &resp.Diagnostics)
+	if !ok {
+		return
+	}
+	defer release()
+
+	if !r.providerData.checkWritable(&resp.Diagnostics, "delete vault secrets") {
+		return
+	}
+
+	var data VaultBulkSecretsModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	var ids map[string]string
+	resp.Diagnostics.Append(data.IDs.ElementsAs(ctx, &ids, false)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	secretIDs := make([]string, 0, len(ids))
+	for _, id := range ids {
+		secretIDs = append(secretIDs, id)
+	}
+
+	if len(secretIDs) == 0 {
+		return
+	}
+
+	// Remove the whole set in one statement rather than one round trip per secret
+	tag, err := r.providerData.exec(ctx, bulkDeleteQuery, secretIDs)
+
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to delete vault secrets",
+			withRemediation(fmt.Sprintf("Error deleting secrets: %s", err), err),
+		)
+		return
+	}
+
+	warnOnDeleteMismatch(&resp.Diagnostics, len(secretIDs), tag)
+
+	tflog.Trace(ctx, "deleted vault secrets", map[string]interface{}{
+		"count": tag.RowsAffected(),
+	})
+}
@@ -0,0 +1,223 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestAccVaultBulkSecretsResource(t *testing.T) {
+	// Skip if TF_ACC is not set
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests skipped unless env 'TF_ACC' set")
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccVaultBulkSecretsResourceConfig(`
+    "test-bulk-1" = "value-1"
+    "test-bulk-2" = "value-2"
+`),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"supabase-vault_bulk_secrets.test",
+						tfjsonpath.New("ids"),
+						knownvalue.MapSizeExact(2),
+					),
+				},
+			},
+			// Removing one secret and adding another updates the set in place
+			{
+				Config: testAccVaultBulkSecretsResourceConfig(`
+    "test-bulk-2" = "value-2-updated"
+    "test-bulk-3" = "value-3"
+`),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"supabase-vault_bulk_secrets.test",
+						tfjsonpath.New("ids"),
+						knownvalue.MapPartial(map[string]knownvalue.Check{
+							"test-bulk-2": knownvalue.NotNull(),
+							"test-bulk-3": knownvalue.NotNull(),
+						}),
+					),
+					statecheck.ExpectKnownValue(
+						"supabase-vault_bulk_secrets.test",
+						tfjsonpath.New("ids"),
+						knownvalue.MapSizeExact(2),
+					),
+				},
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccVaultBulkSecretsResource_SecretsFile(t *testing.T) {
+	// Skip if TF_ACC is not set
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests skipped unless env 'TF_ACC' set")
+	}
+
+	secretsFile := filepath.Join(t.TempDir(), "secrets.yaml")
+	writeSecretsFile := func(content string) {
+		if err := os.WriteFile(secretsFile, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				PreConfig: func() {
+					writeSecretsFile("test-bulk-file-1: value-1\ntest-bulk-file-2: value-2\n")
+				},
+				Config: testAccVaultBulkSecretsResourceConfigFile(secretsFile),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"supabase-vault_bulk_secrets.test",
+						tfjsonpath.New("ids"),
+						knownvalue.MapSizeExact(2),
+					),
+					statecheck.ExpectKnownValue(
+						"supabase-vault_bulk_secrets.test",
+						tfjsonpath.New("secrets_file_hash"),
+						knownvalue.NotNull(),
+					),
+				},
+			},
+			// Editing the file alone updates the set
+			{
+				PreConfig: func() {
+					writeSecretsFile("test-bulk-file-2: value-2-updated\ntest-bulk-file-3: value-3\n")
+				},
+				Config: testAccVaultBulkSecretsResourceConfigFile(secretsFile),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("supabase-vault_bulk_secrets.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"supabase-vault_bulk_secrets.test",
+						tfjsonpath.New("ids"),
+						knownvalue.MapPartial(map[string]knownvalue.Check{
+							"test-bulk-file-2": knownvalue.NotNull(),
+							"test-bulk-file-3": knownvalue.NotNull(),
+						}),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestCreateSecretsRejectedName(t *testing.T) {
+	r := &VaultBulkSecretsResource{
+		providerData: &ProviderData{AllowedNamePatterns: []*regexp.Regexp{regexp.MustCompile(`^app_`)}},
+	}
+
+	var diags diag.Diagnostics
+	// No statement may run, so no transaction is needed
+	ids, err := r.createSecrets(t.Context(), nil, map[string]string{"app_key": "value", "other_key": "value"}, "", &diags)
+
+	if err == nil {
+		t.Fatal("expected an error so the transaction rolls back, got none")
+	}
+	if ids != nil {
+		t.Errorf("expected no ids, got %v", ids)
+	}
+	if !diags.HasError() {
+		t.Error("expected the rejected name to be reported")
+	}
+}
+
+func TestAccVaultBulkSecretsResource_Rollback(t *testing.T) {
+	// Skip if TF_ACC is not set
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests skipped unless env 'TF_ACC' set")
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// A name that is already taken fails the whole set
+			{
+				Config: testAccVaultBulkSecretsResourceConfigRollback(`
+    "test-bulk-rollback-1" = "value-1"
+    "test-bulk-rollback-2" = "value-2"
+`),
+				ExpectError: regexp.MustCompile(`none of them were created`),
+			},
+			// Creating test-bulk-rollback-1 again only succeeds if the failed
+			// apply rolled it back
+			{
+				Config: testAccVaultBulkSecretsResourceConfigRollback(`
+    "test-bulk-rollback-1" = "value-1"
+    "test-bulk-rollback-3" = "value-3"
+`),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"supabase-vault_bulk_secrets.test",
+						tfjsonpath.New("ids"),
+						knownvalue.MapSizeExact(2),
+					),
+				},
+			},
+		},
+	})
+}
+
+func testAccVaultBulkSecretsResourceConfig(secrets string) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "supabase-vault_bulk_secrets" "test" {
+  description = "Bulk test secrets"
+
+  secrets = {%s  }
+}
+`, secrets)
+}
+
+func testAccVaultBulkSecretsResourceConfigFile(secretsFile string) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "supabase-vault_bulk_secrets" "test" {
+  description  = "Bulk test secrets"
+  secrets_file = %q
+}
+`, secretsFile)
+}
+
+func testAccVaultBulkSecretsResourceConfigRollback(secrets string) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "supabase-vault_secret" "existing" {
+  name  = "test-bulk-rollback-2"
+  value = "existing"
+}
+
+resource "supabase-vault_bulk_secrets" "test" {
+  secrets = {%s  }
+
+  depends_on = [supabase-vault_secret.existing]
+}
+`, secrets)
+}
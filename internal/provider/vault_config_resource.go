@@ -0,0 +1,405 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/jackc/pgx/v5"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &VaultConfigResource{}
+var _ resource.ResourceWithValidateConfig = &VaultConfigResource{}
+
+// vaultSettingPattern matches the names of the settings supabase-vault_config
+// manages. Only Vault and pgsodium settings are accepted, and the names are
+// restricted enough to be placed in ALTER DATABASE unquoted.
+var vaultSettingPattern = regexp.MustCompile(`^(vault|pgsodium)\.[a-z_][a-z0-9_]*$`)
+
+func NewVaultConfigResource() resource.Resource {
+	return &VaultConfigResource{}
+}
+
+// VaultConfigResource defines the resource implementation.
+type VaultConfigResource struct {
+	providerData *ProviderData
+}
+
+// VaultConfigModel describes the resource data model.
+type VaultConfigModel struct {
+	ID       types.String `tfsdk:"id"`
+	Settings types.Map    `tfsdk:"settings"`
+}
+
+func (r *VaultConfigResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_config"
+}
+
+func (r *VaultConfigResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages database-level Vault and pgsodium settings with `ALTER DATABASE ... SET`, e.g. `pgsodium.enable_event_trigger`. " +
+			"Settings apply to sessions opened after the change. Changing them requires owning the database or superuser; on hosted Supabase projects many settings can't be changed at all. " +
+			"Only the settings listed here are managed: others set on the database are left alone, and destroying the resource resets just these.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Name of the database the settings apply to",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"settings": schema.MapAttribute{
+				MarkdownDescription: "Setting values keyed by setting name. Names must start with `vault.` or `pgsodium.`.",
+				ElementType:         types.StringType,
+				Required:            true,
+			},
+		},
+	}
+}
+
+func (r *VaultConfigResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data VaultConfigModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() || data.Settings.IsUnknown() {
+		return
+	}
+
+	for name, value := range data.Settings.Elements() {
+		if !vaultSettingPattern.MatchString(name) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("settings").AtMapKey(name),
+				"Invalid setting name",
+				fmt.Sprintf("%q is not a setting supabase-vault_config can manage: names must start with vault. or pgsodium. followed by lowercase letters, digits or underscores.", name),
+			)
+		}
+
+		if value, ok := value.(types.String); ok && isKnown(value) && strings.ContainsRune(value.ValueString(), 0) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("settings").AtMapKey(name),
+				"Invalid setting value",
+				fmt.Sprintf("The value of %q contains a NUL character.", name),
+			)
+		}
+	}
+}
+
+func (r *VaultConfigResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.providerData = providerData
+}
+
+// databaseSettings reads the settings stored for the current database,
+// excluding role-specific ones, along with the database's name.
+func (r *VaultConfigResource) databaseSettings(ctx context.Context) (string, map[string]string, error) {
+	var database string
+	if err := r.providerData.queryRow(ctx, "SELECT current_database()").Scan(&database); err != nil {
+		return "", nil, err
+	}
+
+	query := `
+		SELECT s.setconfig
+		FROM pg_db_role_setting s
+		JOIN pg_database d ON d.oid = s.setdatabase
+		WHERE d.datname = current_database() AND s.setrole = 0
+	`
+
+	var setconfig []string
+	err := r.providerData.queryRow(ctx, query).Scan(&setconfig)
+	if err != nil && err != pgx.ErrNoRows {
+		return "", nil, err
+	}
+
+	settings := make(map[string]string, len(setconfig))
+	for _, entry := range setconfig {
+		if name, value, ok := strings.Cut(entry, "="); ok {
+			settings[name] = value
+		}
+	}
+
+	return database, settings, nil
+}
+
+// apply sets every setting in set and resets every name in reset on the
+// current database, in a single transaction.
+func (r *VaultConfigResource) apply(ctx context.Context, set map[string]string, reset []string) (string, error) {
+	var database string
+
+	err := r.providerData.withTx(ctx, func(tx pgx.Tx) error {
+		if err := tx.QueryRow(ctx, "SELECT current_database()").Scan(&database); err != nil {
+			return fmt.Errorf("reading the current database: %w", err)
+		}
+		identifier := qualifiedName("", database)
+
+		// Apply in name order so failures are reported deterministically
+		names := make([]string, 0, len(set))
+		for name := range set {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			if !vaultSettingPattern.MatchString(name) {
+				return fmt.Errorf("invalid setting name %q", name)
+			}
+
+			// ALTER DATABASE SET doesn't accept query parameters
+			value, err := quoteString(set[name])
+			if err != nil {
+				return fmt.Errorf("setting %s: %w", name, err)
+			}
+
+			statement := fmt.Sprintf("ALTER DATABASE %s SET %s = %s", identifier, name, value)
+			if _, err := tx.Exec(ctx, statement); err != nil {
+				return fmt.Errorf("setting %s: %w", name, err)
+			}
+		}
+
+		sort.Strings(reset)
+		for _, name := range reset {
+			if !vaultSettingPattern.MatchString(name) {
+				return fmt.Errorf("invalid setting name %q", name)
+			}
+
+			statement := fmt.Sprintf("ALTER DATABASE %s RESET %s", identifier, name)
+			if _, err := tx.Exec(ctx, statement); err != nil {
+				return fmt.Errorf("resetting %s: %w", name, err)
+			}
+		}
+
+		return nil
+	})
+
+	return database, err
+}
+
+// addApplyError adds an error for a failed apply to diags, explaining the
+// privileges needed when that's why it failed.
+func addApplyError(diags *diag.Diagnostics, err error) {
+	if isInsufficientPrivilegeError(err) {
+		diags.AddError(
+			"Insufficient privileges to change vault settings",
+			fmt.Sprintf("Changing database settings requires owning the database or superuser, which the connecting role lacks: %s", err),
+		)
+		return
+	}
+
+	diags.AddError(
+		"Unable to change vault settings",
+		withRemediation(fmt.Sprintf("Error changing database settings: %s", err), err),
+	)
+}
+
+func (r *VaultConfigResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.providerData.logContext(ctx)
+
+	release, ok := r.providerData.beginOperation(ctx, &resp.Diagnostics)
+	if !ok {
+		return
+	}
+	defer release()
+
+	if !r.providerData.checkWritable(&resp.Diagnostics, "change vault settings") {
+		return
+	}
+
+	var data VaultConfigModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	var settings map[string]string
+	resp.Diagnostics.Append(data.Settings.ElementsAs(ctx, &settings, false)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	database, err := r.apply(ctx, settings, nil)
+	if err != nil {
+		addApplyError(&resp.Diagnostics, err)
+		return
+	}
+
+	data.ID = types.StringValue(database)
+
+	tflog.Trace(ctx, "changed vault settings", map[string]interface{}{
+		"database": database,
+		"count":    len(settings),
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *VaultConfigResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = r.providerData.logContext(ctx)
+
+	release, ok := r.providerData.beginOperation(ctx, &resp.Diagnostics)
+	if !ok {
+		return
+	}
+	defer release()
+
+	var data VaultConfigModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	database, stored, err := r.databaseSettings(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to read vault settings",
+			withRemediation(fmt.Sprintf("Error reading database settings: %s", err), err),
+		)
+		return
+	}
+
+	// Only the managed settings are tracked; one reset outside Terraform
+	// drops out so the plan sets it again
+	settings := map[string]string{}
+	for name := range data.Settings.Elements() {
+		if value, ok := stored[name]; ok {
+			settings[name] = value
+		}
+	}
+
+	settingsValue, diags := types.MapValueFrom(ctx, types.StringType, settings)
+	resp.Diagnostics.Append(diags...)
+
+	data.ID = types.StringValue(database)
+	data.Settings = settingsValue
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *VaultConfigResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = r.providerData.logContext(ctx)
+
+	release, ok := r.providerData.beginOperation(ctx, &resp.Diagnostics)
+	if !ok {
+		return
+	}
+	defer release()
+
+	if !r.providerData.checkWritable(&resp.Diagnostics, "change vault settings") {
+		return
+	}
+
+	var data VaultConfigModel
+	var state VaultConfigModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	var settings, prior map[string]string
+	resp.Diagnostics.Append(data.Settings.ElementsAs(ctx, &settings, false)...)
+	resp.Diagnostics.Append(state.Settings.ElementsAs(ctx, &prior, false)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Reset settings that are no longer managed and set the rest
+	var reset []string
+	for name := range prior {
+		if _, ok := settings[name]; !ok {
+			reset = append(reset, name)
+		}
+	}
+
+	database, err := r.apply(ctx, settings, reset)
+	if err != nil {
+		addApplyError(&resp.Diagnostics, err)
+		return
+	}
+
+	data.ID = types.StringValue(database)
+
+	tflog.Trace(ctx, "changed vault settings", map[string]interface{}{
+		"database": database,
+		"count":    len(settings),
+		"reset":    len(reset),
+	})
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *VaultConfigResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = r.providerData.logContext(ctx)
+
+	release, ok := r.providerData.beginOperation(ctx, &resp.Diagnostics)
+	if !ok {
+		return
+	}
+	defer release()
+
+	if !r.providerData.checkWritable(&resp.Diagnostics, "reset vault settings") {
+		return
+	}
+
+	var data VaultConfigModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	var settings map[string]string
+	resp.Diagnostics.Append(data.Settings.ElementsAs(ctx, &settings, false)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	reset := make([]string, 0, len(settings))
+	for name := range settings {
+		reset = append(reset, name)
+	}
+
+	if _, err := r.apply(ctx, nil, reset); err != nil {
+		addApplyError(&resp.Diagnostics, err)
+		return
+	}
+
+	tflog.Trace(ctx, "reset vault settings", map[string]interface{}{
+		"database": data.ID.ValueString(),
+		"count":    len(reset),
+	})
+}
@@ -0,0 +1,143 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestAccVaultConfigResource(t *testing.T) {
+	// Skip if TF_ACC is not set
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests skipped unless env 'TF_ACC' set")
+	}
+
+	// Changing database settings needs the database owner or a superuser
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig() + `
+resource "supabase-vault_config" "test" {
+  settings = {
+    "vault.tf_acc_test" = "it's set"
+  }
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"supabase-vault_config.test",
+						tfjsonpath.New("settings").AtMapKey("vault.tf_acc_test"),
+						knownvalue.StringExact("it's set"),
+					),
+					statecheck.ExpectKnownValue(
+						"supabase-vault_config.test",
+						tfjsonpath.New("id"),
+						knownvalue.NotNull(),
+					),
+				},
+			},
+			{
+				Config: testAccProviderConfig() + `
+resource "supabase-vault_config" "test" {
+  settings = {
+    "vault.tf_acc_other" = "on"
+  }
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"supabase-vault_config.test",
+						tfjsonpath.New("settings"),
+						knownvalue.MapExact(map[string]knownvalue.Check{
+							"vault.tf_acc_other": knownvalue.StringExact("on"),
+						}),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestVaultConfigResourceValidateConfig(t *testing.T) {
+	testCases := map[string]struct {
+		settings  map[string]tftypes.Value
+		expectErr bool
+	}{
+		"pgsodium setting": {
+			settings: map[string]tftypes.Value{
+				"pgsodium.enable_event_trigger": tftypes.NewValue(tftypes.String, "off"),
+			},
+		},
+		"vault setting": {
+			settings: map[string]tftypes.Value{
+				"vault.default_key": tftypes.NewValue(tftypes.String, "6f1c1c0e-2b5a-4f8e-9d3c-1a2b3c4d5e6f"),
+			},
+		},
+		"unknown value": {
+			settings: map[string]tftypes.Value{
+				"vault.default_key": tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+			},
+		},
+		"unrelated setting": {
+			settings: map[string]tftypes.Value{
+				"statement_timeout": tftypes.NewValue(tftypes.String, "0"),
+			},
+			expectErr: true,
+		},
+		"injected name": {
+			settings: map[string]tftypes.Value{
+				"vault.x = 1; DROP TABLE vault.secrets; --": tftypes.NewValue(tftypes.String, "1"),
+			},
+			expectErr: true,
+		},
+		"nul in value": {
+			settings: map[string]tftypes.Value{
+				"vault.default_key": tftypes.NewValue(tftypes.String, "a\x00b"),
+			},
+			expectErr: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			r := &VaultConfigResource{}
+
+			schemaResp := &fwresource.SchemaResponse{}
+			r.Schema(ctx, fwresource.SchemaRequest{}, schemaResp)
+
+			config := map[string]tftypes.Value{
+				"settings": tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, testCase.settings),
+			}
+
+			req := fwresource.ValidateConfigRequest{
+				Config: tfsdk.Config{
+					Schema: schemaResp.Schema,
+					Raw:    testConfigValue(t, schemaResp.Schema.Type(), config),
+				},
+			}
+			resp := &fwresource.ValidateConfigResponse{}
+			r.ValidateConfig(ctx, req, resp)
+
+			if testCase.expectErr && !resp.Diagnostics.HasError() {
+				t.Fatal("expected an error, got none")
+			}
+			if !testCase.expectErr && resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected error: %v", resp.Diagnostics)
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// vaultExtension describes the installed supabase_vault extension.
+type vaultExtension struct {
+	version string
+
+	// hasUpdateSecret is unset for releases that predate vault.update_secret
+	hasUpdateSecret bool
+
+	// hasKeyTable is set when pgsodium.key exists and the connecting role
+	// may read it, which releases that no longer use pgsodium don't allow
+	hasKeyTable bool
+
+	// createdByColumn is the column of vault.secrets recording who created a
+	// secret, created_by or owner, which some installations add for
+	// auditing. It is empty when there is none the connecting role may read.
+	createdByColumn string
+}
+
+// vaultExtensionQuery reads the installed extension version, whether it
+// provides vault.update_secret, whether pgsodium.key can be read and which
+// column of vault.secrets records who created a secret. information_schema
+// only lists the columns the connecting role has privileges on. The
+// function is looked up rather than inferred from the version, as Supabase
+// has shipped patched releases.
+const vaultExtensionQuery = `
+	SELECT
+		(SELECT extversion FROM pg_extension WHERE extname = 'supabase_vault'),
+		EXISTS (
+			SELECT 1 FROM pg_proc p JOIN pg_namespace n ON n.oid = p.pronamespace
+			WHERE n.nspname = 'vault' AND p.proname = 'update_secret'
+		),
+		COALESCE(has_table_privilege(to_regclass('pgsodium.key'), 'SELECT'), false),
+		(
+			SELECT column_name::text FROM information_schema.columns
+			WHERE table_schema = 'vault' AND table_name = 'secrets' AND column_name IN ('created_by', 'owner')
+			ORDER BY column_name = 'created_by' DESC
+			LIMIT 1
+		)
+`
+
+// Releases without vault.update_secret encrypt secrets with a pgsodium
+// trigger that fires whenever the secret column is written, so updating the
+// row directly re-encrypts the new value like the function would. Without
+// the trigger it would store the value in plaintext, so these statements
+// only run after checkEncryptionTrigger in the same transaction.
+//
+// The trigger also binds the ciphertext to the row's id, description and
+// timestamps, so changing the description without rewriting the secret
+// leaves it undecryptable. Metadata changes therefore go through
+// legacyReencryptSecretQuery too, where a NULL name keeps the current one.
+const (
+	legacyUpdateSecretQuery = `
+		UPDATE vault.secrets
+		SET secret = $2, name = $3, description = $4, key_id = COALESCE($5::uuid, key_id), updated_at = now()
+		WHERE id = $1
+	`
+
+	legacyReencryptSecretQuery = `
+		UPDATE vault.secrets s
+		SET secret = d.decrypted_secret, name = COALESCE($2::text, s.name), description = $3, key_id = COALESCE($4::uuid, s.key_id), updated_at = now()
+		FROM vault.decrypted_secrets d
+		WHERE s.id = $1 AND d.id = s.id
+	`
+)
+
+// isUndefinedFunctionError reports whether err is PostgreSQL failing to find
+// a function.
+func isUndefinedFunctionError(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+
+	// 42883: undefined_function
+	return pgErr.Code == "42883"
+}
+
+// vaultExtension returns the installed supabase_vault extension, read once
+// and then cached for the provider's database.
+func (d *ProviderData) vaultExtension(ctx context.Context) (vaultExtension, error) {
+	d.vaultExtensionMu.Lock()
+	defer d.vaultExtensionMu.Unlock()
+
+	if d.vaultExt != nil {
+		return *d.vaultExt, nil
+	}
+
+	var version, createdByColumn sql.NullString
+	var ext vaultExtension
+	if err := d.queryRow(ctx, vaultExtensionQuery).Scan(&version, &ext.hasUpdateSecret, &ext.hasKeyTable, &createdByColumn); err != nil {
+		return vaultExtension{}, err
+	}
+	ext.version = version.String
+	ext.createdByColumn = createdByColumn.String
+
+	tflog.Debug(ctx, "Detected the supabase_vault extension", map[string]interface{}{
+		"version":           ext.version,
+		"has_update_secret": ext.hasUpdateSecret,
+		"has_key_table":     ext.hasKeyTable,
+		"created_by_column": ext.createdByColumn,
+	})
+
+	d.vaultExt = &ext
+
+	return ext, nil
+}
+
+// legacyUpdate reports whether secrets have to be updated without
+// vault.update_secret. If the extension can't be read, the function is
+// assumed to exist, and a failing call still falls back.
+func (d *ProviderData) legacyUpdate(ctx context.Context) bool {
+	ext, err := d.vaultExtension(ctx)
+	if err != nil {
+		tflog.Debug(ctx, "Unable to detect the supabase_vault extension", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return false
+	}
+
+	return !ext.hasUpdateSecret
+}
+
+// fallBackToLegacyUpdate reports whether a vault.update_secret call failed
+// because the function doesn't exist, in which case later updates go
+// straight to the direct UPDATE.
+func (d *ProviderData) fallBackToLegacyUpdate(ctx context.Context, err error) bool {
+	if !isUndefinedFunctionError(err) {
+		return false
+	}
+
+	d.vaultExtensionMu.Lock()
+	defer d.vaultExtensionMu.Unlock()
+
+	if d.vaultExt == nil {
+		d.vaultExt = &vaultExtension{}
+	}
+	d.vaultExt.hasUpdateSecret = false
+
+	tflog.Warn(ctx, "vault.update_secret does not exist in this supabase_vault release, updating vault.secrets directly", map[string]interface{}{
+		"error": err.Error(),
+	})
+
+	return true
+}
+
+// encryptionTriggerQuery reports whether vault.secrets has an enabled
+// pgsodium trigger encrypting the secret column whenever it is written.
+const encryptionTriggerQuery = `
+	SELECT EXISTS(
+		SELECT 1
+		FROM pg_trigger t
+		JOIN pg_proc p ON p.oid = t.tgfoid
+		JOIN pg_namespace n ON n.oid = p.pronamespace
+		WHERE t.tgrelid = 'vault.secrets'::regclass
+			AND t.tgenabled <> 'D'
+			AND n.nspname = 'vault' AND p.proname = 'secrets_encrypt_secret_secret'
+	)
+`
+
+// checkEncryptionTrigger returns an error unless vault.secrets has the
+// pgsodium encryption trigger, so that writing the secret column directly
+// can never store plaintext. It runs on tx so that the trigger can't be
+// dropped before the write that follows commits.
+func checkEncryptionTrigger(ctx context.Context, tx pgx.Tx) error {
+	var exists bool
+	if err := tx.QueryRow(ctx, encryptionTriggerQuery).Scan(&exists); err != nil {
+		return fmt.Errorf("checking for the pgsodium encryption trigger: %w", err)
+	}
+	if !exists {
+		return fmt.Errorf("vault.secrets has no enabled pgsodium encryption trigger, so writing the secret directly would store it in plaintext; " +
+			"this supabase_vault release has no vault.update_secret either, so the secret can't be updated")
+	}
+
+	return nil
+}
+
+// updateSecretStatement returns the statement that updates a secret with
+// the arguments of vault.update_secret: id, value, name, description and
+// key_id.
+func (d *ProviderData) updateSecretStatement(ctx context.Context) string {
+	if d.legacyUpdate(ctx) {
+		return legacyUpdateSecretQuery
+	}
+
+	return updateSecretQuery
+}
@@ -0,0 +1,67 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestUpdateSecretStatement(t *testing.T) {
+	testCases := map[string]struct {
+		ext      vaultExtension
+		expected string
+	}{
+		"update_secret":         {ext: vaultExtension{version: "0.3.1", hasUpdateSecret: true}, expected: updateSecretQuery},
+		"without update_secret": {ext: vaultExtension{version: "0.2.3"}, expected: legacyUpdateSecretQuery},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			// The cached extension is used without querying the database
+			d := &ProviderData{vaultExt: &testCase.ext}
+
+			if statement := d.updateSecretStatement(context.Background()); statement != testCase.expected {
+				t.Errorf("expected %s, got %s", testCase.expected, statement)
+			}
+		})
+	}
+}
+
+func TestFallBackToLegacyUpdate(t *testing.T) {
+	testCases := map[string]struct {
+		err      error
+		fallBack bool
+	}{
+		"success":            {},
+		"undefined function": {err: &pgconn.PgError{Code: "42883"}, fallBack: true},
+		"wrapped":            {err: fmt.Errorf("calling vault.update_secret: %w", &pgconn.PgError{Code: "42883"}), fallBack: true},
+		"other error":        {err: errors.New("boom")},
+		"permission denied":  {err: &pgconn.PgError{Code: "42501"}},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			d := &ProviderData{vaultExt: &vaultExtension{version: "0.2.3", hasUpdateSecret: true}}
+
+			if fallBack := d.fallBackToLegacyUpdate(context.Background(), testCase.err); fallBack != testCase.fallBack {
+				t.Fatalf("expected %t, got %t", testCase.fallBack, fallBack)
+			}
+
+			// Once the function is known to be missing, later updates branch
+			// up front
+			expected := updateSecretQuery
+			if testCase.fallBack {
+				expected = legacyUpdateSecretQuery
+			}
+			if statement := d.updateSecretStatement(context.Background()); statement != expected {
+				t.Errorf("expected %s, got %s", expected, statement)
+			}
+		})
+	}
+}
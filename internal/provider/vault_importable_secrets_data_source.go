@@ -0,0 +1,144 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/jackc/pgx/v5"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &VaultImportableSecretsDataSource{}
+
+func NewVaultImportableSecretsDataSource() datasource.DataSource {
+	return &VaultImportableSecretsDataSource{}
+}
+
+// VaultImportableSecretsDataSource defines the data source implementation.
+type VaultImportableSecretsDataSource struct {
+	providerData *ProviderData
+}
+
+// VaultImportableSecretsDataSourceModel describes the data source data model.
+type VaultImportableSecretsDataSourceModel struct {
+	Secrets   []VaultImportableSecretModel `tfsdk:"secrets"`
+	ImportIDs types.Map                    `tfsdk:"import_ids"`
+}
+
+// VaultImportableSecretModel describes a secret that can be imported.
+type VaultImportableSecretModel struct {
+	Name     types.String `tfsdk:"name"`
+	ImportID types.String `tfsdk:"import_id"`
+}
+
+func (d *VaultImportableSecretsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_importable_secrets"
+}
+
+func (d *VaultImportableSecretsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists every secret in Supabase Vault with the ID to import it under, for generating `import` blocks (Terraform 1.7 or later) with `for_each`, e.g. " +
+			"`for_each = data.supabase-vault_importable_secrets.all.import_ids`, `to = supabase-vault_secret.imported[each.key]` and `id = each.value`. Secret values are never read.",
+
+		Attributes: map[string]schema.Attribute{
+			"secrets": schema.ListNestedAttribute{
+				MarkdownDescription: "Secrets ordered by name",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							MarkdownDescription: "Secret name, null for secrets stored without one",
+							Computed:            true,
+						},
+						"import_id": schema.StringAttribute{
+							MarkdownDescription: "ID to import the secret as a `supabase-vault_secret` with, its UUID",
+							Computed:            true,
+						},
+					},
+				},
+			},
+			"import_ids": schema.MapAttribute{
+				MarkdownDescription: "Import IDs keyed by secret name. Secrets without a name are only listed in `secrets`.",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *VaultImportableSecretsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.providerData = providerData
+}
+
+func (d *VaultImportableSecretsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data VaultImportableSecretsDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// A NULL limit returns every secret
+	rows, err := collectRows(ctx, d.providerData, pgx.RowToStructByName[secretMetadataRow], listSecretMetadataQuery, nil, 0)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to list vault secrets",
+			withRemediation(fmt.Sprintf("Error reading secret metadata: %s", err), err),
+		)
+		return
+	}
+
+	data.Secrets = make([]VaultImportableSecretModel, 0, len(rows))
+	importIDs := make(map[string]string, len(rows))
+	for _, row := range rows {
+		secret := VaultImportableSecretModel{
+			Name:     types.StringNull(),
+			ImportID: types.StringValue(row.ID),
+		}
+
+		// Names are decoded the way importing a secret decodes them
+		if row.Name != nil {
+			name := decodeSecretName(*row.Name, d.providerData.AllowInvalidUTF8Names)
+			secret.Name = types.StringValue(name)
+			importIDs[name] = row.ID
+		}
+
+		data.Secrets = append(data.Secrets, secret)
+	}
+
+	importIDsValue, diags := types.MapValueFrom(ctx, types.StringType, importIDs)
+	resp.Diagnostics.Append(diags...)
+	data.ImportIDs = importIDsValue
+
+	tflog.Trace(ctx, "listed importable vault secrets", map[string]interface{}{
+		"count": len(rows),
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,53 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/compare"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestAccVaultImportableSecretsDataSource(t *testing.T) {
+	// Skip if TF_ACC is not set
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests skipped unless env 'TF_ACC' set")
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccVaultImportableSecretsDataSourceConfig(),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.CompareValuePairs(
+						"data.supabase-vault_importable_secrets.all",
+						tfjsonpath.New("import_ids").AtMapKey("test-importable-secrets"),
+						"supabase-vault_secret.test",
+						tfjsonpath.New("id"),
+						compare.ValuesSame(),
+					),
+				},
+			},
+		},
+	})
+}
+
+func testAccVaultImportableSecretsDataSourceConfig() string {
+	return testAccProviderConfig() + `
+resource "supabase-vault_secret" "test" {
+  name  = "test-importable-secrets"
+  value = "importable-value"
+}
+
+data "supabase-vault_importable_secrets" "all" {
+  depends_on = [supabase-vault_secret.test]
+}
+`
+}
@@ -0,0 +1,143 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/jackc/pgx/v5"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &VaultKeyDataSource{}
+
+func NewVaultKeyDataSource() datasource.DataSource {
+	return &VaultKeyDataSource{}
+}
+
+// VaultKeyDataSource defines the data source implementation.
+type VaultKeyDataSource struct {
+	providerData *ProviderData
+}
+
+// VaultKeyDataSourceModel describes the data source data model.
+type VaultKeyDataSourceModel struct {
+	Name    types.String `tfsdk:"name"`
+	ID      types.String `tfsdk:"id"`
+	KeyType types.String `tfsdk:"key_type"`
+	Status  types.String `tfsdk:"status"`
+}
+
+func (d *VaultKeyDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_key"
+}
+
+func (d *VaultKeyDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Looks up a pgsodium encryption key by name, so its ID can be referenced as a secret's `key_id` without hard-coding UUIDs.",
+
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Name of the pgsodium key",
+				Required:            true,
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Key UUID, suitable for a secret's `key_id`",
+				Computed:            true,
+			},
+			"key_type": schema.StringAttribute{
+				MarkdownDescription: "pgsodium key type (e.g. `aead-det`)",
+				Computed:            true,
+			},
+			"status": schema.StringAttribute{
+				MarkdownDescription: "Key status (`default`, `valid`, `invalid` or `expired`)",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *VaultKeyDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.providerData = providerData
+}
+
+func (d *VaultKeyDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data VaultKeyDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	query := `
+		SELECT id, key_type::text, status::text
+		FROM pgsodium.key
+		WHERE name = $1
+	`
+
+	var id string
+	var keyType, status sql.NullString
+	err := d.providerData.queryRow(ctx, query, data.Name.ValueString()).Scan(&id, &keyType, &status)
+
+	if err == pgx.ErrNoRows {
+		resp.Diagnostics.AddError(
+			"Key not found",
+			fmt.Sprintf("No pgsodium key found with name: %s", data.Name.ValueString()),
+		)
+		return
+	}
+
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to read pgsodium key",
+			withRemediation(fmt.Sprintf("Error looking up key by name: %s", err), err),
+		)
+		return
+	}
+
+	data.ID = types.StringValue(id)
+	data.KeyType = types.StringPointerValue(nullStringPointer(keyType))
+	data.Status = types.StringPointerValue(nullStringPointer(status))
+
+	tflog.Trace(ctx, "read a pgsodium key", map[string]interface{}{
+		"id":   id,
+		"name": data.Name.ValueString(),
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// nullStringPointer converts a nullable column value to a pointer, nil when NULL.
+func nullStringPointer(value sql.NullString) *string {
+	if !value.Valid {
+		return nil
+	}
+
+	return &value.String
+}
@@ -0,0 +1,58 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestAccVaultKeyDataSource(t *testing.T) {
+	// Skip if TF_ACC is not set
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests skipped unless env 'TF_ACC' set")
+	}
+
+	// Requires a pre-existing named pgsodium key
+	keyName := os.Getenv("SUPABASE_KEY_NAME")
+	if keyName == "" {
+		t.Skip("Acceptance test skipped unless env 'SUPABASE_KEY_NAME' set")
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccVaultKeyDataSourceConfig(keyName),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.supabase-vault_key.test",
+						tfjsonpath.New("name"),
+						knownvalue.StringExact(keyName),
+					),
+					statecheck.ExpectKnownValue(
+						"data.supabase-vault_key.test",
+						tfjsonpath.New("id"),
+						knownvalue.NotNull(),
+					),
+				},
+			},
+		},
+	})
+}
+
+func testAccVaultKeyDataSourceConfig(name string) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+data "supabase-vault_key" "test" {
+  name = %q
+}
+`, name)
+}
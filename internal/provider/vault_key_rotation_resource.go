@@ -0,0 +1,277 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/jackc/pgx/v5"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &VaultKeyRotationResource{}
+
+func NewVaultKeyRotationResource() resource.Resource {
+	return &VaultKeyRotationResource{}
+}
+
+// VaultKeyRotationResource defines the resource implementation.
+type VaultKeyRotationResource struct {
+	providerData *ProviderData
+}
+
+// VaultKeyRotationModel describes the resource data model.
+type VaultKeyRotationModel struct {
+	ID            types.String `tfsdk:"id"`
+	Name          types.String `tfsdk:"name"`
+	PreviousKeyID types.String `tfsdk:"previous_key_id"`
+	Status        types.String `tfsdk:"status"`
+}
+
+func (r *VaultKeyRotationResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_key_rotation"
+}
+
+func (r *VaultKeyRotationResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Rotates a pgsodium encryption key: creates a new named key and, optionally, marks the key it replaces as expired. " +
+			"Secrets aren't re-encrypted automatically; point their `key_id` at the new key to migrate them. " +
+			"Requires a Vault installation backed by pgsodium; newer Vault releases without pgsodium key management are rejected with an error. " +
+			"Destroying this resource leaves the created key in place, as secrets may still depend on it.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "UUID of the newly created key",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Name of the new key. Changing it creates another key.",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"previous_key_id": schema.StringAttribute{
+				MarkdownDescription: "UUID of the key being rotated out. It is marked `expired` once the new key exists.",
+				Optional:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"status": schema.StringAttribute{
+				MarkdownDescription: "Current status of the new key",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (r *VaultKeyRotationResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.providerData = providerData
+}
+
+func (r *VaultKeyRotationResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.providerData.logContext(ctx)
+
+	release, ok := r.providerData.beginOperation(ctx, &resp.Diagnostics)
+	if !ok {
+		return
+	}
+	defer release()
+
+	if !r.providerData.checkWritable(&resp.Diagnostics, "rotate a pgsodium key") {
+		return
+	}
+
+	var data VaultKeyRotationModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.PreviousKeyID.IsNull() {
+		if err := validateSecretID(data.PreviousKeyID.ValueString()); err != nil {
+			resp.Diagnostics.AddError(
+				"Invalid previous_key_id",
+				err.Error(),
+			)
+			return
+		}
+	}
+
+	// Key management moved out of pgsodium in newer Vault releases
+	supportQuery := `
+		SELECT EXISTS(
+			SELECT 1
+			FROM pg_proc p
+			JOIN pg_namespace n ON n.oid = p.pronamespace
+			WHERE n.nspname = 'pgsodium' AND p.proname = 'create_key'
+		)
+	`
+	var supported bool
+	if err := r.providerData.queryRow(ctx, supportQuery).Scan(&supported); err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to rotate pgsodium key",
+			withRemediation(fmt.Sprintf("Error checking for pgsodium key management: %s", err), err),
+		)
+		return
+	}
+	if !supported {
+		resp.Diagnostics.AddError(
+			"Key rotation not supported",
+			"The installed database has no pgsodium.create_key() function. Key rotation requires a pgsodium-backed Vault installation.",
+		)
+		return
+	}
+
+	// Create the new key and expire the old one together, so a failure can't
+	// leave the old key expired without a replacement
+	var id, status string
+	err := r.providerData.withTx(ctx, func(tx pgx.Tx) error {
+		createQuery := "SELECT id, status::text FROM pgsodium.create_key(name => $1)"
+		if err := tx.QueryRow(ctx, createQuery, data.Name.ValueString()).Scan(&id, &status); err != nil {
+			return fmt.Errorf("creating key: %w", err)
+		}
+
+		if data.PreviousKeyID.IsNull() {
+			return nil
+		}
+
+		expireQuery := "UPDATE pgsodium.key SET status = 'expired' WHERE id = $1"
+		tag, err := tx.Exec(ctx, expireQuery, data.PreviousKeyID.ValueString())
+		if err != nil {
+			return fmt.Errorf("expiring previous key: %w", err)
+		}
+		if tag.RowsAffected() == 0 {
+			return fmt.Errorf("previous key %s does not exist", data.PreviousKeyID.ValueString())
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to rotate pgsodium key",
+			withRemediation(fmt.Sprintf("Error rotating key: %s", err), err),
+		)
+		return
+	}
+
+	data.ID = types.StringValue(id)
+	data.Status = types.StringValue(status)
+
+	tflog.Trace(ctx, "rotated a pgsodium key", map[string]interface{}{
+		"id":              id,
+		"previous_key_id": data.PreviousKeyID.ValueString(),
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *VaultKeyRotationResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = r.providerData.logContext(ctx)
+
+	release, ok := r.providerData.beginOperation(ctx, &resp.Diagnostics)
+	if !ok {
+		return
+	}
+	defer release()
+
+	var data VaultKeyRotationModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	query := "SELECT status::text FROM pgsodium.key WHERE id = $1"
+
+	var status string
+	err := r.providerData.queryRow(ctx, query, data.ID.ValueString()).Scan(&status)
+
+	if err == pgx.ErrNoRows {
+		// Key was removed outside Terraform
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to read pgsodium key",
+			withRemediation(fmt.Sprintf("Error reading key status: %s", err), err),
+		)
+		return
+	}
+
+	data.Status = types.StringValue(status)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *VaultKeyRotationResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data VaultKeyRotationModel
+
+	// Every configurable attribute requires replacement, so there is nothing
+	// to change in the database
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *VaultKeyRotationResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = r.providerData.logContext(ctx)
+
+	release, ok := r.providerData.beginOperation(ctx, &resp.Diagnostics)
+	if !ok {
+		return
+	}
+	defer release()
+
+	var data VaultKeyRotationModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Deleting the key could make secrets encrypted with it unreadable
+	resp.Diagnostics.AddWarning(
+		"pgsodium key left in place",
+		fmt.Sprintf("Key %s was removed from Terraform state but not from the database, as secrets may still be encrypted with it.", data.ID.ValueString()),
+	)
+}
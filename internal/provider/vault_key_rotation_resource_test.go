@@ -0,0 +1,62 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestAccVaultKeyRotationResource(t *testing.T) {
+	// Skip if TF_ACC is not set
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests skipped unless env 'TF_ACC' set")
+	}
+
+	// Key rotation needs a pgsodium-backed Vault installation
+	if os.Getenv("SUPABASE_PGSODIUM") == "" {
+		t.Skip("Acceptance test skipped unless env 'SUPABASE_PGSODIUM' set")
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccVaultKeyRotationResourceConfig("test-rotation-old", "test-rotation-new"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"supabase-vault_key_rotation.new",
+						tfjsonpath.New("id"),
+						knownvalue.NotNull(),
+					),
+					statecheck.ExpectKnownValue(
+						"supabase-vault_key_rotation.new",
+						tfjsonpath.New("status"),
+						knownvalue.StringExact("valid"),
+					),
+				},
+			},
+		},
+	})
+}
+
+func testAccVaultKeyRotationResourceConfig(oldName, newName string) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "supabase-vault_key_rotation" "old" {
+  name = %q
+}
+
+resource "supabase-vault_key_rotation" "new" {
+  name            = %q
+  previous_key_id = supabase-vault_key_rotation.old.id
+}
+`, oldName, newName)
+}
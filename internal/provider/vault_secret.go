@@ -8,10 +8,13 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -22,6 +25,8 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &VaultSecretResource{}
 var _ resource.ResourceWithImportState = &VaultSecretResource{}
+var _ resource.ResourceWithValidateConfig = &VaultSecretResource{}
+var _ resource.ResourceWithModifyPlan = &VaultSecretResource{}
 
 func NewVaultSecretResource() resource.Resource {
 	return &VaultSecretResource{}
@@ -39,6 +44,35 @@ type VaultSecretModel struct {
 	Value       types.String `tfsdk:"value"`
 	KeyID       types.String `tfsdk:"key_id"`
 	Description types.String `tfsdk:"description"`
+	Labels      types.Map    `tfsdk:"labels"`
+	Nonce       types.String `tfsdk:"nonce"`
+	CreatedAt   types.String `tfsdk:"created_at"`
+	UpdatedAt   types.String `tfsdk:"updated_at"`
+	CreatedBy   types.String `tfsdk:"created_by"`
+	KeyStatus   types.String `tfsdk:"key_status"`
+
+	ValueWO        types.String `tfsdk:"value_wo"`
+	ValueWOVersion types.Int64  `tfsdk:"value_wo_version"`
+
+	ValueTemplate types.Bool   `tfsdk:"value_template"`
+	Vars          types.Map    `tfsdk:"vars"`
+	ValueHash     types.String `tfsdk:"value_hash"`
+	ValuePattern  types.String `tfsdk:"value_pattern"`
+
+	DescriptionChecksum types.String `tfsdk:"description_checksum"`
+	ManagedByVersion    types.String `tfsdk:"managed_by_version"`
+
+	AdoptExisting    types.Bool `tfsdk:"adopt_existing"`
+	Immutable        types.Bool `tfsdk:"immutable"`
+	ValidateOnly     types.Bool `tfsdk:"validate_only"`
+	ContentAddressed types.Bool `tfsdk:"content_addressed"`
+
+	ExpiresAt types.String `tfsdk:"expires_at"`
+	IsExpired types.Bool   `tfsdk:"is_expired"`
+
+	Environment types.String `tfsdk:"environment"`
+
+	Connection types.Object `tfsdk:"connection"`
 }
 
 func (r *VaultSecretResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -47,6 +81,7 @@ func (r *VaultSecretResource) Metadata(ctx context.Context, req resource.Metadat
 
 func (r *VaultSecretResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		Version:             vaultSecretSchemaVersion,
 		MarkdownDescription: "Manages a secret in Supabase Vault. Secrets are encrypted and stored securely in the database.",
 
 		Attributes: map[string]schema.Attribute{
@@ -58,30 +93,516 @@ func (r *VaultSecretResource) Schema(ctx context.Context, req resource.SchemaReq
 				},
 			},
 			"name": schema.StringAttribute{
-				MarkdownDescription: "Unique name for the secret",
+				MarkdownDescription: "Unique name for the secret, at most 255 bytes",
 				Required:            true,
 			},
 			"value": schema.StringAttribute{
-				MarkdownDescription: "Secret value to encrypt and store",
-				Required:            true,
+				MarkdownDescription: "Secret value to encrypt and store. One of `value` or `value_wo` must be set when the secret is created. Removing both afterwards leaves the stored value untouched, so it can be managed outside Terraform while the name and description stay Terraform-owned.",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"value_wo": schema.StringAttribute{
+				MarkdownDescription: "Write-only secret value to encrypt and store. Unlike `value` it is never written to the plan or state, e.g. `value_wo = ephemeral.supabase-vault_secret.source.value`. Requires Terraform 1.11 or later; change `value_wo_version` to store a new value.",
+				Optional:            true,
 				Sensitive:           true,
+				WriteOnly:           true,
+			},
+			"value_wo_version": schema.Int64Attribute{
+				MarkdownDescription: "Version of `value_wo`. Terraform can't see changes to a write-only value, so changing this is what triggers an update of the stored value.",
+				Optional:            true,
 			},
 			"key_id": schema.StringAttribute{
-				MarkdownDescription: "Optional encryption key ID (if using custom keys). This value is read from the database and preserved even if not specified in the configuration.",
+				MarkdownDescription: "Optional encryption key ID (if using custom keys). This value is read from the database and preserved even if not specified in the configuration. Changing it re-encrypts the existing secret under the new key.",
 				Optional:            true,
 				Computed:            true,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"created_at": schema.StringAttribute{
+				MarkdownDescription: "When the secret was created, as an RFC 3339 timestamp in the provider's `timestamp_timezone`",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"updated_at": schema.StringAttribute{
+				MarkdownDescription: "When the secret was last changed, as an RFC 3339 timestamp in the provider's `timestamp_timezone`",
+				Computed:            true,
+			},
+			"key_status": schema.StringAttribute{
+				MarkdownDescription: "Status of the pgsodium key the secret is encrypted with, from `pgsodium.key`: `default`, `valid`, `invalid` or `expired`. " +
+					"Refreshing a secret whose key is `invalid` or `expired`, e.g. after a rotation, shows a warning, as it should be re-encrypted by setting `key_id` to a valid key. " +
+					"Null when the secret has no key, or when `pgsodium.key` doesn't exist or can't be read, as with newer Vault releases or the Management API.",
+				Computed: true,
+			},
+			"created_by": schema.StringAttribute{
+				MarkdownDescription: "The role that created the secret, read from a `created_by` or `owner` column of `vault.secrets`. Supabase Vault has neither column by default, in which case, or when the connecting role may not read the column, this is null.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
 			"description": schema.StringAttribute{
-				MarkdownDescription: "Optional description for the secret",
+				MarkdownDescription: "Optional description for the secret. The provider appends a managed-by footer to the stored description and strips it again on read. " +
+					"Only a footer ending the stored description is stripped, so descriptions of imported secrets read back exactly as stored, even when they mention the footer text.",
+				Optional: true,
+			},
+			"labels": schema.MapAttribute{
+				MarkdownDescription: "Key/value metadata for the secret, e.g. `owner` or `rotation_policy`, for policies and tooling to key on. Vault has no column for it, so the labels are stored as a JSON block in the stored description, between `description` and the managed-by footer.",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"nonce": schema.StringAttribute{
+				MarkdownDescription: "Hex-encoded 16-byte nonce to encrypt the secret with instead of one Vault generates, for deterministic ciphertexts across databases. " +
+					"Requires a pgsodium-backed Vault installation, whose encryption trigger reads the nonce from the new row; newer Vault releases are rejected with an error. " +
+					"Vault encrypts deterministically, so the nonce and key together fully determine the ciphertext: two secrets sharing both reveal whether their values are equal, and the nonce is reused every time the value is updated. " +
+					"Only set this when a compliance requirement calls for it. Changing it replaces the secret.",
+				Optional: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"value_pattern": schema.StringAttribute{
+				MarkdownDescription: "Regular expression, in [Go syntax](https://pkg.go.dev/regexp/syntax), the value must match before it is stored, e.g. `^sk_live_[A-Za-z0-9]{24}$` for an API key. " +
+					"The value is checked at plan time when it is known, otherwise during apply. Templates are checked once rendered. The pattern isn't anchored, so use `^` and `$` to match the whole value. " +
+					"Errors never include the value.",
+				Optional: true,
+			},
+			"value_template": schema.BoolAttribute{
+				MarkdownDescription: "Treat `value` as a Go [text/template](https://pkg.go.dev/text/template) rendered against `vars` before it is stored, e.g. `\"{{.user}}:{{.pass}}\"`. Referencing an undefined variable is an error. Defaults to `false`.",
+				Optional:            true,
+			},
+			"vars": schema.MapAttribute{
+				MarkdownDescription: "Variables available to `value` when `value_template` is enabled.",
+				ElementType:         types.StringType,
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"value_hash": schema.StringAttribute{
+				MarkdownDescription: "SHA-256 hex digest of the value stored in Vault. Changes only when the stored value changes, giving a stable signal for drift without exposing the value.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					valueHashPlanModifier{},
+				},
+			},
+			"description_checksum": schema.StringAttribute{
+				MarkdownDescription: "SHA-256 hex digest of the description as shown in Terraform (without the managed-by footer). A stable signal that the description was edited outside Terraform.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					descriptionChecksumPlanModifier{},
+				},
+			},
+			"managed_by_version": schema.StringAttribute{
+				MarkdownDescription: "Version of the provider that last wrote the secret's description, taken from its managed-by footer, e.g. to find secrets last touched by an old provider version before upgrading. " +
+					"Null when the stored description has no footer, such as for secrets without a description or created outside Terraform.",
+				Computed: true,
+			},
+			"connection": connectionOverrideSchema(),
+			"adopt_existing": schema.BoolAttribute{
+				MarkdownDescription: "Adopt a secret that already exists with the same `name` instead of failing to create a duplicate. The existing secret is updated to match the configuration, and its `id` is shown in the plan. Defaults to `false`.",
 				Optional:            true,
 			},
+			"immutable": schema.BoolAttribute{
+				MarkdownDescription: "Refuse to change the stored value once the secret is created, e.g. for write-once bootstrap tokens. Other attributes such as `description` can still be updated; a new value requires replacing the resource with `terraform apply -replace`. Defaults to `false`.",
+				Optional:            true,
+			},
+			"validate_only": schema.BoolAttribute{
+				MarkdownDescription: "Only check that the secret could be created, e.g. to validate permissions and `key_id` in CI before a real apply. Applying runs `vault.create_secret` in a transaction that is rolled back and reports the outcome, so no secret is ever stored and `id` stays null. " +
+					"Changing it replaces the resource. Defaults to `false`.",
+				Optional: true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"content_addressed": schema.BoolAttribute{
+				MarkdownDescription: "Key the secret on the SHA-256 hash of its value, stored as the `" + contentHashLabel + "` label, so an identical value is never stored twice. " +
+					"Creating the resource reuses a secret with the same name and value instead of failing, and fails if the value is already stored under another name, reporting that name. Requires `value`. " +
+					"Changing it replaces the resource. Defaults to `false`.",
+				Optional: true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"expires_at": schema.StringAttribute{
+				MarkdownDescription: "When the secret expires, as an RFC 3339 timestamp (e.g. `2030-01-31T00:00:00Z`), stored as the `" + expiryLabel + "` label. " +
+					"Purely informational: nothing is deleted when it passes, but `is_expired` reports it, e.g. to drive alerts or rotation.",
+				Optional: true,
+			},
+			"is_expired": schema.BoolAttribute{
+				MarkdownDescription: "Whether `expires_at` had passed when the secret was last refreshed, null without `expires_at`",
+				Computed:            true,
+			},
+			"environment": schema.StringAttribute{
+				MarkdownDescription: "Environment the secret belongs to, e.g. `prod` or `staging`: 1 to 32 letters, digits or `-`. Stored as the `" + environmentLabel + "` label and, " +
+					"unless the provider's `environment_naming` is `label`, as a `<environment>_` prefix of the stored name, so one configuration can manage the same `name` in several environments. " +
+					"Importing a secret labelled with an environment sets it. Changing it replaces the secret.",
+				Optional: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
 		},
 	}
 }
 
+func (r *VaultSecretResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data VaultSecretModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Neither may be set once the secret exists; ModifyPlan requires one on create
+	if !data.Value.IsNull() && !data.ValueWO.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("value_wo"),
+			"Conflicting value attributes",
+			"Only one of value or value_wo can be set.",
+		)
+	}
+
+	validateConnectionOverride(ctx, data.Connection, &resp.Diagnostics)
+
+	if isKnown(data.Name) {
+		if err := validateSecretName(data.Name.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("name"),
+				"Invalid secret name",
+				err.Error(),
+			)
+		}
+	}
+
+	if isKnown(data.Description) {
+		if err := validateDescription(data.Description.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("description"),
+				"Invalid description",
+				err.Error(),
+			)
+		}
+	}
+
+	if isKnown(data.Nonce) {
+		if err := validateNonce(data.Nonce.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("nonce"),
+				"Invalid nonce",
+				err.Error(),
+			)
+		}
+	}
+
+	// An adopted secret keeps the nonce it was encrypted with
+	if !data.Nonce.IsNull() && data.AdoptExisting.ValueBool() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("nonce"),
+			"Conflicting nonce attribute",
+			"nonce can't be set together with adopt_existing, as an adopted secret keeps its existing nonce.",
+		)
+	}
+
+	// Adopting or pinning the nonce only makes sense for a secret that is stored
+	if data.ValidateOnly.ValueBool() && (data.AdoptExisting.ValueBool() || !data.Nonce.IsNull()) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("validate_only"),
+			"Conflicting validate_only attribute",
+			"validate_only can't be set together with adopt_existing or nonce, as no secret is stored.",
+		)
+	}
+
+	if data.ContentAddressed.ValueBool() {
+		// The hash has to be recomputed on every update, and nothing may reach
+		// state from a write-only value
+		if !data.ValueWO.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("content_addressed"),
+				"Conflicting content_addressed attribute",
+				"content_addressed requires value; it can't be used with value_wo.",
+			)
+		}
+
+		// Reusing a secret with the same content already adopts it
+		if data.AdoptExisting.ValueBool() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("content_addressed"),
+				"Conflicting content_addressed attribute",
+				"content_addressed can't be set together with adopt_existing.",
+			)
+		}
+
+		if _, ok := data.Labels.Elements()[contentHashLabel]; ok {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("labels").AtMapKey(contentHashLabel),
+				"Reserved label",
+				fmt.Sprintf("The %s label is set by content_addressed.", contentHashLabel),
+			)
+		}
+	}
+
+	if isKnown(data.Environment) {
+		if err := validateEnvironment(data.Environment.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("environment"),
+				"Invalid environment attribute",
+				err.Error(),
+			)
+		}
+
+		if _, ok := data.Labels.Elements()[environmentLabel]; ok {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("labels").AtMapKey(environmentLabel),
+				"Reserved label",
+				fmt.Sprintf("The %s label is set by environment.", environmentLabel),
+			)
+		}
+	}
+
+	if isKnown(data.ExpiresAt) {
+		if _, err := parseExpiry(data.ExpiresAt.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("expires_at"),
+				"Invalid expires_at attribute",
+				err.Error(),
+			)
+		}
+
+		if _, ok := data.Labels.Elements()[expiryLabel]; ok {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("labels").AtMapKey(expiryLabel),
+				"Reserved label",
+				fmt.Sprintf("The %s label is set by expires_at.", expiryLabel),
+			)
+		}
+	}
+
+	// Check the value at plan time when it is known; apply checks it
+	// otherwise. Template errors are reported below.
+	if isKnown(data.ValuePattern) {
+		if _, err := compileValuePattern(data.ValuePattern.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("value_pattern"),
+				"Invalid value_pattern",
+				err.Error(),
+			)
+		} else if data.valueKnown() {
+			if value, diags := data.secretValue(ctx); !diags.HasError() {
+				checkValuePattern(data, value, &resp.Diagnostics)
+			}
+		}
+	}
+
+	// Render the template at plan time when everything it depends on is known,
+	// so template errors surface before apply
+	if data.ValueTemplate.ValueBool() && !data.Value.IsUnknown() && !data.ValueWO.IsUnknown() && !data.Vars.IsUnknown() {
+		for _, element := range data.Vars.Elements() {
+			if element.IsUnknown() {
+				return
+			}
+		}
+
+		_, diags := data.secretValue(ctx)
+		resp.Diagnostics.Append(diags...)
+	}
+}
+
+func (r *VaultSecretResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to plan when the resource is being destroyed
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	r.warnValueInState(ctx, req, resp)
+	r.checkPlannedValue(ctx, req, resp)
+	r.checkPlannedKey(ctx, req, resp)
+
+	// Everything below only concerns creates
+	if !req.State.Raw.IsNull() {
+		return
+	}
+
+	var data VaultSecretModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	// Write-only values are only available from the configuration
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("value_wo"), &data.ValueWO)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// An existing secret may leave its value to be managed elsewhere, but a
+	// new one needs something to store
+	if data.Value.IsNull() && data.ValueWO.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("value"),
+			"Missing value attribute",
+			"One of value or value_wo must be set when the secret is created.",
+		)
+		return
+	}
+
+	// Looking up a secret to adopt needs a configured provider, and the
+	// database to look in isn't known until the override is
+	if r.providerData == nil || !data.AdoptExisting.ValueBool() || !isKnown(data.Name) || data.Connection.IsUnknown() {
+		return
+	}
+
+	if !r.useConnection(ctx, data.Connection, &resp.Diagnostics) {
+		return
+	}
+
+	secretName := r.secretName(data, &resp.Diagnostics)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	existingID, found, err := r.providerData.secrets().lookupSecretID(ctx, secretName)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to look up existing vault secret",
+			withRemediation(fmt.Sprintf("Error looking up secret %q: %s", data.Name.ValueString(), err), err),
+		)
+		return
+	}
+
+	if !found {
+		return
+	}
+
+	// Surface the adoption in the plan so apply holds no surprises
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("id"), existingID)...)
+	resp.Diagnostics.AddAttributeWarning(
+		path.Root("adopt_existing"),
+		"Existing vault secret will be adopted",
+		fmt.Sprintf("A secret named %q already exists (%s). Apply will update it in place to match the configuration rather than create a new one.", data.Name.ValueString(), existingID),
+	)
+}
+
+// checkPlannedValue rejects an empty value at plan time rather than during
+// apply, once the value and any template variables are known.
+func (r *VaultSecretResource) checkPlannedValue(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if r.providerData == nil || r.providerData.AllowEmptyValue {
+		return
+	}
+
+	var data VaultSecretModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	// Write-only values are only available from the configuration
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("value_wo"), &data.ValueWO)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// An omitted value leaves the stored one alone
+	if data.Value.IsNull() && data.ValueWO.IsNull() {
+		return
+	}
+
+	if data.Value.IsUnknown() || data.ValueWO.IsUnknown() || data.Vars.IsUnknown() || data.ValueTemplate.IsUnknown() {
+		return
+	}
+
+	// Rendering errors are reported by ValidateConfig
+	value, diags := data.secretValue(ctx)
+	if diags.HasError() {
+		return
+	}
+
+	r.providerData.checkEmptyValue(data, value, &resp.Diagnostics)
+}
+
+// checkPlannedKey rejects a key_id that doesn't name a pgsodium key at plan
+// time, rather than leaving vault.create_secret to fail during apply. Keys
+// that can't be looked up, e.g. through the Management API, aren't checked.
+func (r *VaultSecretResource) checkPlannedKey(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if r.providerData == nil || r.providerData.managementAPI != nil {
+		return
+	}
+
+	var plan, state VaultSecretModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Only a key the secret is about to be encrypted with needs to exist
+	if !keyChanged(plan, state) || plan.Connection.IsUnknown() {
+		return
+	}
+
+	// Leave r.providerData alone; the rest of ModifyPlan picks its own
+	providerData, err := r.providerData.forConnection(ctx, plan.Connection)
+	if err != nil {
+		return
+	}
+
+	keyID := plan.KeyID.ValueString()
+	exists, err := providerData.keyExists(ctx, keyID)
+	if err != nil && isKeyCheckUnavailable(err) {
+		tflog.Debug(ctx, "pgsodium keys can't be looked up, not checking key_id", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		tflog.Warn(ctx, "Unable to check that key_id exists, leaving it to apply", map[string]interface{}{
+			"key_id": keyID,
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	if !exists {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("key_id"),
+			"Key not found",
+			fmt.Sprintf("No pgsodium key with id %q exists in pgsodium.key. Use the id of an existing key, e.g. from the supabase-vault_key data source, or remove key_id to use Vault's default key.", keyID),
+		)
+	}
+}
+
+// warnValueInState warns when the plan writes a new value to state, unless
+// the provider suppresses the warning.
+func (r *VaultSecretResource) warnValueInState(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if r.providerData != nil && r.providerData.SuppressStateWarnings {
+		return
+	}
+
+	var planned, prior types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("value"), &planned)...)
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("value"), &prior)...)
+	}
+
+	// Only a value that changes is newly written
+	if planned.IsNull() || planned.Equal(prior) {
+		return
+	}
+
+	resp.Diagnostics.AddAttributeWarning(
+		path.Root("value"),
+		"Secret value will be stored in state",
+		"The value is written to the Terraform state in plaintext, where anyone who can read the state can read it. "+
+			"Use value_wo (Terraform 1.11 or later) to keep it out of plan and state, and value_hash to detect changes to the stored value. "+
+			"Set the provider's suppress_state_warnings to hide this warning.",
+	)
+}
+
 func (r *VaultSecretResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	// Prevent panic if the provider has not been configured.
 	if req.ProviderData == nil {
@@ -102,18 +623,215 @@ func (r *VaultSecretResource) Configure(ctx context.Context, req resource.Config
 	r.providerData = providerData
 }
 
-// appendManagedByFooter appends a footer to the description indicating the secret is managed by Terraform.
-func appendManagedByFooter(description string, version string) string {
-	footer := fmt.Sprintf("\n\n---\nManaged by terraform-provider-supabase-vault v%s", version)
+// useConnection points the resource at the database chosen by its
+// connection override, if any, adding an error to diags when it can't be
+// reached.
+func (r *VaultSecretResource) useConnection(ctx context.Context, override types.Object, diags *diag.Diagnostics) bool {
+	providerData, err := r.providerData.forConnection(ctx, override)
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("connection"),
+			"Unable to connect to PostgreSQL",
+			withRemediation(fmt.Sprintf("Unable to use the resource's connection override: %s", err), err),
+		)
+		return false
+	}
+
+	r.providerData = providerData
+
+	return true
+}
+
+// descriptionOnlyChange reports whether the planned secret differs from the
+// prior state in its description alone. The stored value is compared by hash,
+// so a template whose rendered output is unchanged isn't re-encrypted.
+func descriptionOnlyChange(plan, state VaultSecretModel) bool {
+	return !state.ValueHash.IsNull() &&
+		plan.ValueHash.Equal(state.ValueHash) &&
+		plan.Name.Equal(state.Name) &&
+		plan.KeyID.Equal(state.KeyID)
+}
+
+// valueChanged reports whether the planned secret stores a different value
+// than the prior state. Changes to a write-only value are only visible
+// through value_wo_version.
+func valueChanged(plan, state VaultSecretModel) bool {
+	if !plan.ValueHash.Equal(state.ValueHash) {
+		return true
+	}
+
+	return plan.Value.IsNull() && !plan.ValueWOVersion.Equal(state.ValueWOVersion)
+}
+
+// keyIDReadAttempts and keyIDReadBackoff bound the retries of the key_id
+// and timestamp read that follows creating a secret.
+const (
+	keyIDReadAttempts = 4
+	keyIDReadBackoff  = 50 * time.Millisecond
+)
+
+// readVaultAttributes reads the attributes Vault sets on a secret into data:
+// the key it is encrypted with, null when it has none, and its timestamps.
+func (r *VaultSecretResource) readVaultAttributes(ctx context.Context, secretID string, data *VaultSecretModel) error {
+	secret, err := r.providerData.secrets().readSecret(ctx, secretID)
+	if err != nil {
+		return err
+	}
+
+	data.KeyID = types.StringPointerValue(nullStringPointer(secret.KeyID))
+	data.CreatedAt = r.providerData.timestampValue(secret.CreatedAt)
+	data.UpdatedAt = r.providerData.timestampValue(secret.UpdatedAt)
+	data.CreatedBy = types.StringPointerValue(nullStringPointer(secret.CreatedBy))
+	data.KeyStatus = r.readKeyStatus(ctx, secret.KeyID)
+
+	return nil
+}
+
+// readKeyStatus returns the status of the key a secret is encrypted with.
+// The status is informational, so a failure to read it only leaves it null.
+func (r *VaultSecretResource) readKeyStatus(ctx context.Context, keyID sql.NullString) types.String {
+	status, err := r.providerData.keyStatus(ctx, keyID)
+	if err != nil {
+		tflog.Warn(ctx, "Unable to read the status of the secret's key", map[string]interface{}{
+			"key_id": keyID.String,
+			"error":  err.Error(),
+		})
+	}
+
+	return status
+}
+
+// createSecretWithNonce inserts a secret into vault.secrets directly, so that
+// Vault encrypts it with nonce rather than one it generates. Only
+// pgsodium-backed Vault installations encrypt rows with a trigger; newer
+// releases encrypt inside vault.create_secret(), where a direct insert would
+// store the value in plaintext, so those are refused.
+func (r *VaultSecretResource) createSecretWithNonce(ctx context.Context, value, name, description string, keyID types.String, nonce string) (sql.NullString, error) {
+	var secretID sql.NullString
+
+	err := r.providerData.withTx(ctx, func(tx pgx.Tx) error {
+		var supported bool
+		if err := tx.QueryRow(ctx, encryptionTriggerQuery).Scan(&supported); err != nil {
+			return fmt.Errorf("checking for the pgsodium encryption trigger: %w", err)
+		}
+		if !supported {
+			return fmt.Errorf("vault.secrets has no pgsodium encryption trigger; nonce requires a pgsodium-backed Vault installation")
+		}
+
+		// Leave key_id out when unset so the column default picks the key, as
+		// the trigger stores no ciphertext for a NULL key_id
+		query := `
+			INSERT INTO vault.secrets (secret, name, description, nonce)
+			VALUES ($1, $2, $3, decode($4, 'hex'))
+			RETURNING id
+		`
+		args := []any{value, name, description, nonce}
+		if isKnown(keyID) {
+			query = `
+				INSERT INTO vault.secrets (secret, name, description, nonce, key_id)
+				VALUES ($1, $2, $3, decode($4, 'hex'), $5)
+				RETURNING id
+			`
+			args = append(args, keyID.ValueString())
+		}
+
+		return tx.QueryRow(ctx, query, args...).Scan(&secretID)
+	})
+
+	return secretID, err
+}
+
+// validateCreate runs vault.create_secret for data in a transaction that is
+// rolled back, reporting whether creating the secret would succeed. On
+// success data is updated with the key_id the secret would be encrypted
+// with, while id and the timestamps stay null as nothing is stored.
+func (r *VaultSecretResource) validateCreate(ctx context.Context, data *VaultSecretModel, value, name, description string, diags *diag.Diagnostics) {
+	var keyID sql.NullString
+	err := r.providerData.withRollback(ctx, func(tx pgx.Tx) error {
+		var secretID sql.NullString
+		err := tx.QueryRow(ctx, "SELECT vault.create_secret($1, $2, $3, $4)", value, name, description, keyIDArgument(data.KeyID)).Scan(&secretID)
+		if err != nil {
+			return fmt.Errorf("calling vault.create_secret: %w", err)
+		}
+		if !secretID.Valid {
+			return fmt.Errorf("vault.create_secret returned no id; check triggers on vault.secrets and the connecting role's permissions")
+		}
+
+		return tx.QueryRow(ctx, "SELECT key_id FROM vault.secrets WHERE id = $1", secretID.String).Scan(&keyID)
+	})
+	if err != nil {
+		diags.AddError(
+			"Vault secret creation would fail",
+			withRemediation(fmt.Sprintf("validate_only is set, and creating secret %q failed: %s", data.Name.ValueString(), err), err),
+		)
+		return
+	}
+
+	data.ID = types.StringNull()
+	data.KeyID = types.StringNull()
+	if keyID.Valid {
+		data.KeyID = types.StringValue(keyID.String)
+	}
+	data.CreatedAt = types.StringNull()
+	data.UpdatedAt = types.StringNull()
+	data.CreatedBy = types.StringNull()
+	data.KeyStatus = types.StringNull()
+	data.ValueHash = data.valueHash(value)
+	data.DescriptionChecksum = descriptionChecksum(data.Description)
+	data.ManagedByVersion = types.StringNull()
+
+	diags.AddWarning(
+		"Vault secret creation validated",
+		fmt.Sprintf("validate_only is set, so secret %q was created in a transaction that was rolled back. Creating it would succeed, but no secret was stored.", data.Name.ValueString()),
+	)
+}
+
+// secretName returns the name to store for the planned secret, adding an
+// attribute error to diags if it can't be stored.
+func (r *VaultSecretResource) secretName(data VaultSecretModel, diags *diag.Diagnostics) string {
+	if err := checkAllowedName(data.Name.ValueString(), r.providerData.AllowedNamePatterns); err != nil {
+		diags.AddAttributeError(path.Root("name"), "Secret name not allowed", err.Error())
+		return ""
+	}
+
+	name, err := encodeSecretName(r.providerData.environmentName(data.Name.ValueString(), data.Environment), r.providerData.AllowInvalidUTF8Names)
+	if err != nil {
+		diags.AddAttributeError(path.Root("name"), "Invalid secret name", err.Error())
+	}
+
+	return name
+}
+
+// keyOnlyMigration reports whether the stored value is unchanged but has to be
+// re-encrypted under a different key.
+func keyOnlyMigration(plan, state VaultSecretModel) bool {
+	return !state.ValueHash.IsNull() &&
+		plan.ValueHash.Equal(state.ValueHash) &&
+		keyChanged(plan, state)
+}
+
+// keyChanged reports whether the secret is planned to move to a different key.
+func keyChanged(plan, state VaultSecretModel) bool {
+	return !plan.KeyID.IsNull() && !plan.KeyID.IsUnknown() &&
+		!plan.KeyID.Equal(state.KeyID)
+}
 
-	if description == "" {
-		return strings.TrimPrefix(footer, "\n\n")
+// keyIDArgument returns the key_id query argument, nil when not known.
+func keyIDArgument(keyID types.String) any {
+	if keyID.IsNull() || keyID.IsUnknown() {
+		return nil
 	}
 
-	return description + footer
+	return keyID.ValueString()
 }
 
 func (r *VaultSecretResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.providerData.logContext(ctx)
+
+	if !r.providerData.checkWritable(&resp.Diagnostics, "create a vault secret") {
+		return
+	}
+
 	var data VaultSecretModel
 
 	// Read Terraform plan data into the model
@@ -123,73 +841,244 @@ func (r *VaultSecretResource) Create(ctx context.Context, req resource.CreateReq
 		return
 	}
 
-	// Prepare description with footer
-	description := ""
-	if !data.Description.IsNull() {
-		description = data.Description.ValueString()
+	ctx, span := r.providerData.startSpan(ctx, "vault_secret.create", data.Name)
+	ctx = r.providerData.tagQueries(ctx, "supabase-vault_secret create")
+	defer span.end(&resp.Diagnostics)
+
+	release, ok := r.providerData.beginOperation(ctx, &resp.Diagnostics)
+	if !ok {
+		return
+	}
+	defer release()
+
+	if !r.useConnection(ctx, data.Connection, &resp.Diagnostics) {
+		return
+	}
+
+	ctx, tx, ok := r.providerData.beginOperationTx(ctx, &resp.Diagnostics)
+	if !ok {
+		return
+	}
+	defer tx.rollback(ctx)
+
+	var labels map[string]string
+	resp.Diagnostics.Append(data.Labels.ElementsAs(ctx, &labels, false)...)
+	labels = withLabel(labels, expiryLabel, data.ExpiresAt)
+	labels = withLabel(labels, environmentLabel, data.Environment)
+	data.IsExpired = expiryStatus(data.ExpiresAt, time.Now())
+
+	// Prepare description with labels and footer, falling back to the
+	// provider default
+	descriptionWithFooter := r.providerData.storedDescription(data.Description, labels)
+
+	// Write-only values are only available from the configuration
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("value_wo"), &data.ValueWO)...)
+
+	secretValue, diags := data.secretValue(ctx)
+	resp.Diagnostics.Append(diags...)
+	r.providerData.checkValueSize(data, secretValue, &resp.Diagnostics)
+	r.providerData.checkEmptyValue(data, secretValue, &resp.Diagnostics)
+	checkValuePattern(data, secretValue, &resp.Diagnostics)
+	data.ValueWO = types.StringNull()
+
+	secretName := r.secretName(data, &resp.Diagnostics)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.ContentAddressed.ValueBool() {
+		descriptionWithFooter = r.providerData.storedDescription(data.Description, withContentHash(labels, secretValue))
+	}
+
+	if data.ValidateOnly.ValueBool() {
+		r.validateCreate(ctx, &data, secretValue, secretName, descriptionWithFooter, &resp.Diagnostics)
+		if !resp.Diagnostics.HasError() {
+			resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		}
+		return
 	}
-	descriptionWithFooter := appendManagedByFooter(description, r.providerData.Version)
 
 	// Prepare the vault.create_secret() function call
-	// vault.create_secret(secret_value, name, description)
-	var secretID string
+	// vault.create_secret(secret_value, name, description, key_id)
+	// Scan into a nullable string: a failing trigger can make
+	// vault.create_secret return NULL instead of raising an error
+	var secretID sql.NullString
 	var err error
 
-	if !data.KeyID.IsNull() {
-		// If key_id is provided, we need to use a different approach
-		// Since vault.create_secret doesn't directly accept key_id, we'll need to handle this
-		// For now, create without key_id and note that key_id support may need custom SQL
-		tflog.Warn(ctx, "key_id parameter is not yet fully supported in create operation")
+	adopted := false
+	if data.ContentAddressed.ValueBool() {
+		existingID, existingName, found, err := r.lookupContentAddressedSecret(ctx, secretValue)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to look up content-addressed vault secret",
+				withRemediation(fmt.Sprintf("Error looking up a secret with the same value: %s", err), err),
+			)
+			return
+		}
+
+		if found && existingName != secretName {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("name"),
+				"Value already stored",
+				fmt.Sprintf("The value is already stored as secret %q (%s). With content_addressed it isn't stored twice; set name = %q to reuse that secret.",
+					decodeSecretName(existingName, r.providerData.AllowInvalidUTF8Names), existingID, decodeSecretName(existingName, r.providerData.AllowInvalidUTF8Names)),
+			)
+			return
+		}
+
+		if found {
+			// Bring the metadata in line with the configuration; the value is
+			// identical by construction
+			if err := r.providerData.secrets().updateDescription(ctx, existingID, descriptionWithFooter); err != nil {
+				resp.Diagnostics.AddError(
+					"Unable to reuse vault secret",
+					withRemediation(fmt.Sprintf("Error updating secret description: %s", err), err),
+				)
+				return
+			}
+
+			secretID = sql.NullString{String: existingID, Valid: true}
+			adopted = true
+
+			tflog.Debug(ctx, "reused a content-addressed vault secret", map[string]interface{}{
+				"id":   existingID,
+				"name": data.Name.ValueString(),
+			})
+		}
 	}
 
-	// Call vault.create_secret() using prepared statement
-	// vault.create_secret returns a UUID directly (not a record)
-	query := "SELECT vault.create_secret($1, $2, $3)"
-	err = r.providerData.Pool.QueryRow(ctx, query,
-		data.Value.ValueString(),
-		data.Name.ValueString(),
-		descriptionWithFooter,
-	).Scan(&secretID)
+	if data.AdoptExisting.ValueBool() {
+		// ModifyPlan already found the secret when the id is known; look it up
+		// again otherwise, as the name may only have become known at apply
+		existingID, found := data.ID.ValueString(), isKnown(data.ID)
+		if !found {
+			existingID, found, err = r.providerData.secrets().lookupSecretID(ctx, secretName)
+			if err != nil {
+				resp.Diagnostics.AddError(
+					"Unable to look up existing vault secret",
+					withRemediation(fmt.Sprintf("Error looking up secret %q: %s", data.Name.ValueString(), err), err),
+				)
+				return
+			}
+		}
 
-	if err != nil {
+		if found {
+			err = r.providerData.secrets().updateSecret(ctx,
+				existingID,
+				secretValue,
+				secretName,
+				descriptionWithFooter,
+				keyIDArgument(data.KeyID),
+			)
+
+			if err != nil {
+				resp.Diagnostics.AddError(
+					"Unable to adopt vault secret",
+					withRemediation(fmt.Sprintf("Error calling vault.update_secret: %s", err), err),
+				)
+				return
+			}
+
+			secretID = sql.NullString{String: existingID, Valid: true}
+			adopted = true
+
+			tflog.Debug(ctx, "adopted an existing vault secret", map[string]interface{}{
+				"id":   existingID,
+				"name": data.Name.ValueString(),
+			})
+		}
+	}
+
+	if !adopted && !data.Nonce.IsNull() {
+		secretID, err = r.createSecretWithNonce(ctx,
+			secretValue,
+			secretName,
+			descriptionWithFooter,
+			data.KeyID,
+			data.Nonce.ValueString(),
+		)
+
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("nonce"),
+				"Unable to create vault secret",
+				withRemediation(fmt.Sprintf("Error inserting secret with an explicit nonce: %s", err), err),
+			)
+			return
+		}
+	} else if !adopted {
+		// Call vault.create_secret(), which returns a UUID directly (not a
+		// record). A NULL key_id lets Vault use its default key
+		secretID, err = r.providerData.secrets().createSecret(ctx,
+			secretValue,
+			secretName,
+			descriptionWithFooter,
+			keyIDArgument(data.KeyID),
+		)
+
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to create vault secret",
+				withRemediation(fmt.Sprintf("Error calling vault.create_secret: %s", err), err),
+			)
+			return
+		}
+	}
+
+	if !secretID.Valid {
 		resp.Diagnostics.AddError(
 			"Unable to create vault secret",
-			fmt.Sprintf("Error calling vault.create_secret: %s", err),
+			"vault.create_secret returned no id; check triggers on vault.secrets and the connecting role's permissions.",
 		)
 		return
 	}
 
 	// Set the ID from the returned UUID
-	data.ID = types.StringValue(secretID)
+	data.ID = types.StringValue(secretID.String)
+	data.ValueHash = data.valueHash(secretValue)
+	data.DescriptionChecksum = descriptionChecksum(data.Description)
+	data.ManagedByVersion = r.providerData.managedByVersion(descriptionWithFooter)
 
-	// Read key_id from database to ensure it's a known value (computed attribute)
-	keyIDQuery := `SELECT key_id FROM vault.secrets WHERE id = $1`
-	var keyID sql.NullString
-	err = r.providerData.Pool.QueryRow(ctx, keyIDQuery, secretID).Scan(&keyID)
+	// Read key_id from database to ensure it's a known value (computed attribute),
+	// retrying briefly in case the new row hasn't reached a read replica yet
+	err = retryNoRows(ctx, keyIDReadAttempts, keyIDReadBackoff, func() error {
+		return r.readVaultAttributes(ctx, secretID.String, &data)
+	})
+	if err != nil && tx != nil {
+		resp.Diagnostics.AddError(
+			"Unable to read created vault secret",
+			withRemediation(fmt.Sprintf("Error reading key_id and timestamps of the new secret, so its creation was rolled back: %s", err), err),
+		)
+		return
+	}
 	if err != nil {
-		// If we can't read key_id, set it to null (better than unknown)
+		// If we can't read key_id, set it to null (better than unknown). A
+		// strict read fails instead, leaving the new secret tainted
 		data.KeyID = types.StringNull()
-		tflog.Warn(ctx, "Unable to read key_id after creation, setting to null", map[string]interface{}{
-			"error": err,
-		})
-	} else {
-		if keyID.Valid {
-			data.KeyID = types.StringValue(keyID.String)
-		} else {
-			data.KeyID = types.StringNull()
-		}
+		data.CreatedAt = types.StringNull()
+		data.UpdatedAt = types.StringNull()
+		data.CreatedBy = types.StringNull()
+		data.KeyStatus = types.StringNull()
+		r.providerData.keyIDReadFailed(ctx, "creation", err, &resp.Diagnostics)
 	}
 
 	tflog.Trace(ctx, "created a vault secret", map[string]interface{}{
-		"id":   secretID,
+		"id":   secretID.String,
 		"name": data.Name.ValueString(),
 	})
 
+	if !tx.commit(ctx, &resp.Diagnostics) {
+		return
+	}
+
 	// Save data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
 func (r *VaultSecretResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = r.providerData.logContext(ctx)
+
 	var data VaultSecretModel
 
 	// Read Terraform prior state data into the model
@@ -199,51 +1088,114 @@ func (r *VaultSecretResource) Read(ctx context.Context, req resource.ReadRequest
 		return
 	}
 
+	// Nothing was stored, so there is nothing to read
+	if data.ValidateOnly.ValueBool() {
+		return
+	}
+
+	ctx, span := r.providerData.startSpan(ctx, "vault_secret.read", data.Name)
+	ctx = r.providerData.tagQueries(ctx, "supabase-vault_secret read")
+	defer span.end(&resp.Diagnostics)
+
+	release, ok := r.providerData.beginOperation(ctx, &resp.Diagnostics)
+	if !ok {
+		return
+	}
+	defer release()
+
+	if !r.useConnection(ctx, data.Connection, &resp.Diagnostics) {
+		return
+	}
+
+	// Guard against corrupted state before querying by ID
+	if err := validateSecretID(data.ID.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("id"),
+			"Invalid vault secret ID in state",
+			fmt.Sprintf("The secret ID stored in state is malformed: %s. Remove the resource from state and re-import it.", err),
+		)
+		return
+	}
+
 	// Query metadata directly from vault.secrets table (no decryption needed)
 	// name, description, and key_id are stored as plaintext in vault.secrets
 	// This is much more efficient than using vault.decrypted_secrets view
-	query := `
-		SELECT id, name, description, key_id 
-		FROM vault.secrets 
-		WHERE id = $1
-	`
-
-	var id, name, description string
-	var keyID sql.NullString
-	err := r.providerData.Pool.QueryRow(ctx, query, data.ID.ValueString()).Scan(
-		&id, &name, &description, &keyID,
-	)
+	secret, err := r.providerData.secrets().readSecret(ctx, data.ID.ValueString())
 
 	if err == pgx.ErrNoRows {
 		// Secret not found, mark as removed
-		resp.State.RemoveResource(ctx)
+		if r.providerData.allowMissing(&resp.Diagnostics, fmt.Sprintf("secret %q (%s)", data.Name.ValueString(), data.ID.ValueString())) {
+			resp.State.RemoveResource(ctx)
+		}
 		return
 	}
 
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to read vault secret metadata",
-			fmt.Sprintf("Error reading secret metadata: %s", err),
+			withRemediation(fmt.Sprintf("Error reading secret metadata: %s", err), err),
 		)
 		return
 	}
 
 	// Update state with metadata (but not the secret value - it stays in state)
-	data.Name = types.StringValue(name)
-	if keyID.Valid {
-		data.KeyID = types.StringValue(keyID.String)
+	if secret.KeyID.Valid {
+		data.KeyID = types.StringValue(secret.KeyID.String)
 	} else {
 		data.KeyID = types.StringNull()
 	}
 
 	// Remove the managed-by footer from description if present.
 	// This allows users to see their original description.
-	if description != "" {
-		footer := fmt.Sprintf("\n\n---\nManaged by terraform-provider-supabase-vault v%s", r.providerData.Version)
-		description = strings.TrimSuffix(description, footer)
-		data.Description = types.StringValue(description)
-	} else {
-		data.Description = types.StringNull()
+	// A footer left by any provider version is removed, and the provider's
+	// default description reads back as no description.
+	var labels map[string]string
+	data.Description, labels = r.providerData.configuredDescription(secret.Description, data.Description)
+	if data.ContentAddressed.ValueBool() {
+		delete(labels, contentHashLabel)
+	}
+
+	// Like content_addressed, the label is only taken over once expires_at
+	// is set, so secrets labelled by hand keep their labels
+	if !data.ExpiresAt.IsNull() {
+		data.ExpiresAt = types.StringNull()
+		if expiresAt, ok := labels[expiryLabel]; ok {
+			data.ExpiresAt = types.StringValue(expiresAt)
+			delete(labels, expiryLabel)
+		}
+	}
+	data.IsExpired = expiryStatus(data.ExpiresAt, time.Now())
+
+	// The environment is taken from the label once it is set, which import
+	// does for labelled secrets. Without the label the prefix still marks
+	// the environment, so the one in state is kept.
+	if !data.Environment.IsNull() {
+		if environment, ok := labels[environmentLabel]; ok {
+			data.Environment = types.StringValue(environment)
+			delete(labels, environmentLabel)
+		}
+	}
+	data.Name = types.StringValue(r.providerData.configuredName(decodeSecretName(secret.Name, r.providerData.AllowInvalidUTF8Names), data.Environment))
+
+	// An empty map in the configuration stores no block, so keep it as is
+	if len(labels) > 0 || len(data.Labels.Elements()) > 0 {
+		labelsValue, diags := types.MapValueFrom(ctx, types.StringType, labels)
+		resp.Diagnostics.Append(diags...)
+		data.Labels = labelsValue
+	}
+	data.DescriptionChecksum = descriptionChecksum(data.Description)
+	data.ManagedByVersion = r.providerData.managedByVersion(secret.Description)
+	data.CreatedAt = r.providerData.timestampValue(secret.CreatedAt)
+	data.UpdatedAt = r.providerData.timestampValue(secret.UpdatedAt)
+	data.CreatedBy = types.StringPointerValue(nullStringPointer(secret.CreatedBy))
+	data.KeyStatus = r.readKeyStatus(ctx, secret.KeyID)
+
+	if keyStatusInvalid(data.KeyStatus) {
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("key_status"),
+			"Secret encrypted with an unusable key",
+			fmt.Sprintf("Secret %q is encrypted with pgsodium key %s, whose status is %q. Set key_id to a valid key to re-encrypt it.", data.Name.ValueString(), secret.KeyID.String, data.KeyStatus.ValueString()),
+		)
 	}
 
 	// Note: We do NOT read the secret value for security reasons
@@ -254,6 +1206,12 @@ func (r *VaultSecretResource) Read(ctx context.Context, req resource.ReadRequest
 }
 
 func (r *VaultSecretResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = r.providerData.logContext(ctx)
+
+	if !r.providerData.checkWritable(&resp.Diagnostics, "update a vault secret") {
+		return
+	}
+
 	var data VaultSecretModel
 	var state VaultSecretModel
 
@@ -265,41 +1223,203 @@ func (r *VaultSecretResource) Update(ctx context.Context, req resource.UpdateReq
 		return
 	}
 
-	// Prepare description with footer
-	description := ""
-	if !data.Description.IsNull() {
-		description = data.Description.ValueString()
+	ctx, span := r.providerData.startSpan(ctx, "vault_secret.update", data.Name)
+	ctx = r.providerData.tagQueries(ctx, "supabase-vault_secret update")
+	defer span.end(&resp.Diagnostics)
+
+	release, ok := r.providerData.beginOperation(ctx, &resp.Diagnostics)
+	if !ok {
+		return
 	}
-	descriptionWithFooter := appendManagedByFooter(description, r.providerData.Version)
+	defer release()
 
-	// Call vault.update_secret() using prepared statement
-	// vault.update_secret(id, secret_value, name, description)
-	query := "SELECT vault.update_secret($1, $2, $3, $4)"
-	_, err := r.providerData.Pool.Exec(ctx, query,
-		state.ID.ValueString(), // Use ID from state
-		data.Value.ValueString(),
-		data.Name.ValueString(),
-		descriptionWithFooter,
-	)
+	if !r.useConnection(ctx, data.Connection, &resp.Diagnostics) {
+		return
+	}
 
-	if err != nil {
+	ctx, tx, ok := r.providerData.beginOperationTx(ctx, &resp.Diagnostics)
+	if !ok {
+		return
+	}
+	defer tx.rollback(ctx)
+
+	var labels map[string]string
+	resp.Diagnostics.Append(data.Labels.ElementsAs(ctx, &labels, false)...)
+	labels = withLabel(labels, expiryLabel, data.ExpiresAt)
+	labels = withLabel(labels, environmentLabel, data.Environment)
+	data.IsExpired = expiryStatus(data.ExpiresAt, time.Now())
+
+	// Prepare description with labels and footer, falling back to the
+	// provider default
+	descriptionWithFooter := r.providerData.storedDescription(data.Description, labels)
+
+	// Write-only values are only available from the configuration
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("value_wo"), &data.ValueWO)...)
+
+	// Without a value only the metadata is Terraform's to change
+	valueOmitted := data.Value.IsNull() && data.ValueWO.IsNull()
+
+	secretValue, diags := data.secretValue(ctx)
+	resp.Diagnostics.Append(diags...)
+	r.providerData.checkValueSize(data, secretValue, &resp.Diagnostics)
+	if !valueOmitted {
+		r.providerData.checkEmptyValue(data, secretValue, &resp.Diagnostics)
+		checkValuePattern(data, secretValue, &resp.Diagnostics)
+	}
+	data.ValueWO = types.StringNull()
+
+	secretName := r.secretName(data, &resp.Diagnostics)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.ContentAddressed.ValueBool() {
+		descriptionWithFooter = r.providerData.storedDescription(data.Description, withContentHash(labels, secretValue))
+	}
+
+	if data.ValidateOnly.ValueBool() {
+		r.validateCreate(ctx, &data, secretValue, secretName, descriptionWithFooter, &resp.Diagnostics)
+		if !resp.Diagnostics.HasError() {
+			resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		}
+		return
+	}
+
+	data.ValueHash = data.valueHash(secretValue)
+	data.DescriptionChecksum = descriptionChecksum(data.Description)
+	data.ManagedByVersion = r.providerData.managedByVersion(descriptionWithFooter)
+
+	if data.Immutable.ValueBool() && !valueOmitted && valueChanged(data, state) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("value"),
+			"Immutable vault secret",
+			fmt.Sprintf("Secret %q is immutable, so its value can't be updated in place. Replace the resource to store a new value, e.g. with terraform apply -replace or terraform taint, or set immutable = false.", data.Name.ValueString()),
+		)
+		return
+	}
+
+	if descriptionOnlyChange(data, state) {
+		// Only the description changed, so update the metadata column directly.
+		// This avoids re-encrypting a value that hasn't changed.
+		err := r.providerData.secrets().updateDescription(ctx,
+			state.ID.ValueString(),
+			descriptionWithFooter,
+		)
+
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to update vault secret",
+				withRemediation(fmt.Sprintf("Error updating secret description: %s", err), err),
+			)
+			return
+		}
+	} else if keyOnlyMigration(data, state) || (valueOmitted && keyChanged(data, state)) {
+		// The value is unchanged but the key isn't, so re-encrypt the stored
+		// value under the new key. The decrypted value never leaves the
+		// database.
+		err := r.providerData.secrets().reencryptSecret(ctx,
+			state.ID.ValueString(),
+			secretName,
+			descriptionWithFooter,
+			keyIDArgument(data.KeyID),
+		)
+
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to re-encrypt vault secret",
+				withRemediation(fmt.Sprintf("Error re-encrypting secret under key %s: %s", data.KeyID.ValueString(), err), err),
+			)
+			return
+		}
+
+		tflog.Debug(ctx, "re-encrypted a vault secret under a new key", map[string]interface{}{
+			"id":         state.ID.ValueString(),
+			"old_key_id": state.KeyID.ValueString(),
+			"new_key_id": data.KeyID.ValueString(),
+		})
+	} else if valueOmitted {
+		// The value is managed elsewhere, so update the metadata columns and
+		// leave the encrypted payload untouched
+		err := r.providerData.secrets().updateMetadata(ctx,
+			state.ID.ValueString(),
+			secretName,
+			descriptionWithFooter,
+		)
+
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to update vault secret",
+				withRemediation(fmt.Sprintf("Error updating secret metadata: %s", err), err),
+			)
+			return
+		}
+	} else {
+		// vault.update_secret(id, secret_value, name, description, key_id)
+		// A NULL key_id keeps the secret's current key
+		err := r.providerData.secrets().updateSecret(ctx,
+			state.ID.ValueString(), // Use ID from state
+			secretValue,
+			secretName,
+			descriptionWithFooter,
+			keyIDArgument(data.KeyID),
+		)
+
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to update vault secret",
+				withRemediation(fmt.Sprintf("Error calling vault.update_secret: %s", err), err),
+			)
+			return
+		}
+	}
+
+	// Re-read key_id so state reflects the key Vault actually used, e.g. when
+	// the update left the key to Vault's default
+	err := r.readVaultAttributes(ctx, state.ID.ValueString(), &data)
+	if err != nil && tx != nil {
 		resp.Diagnostics.AddError(
-			"Unable to update vault secret",
-			fmt.Sprintf("Error calling vault.update_secret: %s", err),
+			"Unable to read updated vault secret",
+			withRemediation(fmt.Sprintf("Error reading key_id and timestamps of the secret, so the update was rolled back: %s", err), err),
 		)
 		return
 	}
+	if err != nil {
+		// Keep the planned values if known, otherwise fall back to null
+		if data.KeyID.IsUnknown() {
+			data.KeyID = types.StringNull()
+		}
+		if data.CreatedAt.IsUnknown() {
+			data.CreatedAt = types.StringNull()
+		}
+		if data.UpdatedAt.IsUnknown() {
+			data.UpdatedAt = types.StringNull()
+		}
+		if data.CreatedBy.IsUnknown() {
+			data.CreatedBy = types.StringNull()
+		}
+		if data.KeyStatus.IsUnknown() {
+			data.KeyStatus = types.StringNull()
+		}
+		r.providerData.keyIDReadFailed(ctx, "update", err, &resp.Diagnostics)
+	}
 
 	tflog.Trace(ctx, "updated a vault secret", map[string]interface{}{
 		"id":   state.ID.ValueString(),
 		"name": data.Name.ValueString(),
 	})
 
+	if !tx.commit(ctx, &resp.Diagnostics) {
+		return
+	}
+
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
 func (r *VaultSecretResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = r.providerData.logContext(ctx)
+
 	var data VaultSecretModel
 
 	// Read Terraform prior state data into the model
@@ -309,40 +1429,113 @@ func (r *VaultSecretResource) Delete(ctx context.Context, req resource.DeleteReq
 		return
 	}
 
+	// Nothing was stored, so there is nothing to delete, even for a
+	// read-only provider
+	if data.ValidateOnly.ValueBool() {
+		return
+	}
+
+	if !r.providerData.checkWritable(&resp.Diagnostics, "delete a vault secret") {
+		return
+	}
+
+	ctx, span := r.providerData.startSpan(ctx, "vault_secret.delete", data.Name)
+	ctx = r.providerData.tagQueries(ctx, "supabase-vault_secret delete")
+	defer span.end(&resp.Diagnostics)
+
+	release, ok := r.providerData.beginOperation(ctx, &resp.Diagnostics)
+	if !ok {
+		return
+	}
+	defer release()
+
+	if !r.useConnection(ctx, data.Connection, &resp.Diagnostics) {
+		return
+	}
+
+	ctx, tx, ok := r.providerData.beginOperationTx(ctx, &resp.Diagnostics)
+	if !ok {
+		return
+	}
+	defer tx.rollback(ctx)
+
 	// Delete the secret using direct SQL (no helper function available)
-	query := "DELETE FROM vault.secrets WHERE id = $1"
-	_, err := r.providerData.Pool.Exec(ctx, query, data.ID.ValueString())
+	err := r.providerData.secrets().deleteSecret(ctx, data.ID.ValueString())
 
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to delete vault secret",
-			fmt.Sprintf("Error deleting secret: %s", err),
+			withRemediation(fmt.Sprintf("Error deleting secret: %s", err), err),
 		)
 		return
 	}
 
+	if !tx.commit(ctx, &resp.Diagnostics) {
+		return
+	}
+
 	tflog.Trace(ctx, "deleted a vault secret", map[string]interface{}{
 		"id": data.ID.ValueString(),
 	})
 }
 
+// importWithValueSuffix ends an import ID that also reads the secret's
+// decrypted value into state.
+const importWithValueSuffix = "!withvalue"
+
+// importedValue returns the decrypted value read for an import with
+// importWithValueSuffix. Vault reads a NULL decrypted_secret when the secret
+// can't be decrypted, e.g. because its key is missing or invalid.
+func importedValue(secretName string, decrypted sql.NullString) (string, error) {
+	if !decrypted.Valid {
+		return "", fmt.Errorf("secret %q could not be decrypted: vault.decrypted_secrets returned no value, which happens when its key is missing or invalid. "+
+			"Check the secret's key_id, or import it without %s", secretName, importWithValueSuffix)
+	}
+
+	return decrypted.String, nil
+}
+
 func (r *VaultSecretResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	// Import by secret name - we'll need to look up the ID
-	secretName := req.ID
+	// Import ID formats: <name>, <uuid>, or either followed by |<key_id> to
+	// assert the key the secret is expected to be encrypted with. A trailing
+	// !withvalue also reads the decrypted value into state.
+	importID, withValue := strings.CutSuffix(req.ID, importWithValueSuffix)
+	secretRef, expectedKeyID, assertKeyID := strings.Cut(importID, "|")
 
+	// Look up the secret by UUID when the reference looks like one, otherwise by name.
+	// Like Read, this queries vault.secrets, where name and key_id are plaintext,
+	// so importing needs no decryption privileges.
 	query := `
-		SELECT id 
-		FROM vault.decrypted_secrets 
+		SELECT id, name, key_id, description
+		FROM vault.secrets
 		WHERE name = $1
 	`
+	if validateSecretID(secretRef) == nil {
+		query = `
+			SELECT id, name, key_id, description
+			FROM vault.secrets
+			WHERE id = $1
+		`
+	}
+
+	if assertKeyID {
+		if err := validateSecretID(expectedKeyID); err != nil {
+			resp.Diagnostics.AddError(
+				"Invalid import ID",
+				fmt.Sprintf("The key_id in the import ID is invalid: %s. Expected <name>|<key_id> or <uuid>|<key_id>.", err),
+			)
+			return
+		}
+	}
 
-	var secretID string
-	err := r.providerData.Pool.QueryRow(ctx, query, secretName).Scan(&secretID)
+	var secretID, secretName, description string
+	var keyID sql.NullString
+	err := r.providerData.queryRow(ctx, query, secretRef).Scan(&secretID, &secretName, &keyID, &description)
 
 	if err == pgx.ErrNoRows {
 		resp.Diagnostics.AddError(
 			"Secret not found",
-			fmt.Sprintf("No secret found with name: %s", secretName),
+			fmt.Sprintf("No secret found with name or id: %s", secretRef),
 		)
 		return
 	}
@@ -350,12 +1543,71 @@ func (r *VaultSecretResource) ImportState(ctx context.Context, req resource.Impo
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to import vault secret",
-			fmt.Sprintf("Error looking up secret by name: %s", err),
+			withRemediation(fmt.Sprintf("Error looking up secret: %s", err), err),
 		)
 		return
 	}
 
+	// Refuse the import if the secret isn't encrypted with the expected key
+	if assertKeyID {
+		if err := checkSecretKeyID(keyID, expectedKeyID); err != nil {
+			resp.Diagnostics.AddError(
+				"Secret key mismatch",
+				fmt.Sprintf("Secret %q can't be imported: %s.", secretName, err),
+			)
+			return
+		}
+	}
+
+	// A labelled environment is set so Read strips its prefix from the name.
+	// With prefixed names the name has to carry it too, so secrets merely
+	// labelled by hand import as before.
+	environment := types.StringNull()
+	_, labels := splitLabels(stripManagedByFooter(description, r.providerData.footerSeparator()))
+	if label := labels[environmentLabel]; validateEnvironment(label) == nil {
+		if !r.providerData.prefixesEnvironment() || strings.HasPrefix(secretName, label+"_") {
+			environment = types.StringValue(label)
+		}
+	}
+
 	// Set the ID so Terraform can read the resource
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), secretID)...)
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), secretName)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), r.providerData.configuredName(decodeSecretName(secretName, r.providerData.AllowInvalidUTF8Names), environment))...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("environment"), environment)...)
+
+	if !withValue {
+		return
+	}
+
+	// Opted into explicitly: this writes the plaintext value to state, which
+	// Read never does
+	var decrypted sql.NullString
+	err = r.providerData.queryRow(ctx, `
+		SELECT decrypted_secret
+		FROM vault.decrypted_secrets
+		WHERE id = $1
+	`, secretID).Scan(&decrypted)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to import vault secret value",
+			withRemediation(fmt.Sprintf("Error reading decrypted secret %q for %s: %s", secretName, importWithValueSuffix, err), err),
+		)
+		return
+	}
+
+	value, err := importedValue(secretName, decrypted)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Secret could not be decrypted",
+			err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("value"), value)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("value_hash"), hashSecretValue(value))...)
+	resp.Diagnostics.AddWarning(
+		"Secret value written to state",
+		fmt.Sprintf("Secret %q was imported with %s, so its decrypted value is now stored in plaintext in the Terraform state. Only use this where the value isn't sensitive.", secretName, importWithValueSuffix),
+	)
 }
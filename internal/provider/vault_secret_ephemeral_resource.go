@@ -0,0 +1,167 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/jackc/pgx/v5"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ ephemeral.EphemeralResource = &VaultSecretEphemeralResource{}
+var _ ephemeral.EphemeralResourceWithConfigure = &VaultSecretEphemeralResource{}
+
+func NewVaultSecretEphemeralResource() ephemeral.EphemeralResource {
+	return &VaultSecretEphemeralResource{}
+}
+
+// VaultSecretEphemeralResource defines the ephemeral resource implementation.
+type VaultSecretEphemeralResource struct {
+	providerData *ProviderData
+}
+
+// VaultSecretEphemeralResourceModel describes the ephemeral resource data model.
+type VaultSecretEphemeralResourceModel struct {
+	Name  types.String `tfsdk:"name"`
+	KeyID types.String `tfsdk:"key_id"`
+	ID    types.String `tfsdk:"id"`
+	Value types.String `tfsdk:"value"`
+}
+
+func (r *VaultSecretEphemeralResource) Metadata(ctx context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_secret"
+}
+
+func (r *VaultSecretEphemeralResource) Schema(ctx context.Context, req ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Reads the decrypted value of a secret by name without storing it in the plan or state. " +
+			"Pass `value` to write-only attributes such as `supabase-vault_secret`'s `value_wo`. The connecting role must be able to read `vault.decrypted_secrets`.",
+
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Name of the secret to read",
+				Required:            true,
+			},
+			"key_id": schema.StringAttribute{
+				MarkdownDescription: "UUID of the key the secret is expected to be encrypted with. When multiple keys are in use, opening fails unless the secret's `key_id` matches, rather than returning a value decrypted with another key.",
+				Optional:            true,
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Secret UUID",
+				Computed:            true,
+			},
+			"value": schema.StringAttribute{
+				MarkdownDescription: "Decrypted secret value",
+				Computed:            true,
+				Sensitive:           true,
+			},
+		},
+	}
+}
+
+func (r *VaultSecretEphemeralResource) Configure(ctx context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Ephemeral Resource Configure Type",
+			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.providerData = providerData
+}
+
+func (r *VaultSecretEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data VaultSecretEphemeralResourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.KeyID.IsNull() {
+		if err := validateSecretID(data.KeyID.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("key_id"),
+				"Invalid key_id",
+				err.Error(),
+			)
+			return
+		}
+	}
+
+	query := `
+		SELECT id, decrypted_secret, key_id
+		FROM vault.decrypted_secrets
+		WHERE name = $1
+	`
+
+	var id string
+	var decrypted, keyID sql.NullString
+	err := r.providerData.queryRow(ctx, query, data.Name.ValueString()).Scan(&id, &decrypted, &keyID)
+
+	if err == pgx.ErrNoRows {
+		resp.Diagnostics.AddError(
+			"Secret not found",
+			fmt.Sprintf("No secret found with name: %s", data.Name.ValueString()),
+		)
+		return
+	}
+
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to read vault secret value",
+			withRemediation(fmt.Sprintf("Error reading decrypted secret: %s", err), err),
+		)
+		return
+	}
+
+	if !data.KeyID.IsNull() {
+		if err := checkSecretKeyID(keyID, data.KeyID.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("key_id"),
+				"Secret key mismatch",
+				fmt.Sprintf("Secret %q can't be read with the expected key: %s.", data.Name.ValueString(), err),
+			)
+			return
+		}
+	}
+
+	value, err := decryptedValue(data.Name.ValueString(), decrypted)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Secret could not be decrypted",
+			err.Error(),
+		)
+		return
+	}
+
+	data.ID = types.StringValue(id)
+	data.Value = types.StringValue(value)
+
+	tflog.Trace(ctx, "opened an ephemeral vault secret", map[string]interface{}{
+		"name": data.Name.ValueString(),
+	})
+
+	// Save data into the ephemeral result
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
@@ -0,0 +1,153 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+
+	fwephemeral "github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+	"github.com/jackc/pgx/v5"
+)
+
+func TestAccVaultSecretEphemeralResource(t *testing.T) {
+	// Skip if TF_ACC is not set
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests skipped unless env 'TF_ACC' set")
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactoriesWithEcho,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			// Write-only attributes need Terraform 1.11
+			tfversion.SkipBelow(tfversion.Version1_11_0),
+		},
+		Steps: []resource.TestStep{
+			// The source secret has to exist before the ephemeral resource opens
+			{
+				Config: testAccVaultSecretEphemeralResourceSourceConfig("test-secret-ephemeral", "ephemeral-value"),
+			},
+			{
+				Config: testAccVaultSecretEphemeralResourceSourceConfig("test-secret-ephemeral", "ephemeral-value") + `
+ephemeral "supabase-vault_secret" "source" {
+  name = supabase-vault_secret.source.name
+}
+
+provider "echo" {
+  data = ephemeral.supabase-vault_secret.source.value
+}
+
+resource "echo" "source" {}
+
+resource "supabase-vault_secret" "copy" {
+  name             = "test-secret-ephemeral-copy"
+  value_wo         = ephemeral.supabase-vault_secret.source.value
+  value_wo_version = 1
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"echo.source",
+						tfjsonpath.New("data"),
+						knownvalue.StringExact("ephemeral-value"),
+					),
+					// Nothing derived from the write-only value reaches the state
+					statecheck.ExpectKnownValue(
+						"supabase-vault_secret.copy",
+						tfjsonpath.New("value_wo"),
+						knownvalue.Null(),
+					),
+					statecheck.ExpectKnownValue(
+						"supabase-vault_secret.copy",
+						tfjsonpath.New("value_hash"),
+						knownvalue.Null(),
+					),
+				},
+			},
+		},
+	})
+}
+
+func testAccVaultSecretEphemeralResourceSourceConfig(name, value string) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "supabase-vault_secret" "source" {
+  name  = %q
+  value = %q
+}
+`, name, value)
+}
+
+// rowTx is an operation transaction whose every query returns values.
+type rowTx struct {
+	pgx.Tx
+	values []any
+}
+
+func (tx rowTx) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return valuesRow(tx.values)
+}
+
+// valuesRow scans its values into the destinations in order.
+type valuesRow []any
+
+func (r valuesRow) Scan(dest ...any) error {
+	for i, value := range r {
+		reflect.ValueOf(dest[i]).Elem().Set(reflect.ValueOf(value))
+	}
+	return nil
+}
+
+func TestVaultSecretEphemeralResourceOpenUndecryptable(t *testing.T) {
+	testCases := map[string]struct {
+		decrypted sql.NullString
+		expectErr bool
+	}{
+		"decrypted": {decrypted: sql.NullString{String: "hunter2", Valid: true}},
+		// vault.decrypted_secrets returns NULL when the key is missing or invalid
+		"undecryptable": {expectErr: true},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			tx := rowTx{values: []any{"4c1f2a3b-5d6e-4f70-8a9b-0c1d2e3f4a5b", testCase.decrypted, sql.NullString{}}}
+			ctx := context.WithValue(context.Background(), operationTxKey{}, pgx.Tx(tx))
+			r := &VaultSecretEphemeralResource{providerData: &ProviderData{}}
+
+			schemaResp := &fwephemeral.SchemaResponse{}
+			r.Schema(ctx, fwephemeral.SchemaRequest{}, schemaResp)
+			schema := schemaResp.Schema
+
+			config := testConfigValue(t, schema.Type(), map[string]tftypes.Value{
+				"name": tftypes.NewValue(tftypes.String, "api_key"),
+			})
+			resp := &fwephemeral.OpenResponse{
+				Result: tfsdk.EphemeralResultData{Schema: schema, Raw: tftypes.NewValue(schema.Type().TerraformType(ctx), nil)},
+			}
+			r.Open(ctx, fwephemeral.OpenRequest{Config: tfsdk.Config{Schema: schema, Raw: config}}, resp)
+
+			if !testCase.expectErr {
+				if resp.Diagnostics.HasError() {
+					t.Fatalf("unexpected error: %v", resp.Diagnostics)
+				}
+				return
+			}
+
+			if !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != "Secret could not be decrypted" {
+				t.Fatalf("expected a decryption error, got: %v", resp.Diagnostics)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &VaultSecretExistsDataSource{}
+
+func NewVaultSecretExistsDataSource() datasource.DataSource {
+	return &VaultSecretExistsDataSource{}
+}
+
+// VaultSecretExistsDataSource defines the data source implementation.
+type VaultSecretExistsDataSource struct {
+	providerData *ProviderData
+}
+
+// VaultSecretExistsDataSourceModel describes the data source data model.
+type VaultSecretExistsDataSourceModel struct {
+	Name   types.String `tfsdk:"name"`
+	Exists types.Bool   `tfsdk:"exists"`
+}
+
+func (d *VaultSecretExistsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_secret_exists"
+}
+
+func (d *VaultSecretExistsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Reports whether a secret with the given name exists, e.g. to create a secret only when it is absent with " +
+			"`count = data.supabase-vault_secret_exists.api_key.exists ? 0 : 1`. Only `vault.secrets` is queried, so no decryption privileges are needed.",
+
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Name of the secret to look for",
+				Required:            true,
+			},
+			"exists": schema.BoolAttribute{
+				MarkdownDescription: "Whether a secret with this name exists",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *VaultSecretExistsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.providerData = providerData
+}
+
+func (d *VaultSecretExistsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data VaultSecretExistsDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Look the name up the way the secret resource stores it
+	name, err := encodeSecretName(data.Name.ValueString(), d.providerData.AllowInvalidUTF8Names)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("name"), "Invalid secret name", err.Error())
+		return
+	}
+
+	query := `SELECT EXISTS(SELECT 1 FROM vault.secrets WHERE name = $1)`
+
+	var exists bool
+	if err := d.providerData.queryRow(ctx, query, name).Scan(&exists); err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to look up vault secret",
+			withRemediation(fmt.Sprintf("Error checking for secret %q: %s", data.Name.ValueString(), err), err),
+		)
+		return
+	}
+
+	data.Exists = types.BoolValue(exists)
+
+	tflog.Trace(ctx, "checked whether a vault secret exists", map[string]interface{}{
+		"name":   data.Name.ValueString(),
+		"exists": exists,
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
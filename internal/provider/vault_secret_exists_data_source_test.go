@@ -0,0 +1,61 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestAccVaultSecretExistsDataSource(t *testing.T) {
+	// Skip if TF_ACC is not set
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests skipped unless env 'TF_ACC' set")
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccVaultSecretExistsDataSourceConfig("test-secret-exists"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.supabase-vault_secret_exists.existing",
+						tfjsonpath.New("exists"),
+						knownvalue.Bool(true),
+					),
+					statecheck.ExpectKnownValue(
+						"data.supabase-vault_secret_exists.missing",
+						tfjsonpath.New("exists"),
+						knownvalue.Bool(false),
+					),
+				},
+			},
+		},
+	})
+}
+
+func testAccVaultSecretExistsDataSourceConfig(name string) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "supabase-vault_secret" "test" {
+  name  = %[1]q
+  value = "the-value"
+}
+
+data "supabase-vault_secret_exists" "existing" {
+  name = supabase-vault_secret.test.name
+}
+
+data "supabase-vault_secret_exists" "missing" {
+  name = "%[1]s-does-not-exist"
+}
+`, name)
+}
@@ -0,0 +1,308 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/jackc/pgx/v5"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &VaultSecretExportResource{}
+
+func NewVaultSecretExportResource() resource.Resource {
+	return &VaultSecretExportResource{}
+}
+
+// VaultSecretExportResource defines the resource implementation.
+type VaultSecretExportResource struct {
+	providerData *ProviderData
+}
+
+// VaultSecretExportModel describes the resource data model.
+type VaultSecretExportModel struct {
+	Path          types.String `tfsdk:"path"`
+	ContentSHA256 types.String `tfsdk:"content_sha256"`
+}
+
+func (r *VaultSecretExportResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_secret_export"
+}
+
+func (r *VaultSecretExportResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Writes the metadata (`id`, `name`, `description`, `labels` and `key_id`) of every secret managed by this provider to a local JSON file, e.g. to keep a committed manifest of what exists. " +
+			"Secret values are never read or written. The file is rewritten on apply whenever it no longer matches the vault, including after it was edited or deleted locally, and removed when the resource is destroyed.",
+
+		Attributes: map[string]schema.Attribute{
+			"path": schema.StringAttribute{
+				MarkdownDescription: "Path of the JSON file to write, e.g. `\"${path.module}/secrets.manifest.json\"`. Missing parent directories are created.",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"content_sha256": schema.StringAttribute{
+				MarkdownDescription: "SHA-256 hex digest of the written file",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (r *VaultSecretExportResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.providerData = providerData
+}
+
+// managedSecretMetadata reads the metadata of every secret whose description
+// carries the managed-by footer, ordered by name.
+func (r *VaultSecretExportResource) managedSecretMetadata(ctx context.Context) ([]secretMetadataRow, error) {
+	// Metadata is stored in plaintext in vault.secrets, so no decryption is needed
+	query := `
+		SELECT id, name, description, key_id
+		FROM vault.secrets
+		WHERE strpos(description, $1) > 0
+		ORDER BY name, id
+	`
+
+	rows, err := collectRows(ctx, r.providerData, pgx.RowToStructByNameLax[secretMetadataRow], query, managedByFooterMarker)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range rows {
+		if rows[i].Name != nil {
+			name := decodeSecretName(*rows[i].Name, r.providerData.AllowInvalidUTF8Names)
+			rows[i].Name = &name
+		}
+	}
+
+	return rows, nil
+}
+
+// renderSecretExport renders the export file for rows, with descriptions as
+// shown in Terraform, footers delimited by separator stripped and labels
+// parsed out of them. Rows are written in the order given, so the same
+// metadata always renders the same content.
+func renderSecretExport(rows []secretMetadataRow, separator string) ([]byte, error) {
+	exported := make([]secretMetadataRow, len(rows))
+	for i, row := range rows {
+		row.Description, row.Labels = splitLabels(stripManagedByFooter(row.Description, separator))
+		if row.Labels == nil {
+			row.Labels = map[string]string{}
+		}
+		exported[i] = row
+	}
+
+	content, err := json.MarshalIndent(exported, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(content, '\n'), nil
+}
+
+// write renders the export and writes it to the configured path.
+func (r *VaultSecretExportResource) write(ctx context.Context, data *VaultSecretExportModel) error {
+	rows, err := r.managedSecretMetadata(ctx)
+	if err != nil {
+		return fmt.Errorf("reading secret metadata: %w", err)
+	}
+
+	content, err := renderSecretExport(rows, r.providerData.footerSeparator())
+	if err != nil {
+		return fmt.Errorf("encoding secret metadata as JSON: %w", err)
+	}
+
+	exportPath := data.Path.ValueString()
+	if err := os.MkdirAll(filepath.Dir(exportPath), 0o755); err != nil {
+		return fmt.Errorf("creating directory for %s: %w", exportPath, err)
+	}
+	if err := os.WriteFile(exportPath, content, 0o644); err != nil {
+		return fmt.Errorf("writing %s: %w", exportPath, err)
+	}
+
+	data.ContentSHA256 = types.StringValue(hashSecretValue(string(content)))
+
+	tflog.Trace(ctx, "exported vault secret metadata", map[string]interface{}{
+		"path":  exportPath,
+		"count": len(rows),
+	})
+
+	return nil
+}
+
+func (r *VaultSecretExportResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.providerData.logContext(ctx)
+
+	release, ok := r.providerData.beginOperation(ctx, &resp.Diagnostics)
+	if !ok {
+		return
+	}
+	defer release()
+
+	var data VaultSecretExportModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.write(ctx, &data); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("path"),
+			"Unable to export vault secret metadata",
+			withRemediation(err.Error(), err),
+		)
+		return
+	}
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *VaultSecretExportResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = r.providerData.logContext(ctx)
+
+	release, ok := r.providerData.beginOperation(ctx, &resp.Diagnostics)
+	if !ok {
+		return
+	}
+	defer release()
+
+	var data VaultSecretExportModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	rows, err := r.managedSecretMetadata(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to read vault secret metadata",
+			withRemediation(fmt.Sprintf("Error reading secret metadata: %s", err), err),
+		)
+		return
+	}
+
+	expected, err := renderSecretExport(rows, r.providerData.footerSeparator())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to encode vault secret metadata",
+			fmt.Sprintf("Error encoding secret metadata as JSON: %s", err),
+		)
+		return
+	}
+
+	content, err := os.ReadFile(data.Path.ValueString())
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("path"),
+			"Unable to read vault secret export",
+			fmt.Sprintf("Error reading %s: %s", data.Path.ValueString(), err),
+		)
+		return
+	}
+
+	// A missing or outdated file is written again by planning a new export
+	if err != nil || !bytes.Equal(content, expected) {
+		tflog.Debug(ctx, "vault secret export is out of date, planning to rewrite it", map[string]interface{}{
+			"path": data.Path.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	data.ContentSHA256 = types.StringValue(hashSecretValue(string(content)))
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *VaultSecretExportResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = r.providerData.logContext(ctx)
+
+	release, ok := r.providerData.beginOperation(ctx, &resp.Diagnostics)
+	if !ok {
+		return
+	}
+	defer release()
+
+	var data VaultSecretExportModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.write(ctx, &data); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("path"),
+			"Unable to export vault secret metadata",
+			withRemediation(err.Error(), err),
+		)
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *VaultSecretExportResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data VaultSecretExportModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// A file that's already gone needs no cleanup
+	if err := os.Remove(data.Path.ValueString()); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("path"),
+			"Unable to remove vault secret export",
+			fmt.Sprintf("Error removing %s: %s", data.Path.ValueString(), err),
+		)
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestAccVaultSecretExportResource(t *testing.T) {
+	// Skip if TF_ACC is not set
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests skipped unless env 'TF_ACC' set")
+	}
+
+	exportPath := filepath.Join(t.TempDir(), "manifest", "secrets.json")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(*terraform.State) error {
+			if _, err := os.Stat(exportPath); !os.IsNotExist(err) {
+				return fmt.Errorf("expected %s to be removed, got: %v", exportPath, err)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccVaultSecretExportResourceConfig("test-secret-export", "the-value", exportPath),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"supabase-vault_secret_export.test",
+						tfjsonpath.New("content_sha256"),
+						knownvalue.NotNull(),
+					),
+				},
+				Check: testAccCheckVaultSecretExport(exportPath, "test-secret-export", "the-value"),
+			},
+			// Deleting the file outside Terraform writes it again
+			{
+				PreConfig: func() {
+					if err := os.Remove(exportPath); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config: testAccVaultSecretExportResourceConfig("test-secret-export", "the-value", exportPath),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("supabase-vault_secret_export.test", plancheck.ResourceActionCreate),
+					},
+				},
+				Check: testAccCheckVaultSecretExport(exportPath, "test-secret-export", "the-value"),
+			},
+		},
+	})
+}
+
+// testAccCheckVaultSecretExport checks that the export at exportPath lists
+// the named secret without its value.
+func testAccCheckVaultSecretExport(exportPath, name, value string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		content, err := os.ReadFile(exportPath)
+		if err != nil {
+			return err
+		}
+
+		var secrets []map[string]any
+		if err := json.Unmarshal(content, &secrets); err != nil {
+			return fmt.Errorf("parsing %s: %w", exportPath, err)
+		}
+
+		found := false
+		for _, secret := range secrets {
+			if secret["name"] == name {
+				found = true
+			}
+			for key, field := range secret {
+				if field == value {
+					return fmt.Errorf("export exposes the secret value in %q", key)
+				}
+			}
+		}
+		if !found {
+			return fmt.Errorf("secret %q not found in %s", name, exportPath)
+		}
+
+		return nil
+	}
+}
+
+func testAccVaultSecretExportResourceConfig(name, value, exportPath string) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "supabase-vault_secret" "test" {
+  name        = %[1]q
+  value       = %[2]q
+  description = "Exported secret"
+}
+
+resource "supabase-vault_secret_export" "test" {
+  path = %[3]q
+
+  depends_on = [supabase-vault_secret.test]
+}
+`, name, value, exportPath)
+}
+
+func TestRenderSecretExport(t *testing.T) {
+	name := "api_key"
+	keyID := "6f1c1c0e-2b5a-4f8e-9d3c-1a2b3c4d5e6f"
+
+	rows := []secretMetadataRow{
+		{
+			ID:          "0b9d5a4e-8f2c-4d1a-9e7b-3c6f5a2d1e0f",
+			Name:        &name,
+			Description: appendManagedByFooter(appendLabels("API key", map[string]string{"owner": "platform"}), defaultFooterSeparator, "1.0.0"),
+			KeyID:       &keyID,
+			TotalCount:  1,
+		},
+	}
+
+	content, err := renderSecretExport(rows, defaultFooterSeparator)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := `[
+  {
+    "id": "0b9d5a4e-8f2c-4d1a-9e7b-3c6f5a2d1e0f",
+    "name": "api_key",
+    "description": "API key",
+    "key_id": "6f1c1c0e-2b5a-4f8e-9d3c-1a2b3c4d5e6f",
+    "labels": {
+      "owner": "platform"
+    }
+  }
+]
+`
+	if string(content) != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, content)
+	}
+
+	// The rows are rendered as read, not modified in place
+	if rows[0].Labels != nil {
+		t.Errorf("expected the input rows to be left untouched, got labels %v", rows[0].Labels)
+	}
+
+	empty, err := renderSecretExport(nil, defaultFooterSeparator)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if string(empty) != "[]\n" {
+		t.Errorf("expected an empty array, got %q", empty)
+	}
+}
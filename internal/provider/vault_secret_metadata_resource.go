@@ -0,0 +1,351 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/jackc/pgx/v5"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &VaultSecretMetadataResource{}
+var _ resource.ResourceWithValidateConfig = &VaultSecretMetadataResource{}
+var _ resource.ResourceWithImportState = &VaultSecretMetadataResource{}
+
+func NewVaultSecretMetadataResource() resource.Resource {
+	return &VaultSecretMetadataResource{}
+}
+
+// VaultSecretMetadataResource defines the resource implementation.
+type VaultSecretMetadataResource struct {
+	providerData *ProviderData
+}
+
+// VaultSecretMetadataResourceModel describes the resource data model.
+type VaultSecretMetadataResourceModel struct {
+	ID          types.String `tfsdk:"id"`
+	Name        types.String `tfsdk:"name"`
+	Description types.String `tfsdk:"description"`
+}
+
+func (r *VaultSecretMetadataResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_secret_metadata"
+}
+
+func (r *VaultSecretMetadataResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages the name and description of an existing secret in Supabase Vault whose value is owned by another process. " +
+			"Only the metadata columns of `vault.secrets` are updated and the value never leaves the database. " +
+			"On `supabase_vault` releases without `vault.update_secret`, whose encryption binds the value to its description, the stored value is re-encrypted in the database along with the change. " +
+			"Creating the resource takes over the secret named `name`; destroying it leaves the secret as it is.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Secret UUID",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Name of the secret, at most 255 bytes. The secret must exist when the resource is created; changing the name afterwards renames it.",
+				Required:            true,
+			},
+			"description": schema.StringAttribute{
+				MarkdownDescription: "Description of the secret. The provider's `default_description` is used when omitted.",
+				Optional:            true,
+			},
+		},
+	}
+}
+
+func (r *VaultSecretMetadataResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data VaultSecretMetadataResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if isKnown(data.Name) {
+		if err := validateSecretName(data.Name.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("name"),
+				"Invalid secret name",
+				err.Error(),
+			)
+		}
+	}
+
+	if isKnown(data.Description) {
+		if err := validateDescription(data.Description.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("description"),
+				"Invalid description",
+				err.Error(),
+			)
+		}
+	}
+}
+
+func (r *VaultSecretMetadataResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.providerData = providerData
+}
+
+// secretName returns the name to store for the planned secret, adding an
+// attribute error to diags if it can't be stored.
+func (r *VaultSecretMetadataResource) secretName(data VaultSecretMetadataResourceModel, diags *diag.Diagnostics) string {
+	if err := checkAllowedName(data.Name.ValueString(), r.providerData.AllowedNamePatterns); err != nil {
+		diags.AddAttributeError(path.Root("name"), "Secret name not allowed", err.Error())
+		return ""
+	}
+
+	name, err := encodeSecretName(data.Name.ValueString(), r.providerData.AllowInvalidUTF8Names)
+	if err != nil {
+		diags.AddAttributeError(path.Root("name"), "Invalid secret name", err.Error())
+	}
+
+	return name
+}
+
+func (r *VaultSecretMetadataResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.providerData.logContext(ctx)
+
+	release, ok := r.providerData.beginOperation(ctx, &resp.Diagnostics)
+	if !ok {
+		return
+	}
+	defer release()
+
+	if !r.providerData.checkWritable(&resp.Diagnostics, "update vault secret metadata") {
+		return
+	}
+
+	var data VaultSecretMetadataResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	secretName := r.secretName(data, &resp.Diagnostics)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var id string
+	err := r.providerData.queryRow(ctx, "SELECT id FROM vault.secrets WHERE name = $1", secretName).Scan(&id)
+
+	if err == pgx.ErrNoRows {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("name"),
+			"Secret not found",
+			fmt.Sprintf("No secret found with name: %s. supabase-vault_secret_metadata only manages existing secrets; create the secret first, or use supabase-vault_secret to manage its value too.", data.Name.ValueString()),
+		)
+		return
+	}
+
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to look up vault secret",
+			withRemediation(fmt.Sprintf("Error looking up secret %q: %s", data.Name.ValueString(), err), err),
+		)
+		return
+	}
+
+	if err := r.providerData.secrets().updateMetadata(ctx, id, secretName, r.providerData.storedDescription(data.Description, nil)); err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to update vault secret metadata",
+			withRemediation(fmt.Sprintf("Error updating secret metadata: %s", err), err),
+		)
+		return
+	}
+
+	data.ID = types.StringValue(id)
+
+	tflog.Trace(ctx, "took over vault secret metadata", map[string]interface{}{
+		"id":   id,
+		"name": data.Name.ValueString(),
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *VaultSecretMetadataResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = r.providerData.logContext(ctx)
+
+	release, ok := r.providerData.beginOperation(ctx, &resp.Diagnostics)
+	if !ok {
+		return
+	}
+	defer release()
+
+	var data VaultSecretMetadataResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Guard against corrupted state before querying by ID
+	if err := validateSecretID(data.ID.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("id"),
+			"Invalid vault secret ID in state",
+			fmt.Sprintf("The secret ID stored in state is malformed: %s. Remove the resource from state and re-import it.", err),
+		)
+		return
+	}
+
+	// Metadata is stored in plaintext in vault.secrets, so no decryption is needed
+	var name, description string
+	err := r.providerData.queryRow(ctx, "SELECT name, description FROM vault.secrets WHERE id = $1", data.ID.ValueString()).Scan(&name, &description)
+
+	if err == pgx.ErrNoRows {
+		// Secret not found, mark as removed
+		if r.providerData.allowMissing(&resp.Diagnostics, fmt.Sprintf("secret %q (%s)", data.Name.ValueString(), data.ID.ValueString())) {
+			resp.State.RemoveResource(ctx)
+		}
+		return
+	}
+
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to read vault secret metadata",
+			withRemediation(fmt.Sprintf("Error reading secret metadata: %s", err), err),
+		)
+		return
+	}
+
+	data.Name = types.StringValue(decodeSecretName(name, r.providerData.AllowInvalidUTF8Names))
+	data.Description, _ = r.providerData.configuredDescription(description, data.Description)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *VaultSecretMetadataResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = r.providerData.logContext(ctx)
+
+	release, ok := r.providerData.beginOperation(ctx, &resp.Diagnostics)
+	if !ok {
+		return
+	}
+	defer release()
+
+	if !r.providerData.checkWritable(&resp.Diagnostics, "update vault secret metadata") {
+		return
+	}
+
+	var data VaultSecretMetadataResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	secretName := r.secretName(data, &resp.Diagnostics)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.providerData.secrets().updateMetadata(ctx, data.ID.ValueString(), secretName, r.providerData.storedDescription(data.Description, nil)); err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to update vault secret metadata",
+			withRemediation(fmt.Sprintf("Error updating secret metadata: %s", err), err),
+		)
+		return
+	}
+
+	tflog.Trace(ctx, "updated vault secret metadata", map[string]interface{}{
+		"id":   data.ID.ValueString(),
+		"name": data.Name.ValueString(),
+	})
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *VaultSecretMetadataResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data VaultSecretMetadataResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The secret belongs to whichever process owns its value, so it is only
+	// removed from state
+	tflog.Trace(ctx, "released vault secret metadata", map[string]interface{}{
+		"id": data.ID.ValueString(),
+	})
+}
+
+func (r *VaultSecretMetadataResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Import ID formats: <name> or <uuid>
+	query := "SELECT id, name FROM vault.secrets WHERE name = $1"
+	if validateSecretID(req.ID) == nil {
+		query = "SELECT id, name FROM vault.secrets WHERE id = $1"
+	}
+
+	var id, name string
+	err := r.providerData.queryRow(ctx, query, req.ID).Scan(&id, &name)
+
+	if err == pgx.ErrNoRows {
+		resp.Diagnostics.AddError(
+			"Secret not found",
+			fmt.Sprintf("No secret found with name or id: %s", req.ID),
+		)
+		return
+	}
+
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to import vault secret metadata",
+			withRemediation(fmt.Sprintf("Error looking up secret: %s", err), err),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), decodeSecretName(name, r.providerData.AllowInvalidUTF8Names))...)
+}
@@ -0,0 +1,131 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestAccVaultSecretMetadataResource(t *testing.T) {
+	// Skip if TF_ACC is not set
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests skipped unless env 'TF_ACC' set")
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccVaultSecretMetadataResourceConfig("test-secret-metadata", "First description"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"supabase-vault_secret_metadata.test",
+						tfjsonpath.New("description"),
+						knownvalue.StringExact("First description"),
+					),
+				},
+			},
+			{
+				Config: testAccVaultSecretMetadataResourceConfig("test-secret-metadata", "Second description"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"supabase-vault_secret_metadata.test",
+						tfjsonpath.New("description"),
+						knownvalue.StringExact("Second description"),
+					),
+				},
+			},
+			{
+				ResourceName:      "supabase-vault_secret_metadata.test",
+				ImportState:       true,
+				ImportStateId:     "test-secret-metadata",
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccVaultSecretMetadataResourceConfig(name, description string) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+# Stands in for the process that owns the value
+resource "supabase-vault_secret" "owner" {
+  name  = %[1]q
+  value = "owned-elsewhere"
+
+  lifecycle {
+    ignore_changes = [description]
+  }
+}
+
+resource "supabase-vault_secret_metadata" "test" {
+  name        = supabase-vault_secret.owner.name
+  description = %[2]q
+}
+`, name, description)
+}
+
+func TestVaultSecretMetadataResourceValidateConfig(t *testing.T) {
+	testCases := map[string]struct {
+		config    map[string]tftypes.Value
+		expectErr bool
+	}{
+		"valid": {
+			config: map[string]tftypes.Value{
+				"name":        tftypes.NewValue(tftypes.String, "api_key"),
+				"description": tftypes.NewValue(tftypes.String, "API key"),
+			},
+		},
+		"invalid name": {
+			config: map[string]tftypes.Value{
+				"name": tftypes.NewValue(tftypes.String, ""),
+			},
+			expectErr: true,
+		},
+		"description with footer": {
+			config: map[string]tftypes.Value{
+				"name":        tftypes.NewValue(tftypes.String, "api_key"),
+				"description": tftypes.NewValue(tftypes.String, appendManagedByFooter("API key", defaultFooterSeparator, "1.0.0")),
+			},
+			expectErr: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			r := &VaultSecretMetadataResource{}
+
+			schemaResp := &fwresource.SchemaResponse{}
+			r.Schema(ctx, fwresource.SchemaRequest{}, schemaResp)
+
+			req := fwresource.ValidateConfigRequest{
+				Config: tfsdk.Config{
+					Schema: schemaResp.Schema,
+					Raw:    testConfigValue(t, schemaResp.Schema.Type(), testCase.config),
+				},
+			}
+			resp := &fwresource.ValidateConfigResponse{}
+			r.ValidateConfig(ctx, req, resp)
+
+			if testCase.expectErr && !resp.Diagnostics.HasError() {
+				t.Fatal("expected an error, got none")
+			}
+			if !testCase.expectErr && resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected error: %v", resp.Diagnostics)
+			}
+		})
+	}
+}
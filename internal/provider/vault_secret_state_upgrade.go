@@ -0,0 +1,140 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// vaultSecretSchemaVersion is the current version of the secret resource
+// schema. Bump it, and add an upgrader, whenever a change would leave older
+// state inconsistent with the schema.
+const vaultSecretSchemaVersion = 1
+
+var _ resource.ResourceWithUpgradeState = &VaultSecretResource{}
+
+// vaultSecretModelV0 describes version 0 of the secret resource state. The
+// attributes after description were added without a version bump, so state
+// written by earlier releases doesn't have them.
+type vaultSecretModelV0 struct {
+	ID          types.String `tfsdk:"id"`
+	Name        types.String `tfsdk:"name"`
+	Value       types.String `tfsdk:"value"`
+	KeyID       types.String `tfsdk:"key_id"`
+	Description types.String `tfsdk:"description"`
+
+	ValueTemplate       types.Bool   `tfsdk:"value_template"`
+	Vars                types.Map    `tfsdk:"vars"`
+	ValueHash           types.String `tfsdk:"value_hash"`
+	DescriptionChecksum types.String `tfsdk:"description_checksum"`
+	AdoptExisting       types.Bool   `tfsdk:"adopt_existing"`
+}
+
+func (r *VaultSecretResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: {
+			PriorSchema: &schema.Schema{
+				Attributes: map[string]schema.Attribute{
+					"id": schema.StringAttribute{
+						Computed: true,
+					},
+					"name": schema.StringAttribute{
+						Required: true,
+					},
+					"value": schema.StringAttribute{
+						Required:  true,
+						Sensitive: true,
+					},
+					"key_id": schema.StringAttribute{
+						Optional: true,
+						Computed: true,
+					},
+					"description": schema.StringAttribute{
+						Optional: true,
+					},
+					"value_template": schema.BoolAttribute{
+						Optional: true,
+					},
+					"vars": schema.MapAttribute{
+						ElementType: types.StringType,
+						Optional:    true,
+						Sensitive:   true,
+					},
+					"value_hash": schema.StringAttribute{
+						Computed: true,
+					},
+					"description_checksum": schema.StringAttribute{
+						Computed: true,
+					},
+					"adopt_existing": schema.BoolAttribute{
+						Optional: true,
+					},
+				},
+			},
+			StateUpgrader: upgradeVaultSecretStateV0,
+		},
+	}
+}
+
+// upgradeVaultSecretStateV0 carries version 0 state forward, backfilling the
+// computed value and description digests where the state predates them so
+// the first plan after upgrading the provider is empty.
+func upgradeVaultSecretStateV0(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+	var prior vaultSecretModelV0
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &prior)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// State without a hash predates templates, so its value is the stored value
+	valueHash := prior.ValueHash
+	if valueHash.IsNull() && !prior.Value.IsNull() {
+		valueHash = types.StringValue(hashSecretValue(prior.Value.ValueString()))
+	}
+
+	upgraded := VaultSecretModel{
+		ID:          prior.ID,
+		Name:        prior.Name,
+		Value:       prior.Value,
+		KeyID:       prior.KeyID,
+		Description: prior.Description,
+		Labels:      types.MapNull(types.StringType),
+		Nonce:       types.StringNull(),
+		CreatedAt:   types.StringNull(),
+		UpdatedAt:   types.StringNull(),
+		CreatedBy:   types.StringNull(),
+		KeyStatus:   types.StringNull(),
+
+		ValueWO:        types.StringNull(),
+		ValueWOVersion: types.Int64Null(),
+
+		ValueTemplate: prior.ValueTemplate,
+		Vars:          prior.Vars,
+		ValueHash:     valueHash,
+		ValuePattern:  types.StringNull(),
+
+		DescriptionChecksum: descriptionChecksum(prior.Description),
+		ManagedByVersion:    types.StringNull(),
+
+		AdoptExisting:    prior.AdoptExisting,
+		Immutable:        types.BoolNull(),
+		ValidateOnly:     types.BoolNull(),
+		ContentAddressed: types.BoolNull(),
+
+		ExpiresAt: types.StringNull(),
+		IsExpired: types.BoolNull(),
+
+		Environment: types.StringNull(),
+
+		Connection: types.ObjectNull(connectionOverrideAttrTypes),
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, upgraded)...)
+}
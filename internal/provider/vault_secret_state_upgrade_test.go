@@ -0,0 +1,91 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestUpgradeVaultSecretStateV0(t *testing.T) {
+	testCases := map[string]struct {
+		state             map[string]tftypes.Value
+		expectedValueHash string
+	}{
+		"original attributes only": {
+			state: map[string]tftypes.Value{
+				"id":          tftypes.NewValue(tftypes.String, "00000000-0000-0000-0000-000000000001"),
+				"name":        tftypes.NewValue(tftypes.String, "api_key"),
+				"value":       tftypes.NewValue(tftypes.String, "my-secret-value"),
+				"description": tftypes.NewValue(tftypes.String, "API key"),
+			},
+			expectedValueHash: hashSecretValue("my-secret-value"),
+		},
+		"existing hash": {
+			state: map[string]tftypes.Value{
+				"id":             tftypes.NewValue(tftypes.String, "00000000-0000-0000-0000-000000000001"),
+				"name":           tftypes.NewValue(tftypes.String, "api_key"),
+				"value":          tftypes.NewValue(tftypes.String, "{{.user}}"),
+				"value_template": tftypes.NewValue(tftypes.Bool, true),
+				"value_hash":     tftypes.NewValue(tftypes.String, hashSecretValue("admin")),
+			},
+			expectedValueHash: hashSecretValue("admin"),
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			r := &VaultSecretResource{}
+
+			upgrader := r.UpgradeState(ctx)[0]
+
+			schemaResp := &resource.SchemaResponse{}
+			r.Schema(ctx, resource.SchemaRequest{}, schemaResp)
+
+			priorState := tfsdk.State{
+				Schema: *upgrader.PriorSchema,
+				Raw:    testConfigValue(t, upgrader.PriorSchema.Type(), testCase.state),
+			}
+
+			req := resource.UpgradeStateRequest{State: &priorState}
+			resp := &resource.UpgradeStateResponse{
+				State: tfsdk.State{
+					Schema: schemaResp.Schema,
+					Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+				},
+			}
+
+			upgrader.StateUpgrader(ctx, req, resp)
+
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected error: %v", resp.Diagnostics)
+			}
+
+			var upgraded VaultSecretModel
+			resp.Diagnostics.Append(resp.State.Get(ctx, &upgraded)...)
+
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected error reading upgraded state: %v", resp.Diagnostics)
+			}
+
+			if upgraded.Name.ValueString() != "api_key" {
+				t.Errorf("expected name to be preserved, got %q", upgraded.Name.ValueString())
+			}
+			if upgraded.ValueHash.ValueString() != testCase.expectedValueHash {
+				t.Errorf("expected value_hash %q, got %q", testCase.expectedValueHash, upgraded.ValueHash.ValueString())
+			}
+			if !upgraded.DescriptionChecksum.Equal(descriptionChecksum(upgraded.Description)) {
+				t.Errorf("expected description_checksum to be backfilled, got %q", upgraded.DescriptionChecksum.ValueString())
+			}
+			if !upgraded.KeyID.IsNull() {
+				t.Errorf("expected key_id to stay null, got %q", upgraded.KeyID.ValueString())
+			}
+		})
+	}
+}
@@ -4,14 +4,24 @@
 package provider
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
 	"os"
+	"regexp"
+	"strings"
 	"testing"
 
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/compare"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
 	"github.com/hashicorp/terraform-plugin-testing/statecheck"
 	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
 )
 
 func TestAccVaultSecretResource(t *testing.T) {
@@ -50,45 +60,595 @@ func TestAccVaultSecretResource(t *testing.T) {
 					),
 				},
 			},
+			// Re-applying an identical value plans no changes
+			{
+				Config: testAccVaultSecretResourceConfig("test-secret-1", "my-secret-value", "Test secret description"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"supabase-vault_secret.test",
+						tfjsonpath.New("value_hash"),
+						knownvalue.StringExact(hashSecretValue("my-secret-value")),
+					),
+				},
+			},
 			// ImportState testing
 			{
-				ResourceName:            "supabase-vault_secret.test",
-				ImportState:             true,
-				ImportStateVerify:       true,
-				ImportStateVerifyIgnore: []string{"value"}, // Value is not read back for security
+				ResourceName:            "supabase-vault_secret.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"value", "value_hash"}, // Value is not read back for security
+			},
+			// Importing with !withvalue reads the value back too
+			{
+				ResourceName:      "supabase-vault_secret.test",
+				ImportState:       true,
+				ImportStateId:     "test-secret-1" + importWithValueSuffix,
+				ImportStateVerify: true,
+			},
+			// Update and Read testing
+			{
+				Config: testAccVaultSecretResourceConfig("test-secret-1", "updated-secret-value", "Updated test secret description"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"supabase-vault_secret.test",
+						tfjsonpath.New("name"),
+						knownvalue.StringExact("test-secret-1"),
+					),
+					statecheck.ExpectKnownValue(
+						"supabase-vault_secret.test",
+						tfjsonpath.New("value"),
+						knownvalue.StringExact("updated-secret-value"),
+					),
+					statecheck.ExpectKnownValue(
+						"supabase-vault_secret.test",
+						tfjsonpath.New("description"),
+						knownvalue.StringExact("Updated test secret description"),
+					),
+					statecheck.ExpectKnownValue(
+						"supabase-vault_secret.test",
+						tfjsonpath.New("id"),
+						knownvalue.NotNull(),
+					),
+				},
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccVaultSecretResource_Minimal(t *testing.T) {
+	// Skip if TF_ACC is not set
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests skipped unless env 'TF_ACC' set")
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create with minimal config (only name and value)
+			{
+				Config: testAccVaultSecretResourceConfigMinimal("test-secret-minimal", "minimal-value"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"supabase-vault_secret.test",
+						tfjsonpath.New("name"),
+						knownvalue.StringExact("test-secret-minimal"),
+					),
+					statecheck.ExpectKnownValue(
+						"supabase-vault_secret.test",
+						tfjsonpath.New("value"),
+						knownvalue.StringExact("minimal-value"),
+					),
+					statecheck.ExpectKnownValue(
+						"supabase-vault_secret.test",
+						tfjsonpath.New("id"),
+						knownvalue.NotNull(),
+					),
+					statecheck.ExpectKnownValue(
+						"supabase-vault_secret.test",
+						tfjsonpath.New("created_at"),
+						knownvalue.StringRegexp(regexp.MustCompile(`^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}Z$`)),
+					),
+					statecheck.ExpectKnownValue(
+						"supabase-vault_secret.test",
+						tfjsonpath.New("updated_at"),
+						knownvalue.NotNull(),
+					),
+					// Supabase Vault doesn't record who created a secret
+					statecheck.ExpectKnownValue(
+						"supabase-vault_secret.test",
+						tfjsonpath.New("created_by"),
+						knownvalue.Null(),
+					),
+					// Without a description no managed-by footer is stored
+					statecheck.ExpectKnownValue(
+						"supabase-vault_secret.test",
+						tfjsonpath.New("managed_by_version"),
+						knownvalue.Null(),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestAccVaultSecretResource_DescriptionOnly(t *testing.T) {
+	// Skip if TF_ACC is not set
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests skipped unless env 'TF_ACC' set")
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccVaultSecretResourceConfig("test-secret-description", "unchanged-value", "Original description"),
+			},
+			// Change only the description; the value is left untouched
+			{
+				Config: testAccVaultSecretResourceConfig("test-secret-description", "unchanged-value", "Changed description"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("supabase-vault_secret.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"supabase-vault_secret.test",
+						tfjsonpath.New("description"),
+						knownvalue.StringExact("Changed description"),
+					),
+					statecheck.ExpectKnownValue(
+						"supabase-vault_secret.test",
+						tfjsonpath.New("value"),
+						knownvalue.StringExact("unchanged-value"),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestAccVaultSecretResource_RemoveDescription(t *testing.T) {
+	// Skip if TF_ACC is not set
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests skipped unless env 'TF_ACC' set")
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccVaultSecretResourceConfig("test-secret-remove-description", "some-value", "Soon gone"),
+			},
+			// The stored description is cleared rather than left as a lone footer
+			{
+				Config: testAccVaultSecretResourceConfigMinimal("test-secret-remove-description", "some-value"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"supabase-vault_secret.test",
+						tfjsonpath.New("description"),
+						knownvalue.Null(),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestAccVaultSecretResource_PreserveValue(t *testing.T) {
+	// Skip if TF_ACC is not set
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests skipped unless env 'TF_ACC' set")
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccVaultSecretResourceConfig("test-secret-preserve", "external-value", "Original description"),
+			},
+			// Dropping value hands it over; only the description is updated
+			{
+				Config: testAccProviderConfig() + `
+resource "supabase-vault_secret" "test" {
+  name        = "test-secret-preserve"
+  description = "Metadata only"
+}
+
+data "supabase-vault_secret_value" "test" {
+  name = supabase-vault_secret.test.name
+}
+`,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("supabase-vault_secret.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"supabase-vault_secret.test",
+						tfjsonpath.New("description"),
+						knownvalue.StringExact("Metadata only"),
+					),
+					statecheck.ExpectKnownValue(
+						"data.supabase-vault_secret_value.test",
+						tfjsonpath.New("value"),
+						knownvalue.StringExact("external-value"),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestAccVaultSecretResource_Labels(t *testing.T) {
+	// Skip if TF_ACC is not set
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests skipped unless env 'TF_ACC' set")
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccVaultSecretResourceConfigLabels("test-secret-labels", `owner = "payments"`),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"supabase-vault_secret.test",
+						tfjsonpath.New("description"),
+						knownvalue.StringExact("Labelled secret"),
+					),
+				},
+			},
+			// Changing the labels is a metadata-only update
+			{
+				Config: testAccVaultSecretResourceConfigLabels("test-secret-labels", `owner = "payments"
+    rotation_policy = "90d"`),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("supabase-vault_secret.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"supabase-vault_secret.test",
+						tfjsonpath.New("labels"),
+						knownvalue.MapExact(map[string]knownvalue.Check{
+							"owner":           knownvalue.StringExact("payments"),
+							"rotation_policy": knownvalue.StringExact("90d"),
+						}),
+					),
+				},
+			},
+			// The labels survive an import
+			{
+				ResourceName:            "supabase-vault_secret.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"value", "value_hash"},
+			},
+		},
+	})
+}
+
+func testAccVaultSecretResourceConfigLabels(name, labels string) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "supabase-vault_secret" "test" {
+  name        = %[1]q
+  value       = "labelled-value"
+  description = "Labelled secret"
+
+  labels = {
+    %[2]s
+  }
+}
+`, name, labels)
+}
+
+func TestAccVaultSecretResource_UpdateKeepsKeyID(t *testing.T) {
+	// Skip if TF_ACC is not set
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests skipped unless env 'TF_ACC' set")
+	}
+
+	keyIDSame := statecheck.CompareValue(compare.ValuesSame())
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccVaultSecretResourceConfig("test-secret-key-id", "first-value", "First description"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					keyIDSame.AddStateValue("supabase-vault_secret.test", tfjsonpath.New("key_id")),
+				},
+			},
+			// Updating the value re-reads key_id, which must be unchanged
+			{
+				Config: testAccVaultSecretResourceConfig("test-secret-key-id", "second-value", "Second description"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					keyIDSame.AddStateValue("supabase-vault_secret.test", tfjsonpath.New("key_id")),
+				},
+			},
+		},
+	})
+}
+
+func TestAccVaultSecretResource_Rename(t *testing.T) {
+	// Skip if TF_ACC is not set
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests skipped unless env 'TF_ACC' set")
+	}
+
+	idSame := statecheck.CompareValue(compare.ValuesSame())
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccVaultSecretResourceConfig("test-secret-rename-before", "rename-value", "Renamed secret"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					idSame.AddStateValue("supabase-vault_secret.test", tfjsonpath.New("id")),
+				},
+			},
+			// Renaming is an in-place update that keeps the secret's ID
+			{
+				Config: testAccVaultSecretResourceConfig("test-secret-rename-after", "rename-value", "Renamed secret"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("supabase-vault_secret.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				ConfigStateChecks: []statecheck.StateCheck{
+					idSame.AddStateValue("supabase-vault_secret.test", tfjsonpath.New("id")),
+					statecheck.ExpectKnownValue(
+						"supabase-vault_secret.test",
+						tfjsonpath.New("name"),
+						knownvalue.StringExact("test-secret-rename-after"),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestAccVaultSecretResource_AdoptExisting(t *testing.T) {
+	// Skip if TF_ACC is not set
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests skipped unless env 'TF_ACC' set")
+	}
+
+	idSame := statecheck.CompareValue(compare.ValuesSame())
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			// removed blocks need Terraform 1.7
+			tfversion.SkipBelow(tfversion.Version1_7_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccVaultSecretResourceConfig("test-secret-adopt", "original-value", "Pre-existing secret"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					idSame.AddStateValue("supabase-vault_secret.test", tfjsonpath.New("id")),
+				},
+			},
+			// Forget the secret without deleting it, then adopt it under a new address
+			{
+				Config: testAccProviderConfig() + `
+removed {
+  from = supabase-vault_secret.test
+
+  lifecycle {
+    destroy = false
+  }
+}
+
+resource "supabase-vault_secret" "adopted" {
+  name           = "test-secret-adopt"
+  value          = "adopted-value"
+  adopt_existing = true
+}
+`,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("supabase-vault_secret.adopted", plancheck.ResourceActionCreate),
+						plancheck.ExpectKnownValue(
+							"supabase-vault_secret.adopted",
+							tfjsonpath.New("id"),
+							knownvalue.NotNull(),
+						),
+					},
+				},
+				ConfigStateChecks: []statecheck.StateCheck{
+					idSame.AddStateValue("supabase-vault_secret.adopted", tfjsonpath.New("id")),
+					statecheck.ExpectKnownValue(
+						"supabase-vault_secret.adopted",
+						tfjsonpath.New("value_hash"),
+						knownvalue.StringExact(hashSecretValue("adopted-value")),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestAccVaultSecretResource_ImportWithoutDecrypt(t *testing.T) {
+	// Skip if TF_ACC is not set
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests skipped unless env 'TF_ACC' set")
+	}
+
+	// A role that can read vault.secrets but not vault.decrypted_secrets
+	user := os.Getenv("SUPABASE_NO_DECRYPT_USER")
+	if user == "" {
+		t.Skip("Acceptance test skipped unless env 'SUPABASE_NO_DECRYPT_USER' set")
+	}
+	password := os.Getenv("SUPABASE_NO_DECRYPT_PASSWORD")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccVaultSecretResourceConfig("test-secret-no-decrypt", "no-decrypt-value", "Imported without decryption"),
+			},
+			{
+				Config: testAccProviderConfigForRole(user, password) + `
+resource "supabase-vault_secret" "test" {
+  name        = "test-secret-no-decrypt"
+  value       = "no-decrypt-value"
+  description = "Imported without decryption"
+}
+`,
+				ResourceName:            "supabase-vault_secret.test",
+				ImportState:             true,
+				ImportStateId:           "test-secret-no-decrypt",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"value", "value_hash"},
+			},
+		},
+	})
+}
+
+func TestAccVaultSecretResource_Immutable(t *testing.T) {
+	// Skip if TF_ACC is not set
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests skipped unless env 'TF_ACC' set")
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccVaultSecretResourceConfigImmutable("test-secret-immutable", "bootstrap-token", "First description"),
 			},
-			// Update and Read testing
+			// The description can still change
 			{
-				Config: testAccVaultSecretResourceConfig("test-secret-1", "updated-secret-value", "Updated test secret description"),
+				Config: testAccVaultSecretResourceConfigImmutable("test-secret-immutable", "bootstrap-token", "Second description"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("supabase-vault_secret.test", plancheck.ResourceActionUpdate),
+					},
+				},
+			},
+			// The value can't
+			{
+				Config:      testAccVaultSecretResourceConfigImmutable("test-secret-immutable", "rotated-token", "Second description"),
+				ExpectError: regexp.MustCompile(`Immutable vault secret`),
+			},
+		},
+	})
+}
+
+func TestAccVaultSecretResource_ValidateOnly(t *testing.T) {
+	// Skip if TF_ACC is not set
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests skipped unless env 'TF_ACC' set")
+	}
+
+	config := testAccProviderConfig() + `
+resource "supabase-vault_secret" "test" {
+  name          = "test-secret-validate-only"
+  value         = "never-stored"
+  validate_only = true
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
 				ConfigStateChecks: []statecheck.StateCheck{
 					statecheck.ExpectKnownValue(
 						"supabase-vault_secret.test",
-						tfjsonpath.New("name"),
-						knownvalue.StringExact("test-secret-1"),
-					),
-					statecheck.ExpectKnownValue(
-						"supabase-vault_secret.test",
-						tfjsonpath.New("value"),
-						knownvalue.StringExact("updated-secret-value"),
+						tfjsonpath.New("id"),
+						knownvalue.Null(),
 					),
+				},
+			},
+			// The rolled back secret was never stored
+			{
+				Config: config + `
+data "supabase-vault_secret_exists" "test" {
+  name = "test-secret-validate-only"
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
 					statecheck.ExpectKnownValue(
-						"supabase-vault_secret.test",
-						tfjsonpath.New("description"),
-						knownvalue.StringExact("Updated test secret description"),
+						"data.supabase-vault_secret_exists.test",
+						tfjsonpath.New("exists"),
+						knownvalue.Bool(false),
 					),
+				},
+			},
+		},
+	})
+}
+
+func TestAccVaultSecretResource_ContentAddressed(t *testing.T) {
+	// Skip if TF_ACC is not set
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests skipped unless env 'TF_ACC' set")
+	}
+
+	config := testAccProviderConfig() + `
+resource "supabase-vault_secret" "test" {
+  name              = "test-secret-content-addressed"
+  value             = "content-addressed-value"
+  content_addressed = true
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// The hash label isn't shown as a configured label
+			{
+				Config: config,
+				ConfigStateChecks: []statecheck.StateCheck{
 					statecheck.ExpectKnownValue(
 						"supabase-vault_secret.test",
-						tfjsonpath.New("id"),
-						knownvalue.NotNull(),
+						tfjsonpath.New("labels"),
+						knownvalue.Null(),
 					),
 				},
 			},
-			// Delete testing automatically occurs in TestCase
+			// The same value isn't stored under a second name
+			{
+				Config: config + `
+resource "supabase-vault_secret" "duplicate" {
+  name              = "test-secret-content-addressed-duplicate"
+  value             = "content-addressed-value"
+  content_addressed = true
+
+  depends_on = [supabase-vault_secret.test]
+}
+`,
+				ExpectError: regexp.MustCompile(`Value already stored`),
+			},
 		},
 	})
 }
 
-func TestAccVaultSecretResource_Minimal(t *testing.T) {
+func TestAccVaultSecretResource_Environment(t *testing.T) {
 	// Skip if TF_ACC is not set
 	if os.Getenv("TF_ACC") == "" {
 		t.Skip("Acceptance tests skipped unless env 'TF_ACC' set")
@@ -98,27 +658,53 @@ func TestAccVaultSecretResource_Minimal(t *testing.T) {
 		PreCheck:                 func() { testAccPreCheck(t) },
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
-			// Create with minimal config (only name and value)
+			// The same name is stored once per environment
 			{
-				Config: testAccVaultSecretResourceConfigMinimal("test-secret-minimal", "minimal-value"),
+				Config: testAccProviderConfig() + `
+resource "supabase-vault_secret" "staging" {
+  name        = "test-secret-environment"
+  value       = "staging-value"
+  environment = "staging"
+}
+
+resource "supabase-vault_secret" "prod" {
+  name        = "test-secret-environment"
+  value       = "prod-value"
+  environment = "prod"
+}
+
+data "supabase-vault_secret_value" "prod" {
+  name = "prod_test-secret-environment"
+
+  depends_on = [supabase-vault_secret.prod]
+}
+`,
 				ConfigStateChecks: []statecheck.StateCheck{
 					statecheck.ExpectKnownValue(
-						"supabase-vault_secret.test",
+						"supabase-vault_secret.prod",
 						tfjsonpath.New("name"),
-						knownvalue.StringExact("test-secret-minimal"),
+						knownvalue.StringExact("test-secret-environment"),
 					),
 					statecheck.ExpectKnownValue(
-						"supabase-vault_secret.test",
-						tfjsonpath.New("value"),
-						knownvalue.StringExact("minimal-value"),
+						"supabase-vault_secret.prod",
+						tfjsonpath.New("labels"),
+						knownvalue.Null(),
 					),
 					statecheck.ExpectKnownValue(
-						"supabase-vault_secret.test",
-						tfjsonpath.New("id"),
-						knownvalue.NotNull(),
+						"data.supabase-vault_secret_value.prod",
+						tfjsonpath.New("value"),
+						knownvalue.StringExact("prod-value"),
 					),
 				},
 			},
+			// Importing by the stored name restores the environment
+			{
+				ResourceName:            "supabase-vault_secret.prod",
+				ImportState:             true,
+				ImportStateId:           "prod_test-secret-environment",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"value", "value_hash"},
+			},
 		},
 	})
 }
@@ -225,3 +811,619 @@ resource "supabase-vault_secret" "test" {
 
 	return config
 }
+
+func testAccVaultSecretResourceConfigImmutable(name, value, description string) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "supabase-vault_secret" "test" {
+  name        = %[1]q
+  value       = %[2]q
+  description = %[3]q
+  immutable   = true
+}
+`, name, value, description)
+}
+
+func TestVaultSecretResourceValidateConfig(t *testing.T) {
+	testCases := map[string]struct {
+		config    map[string]tftypes.Value
+		expectErr bool
+	}{
+		"valid": {
+			config: map[string]tftypes.Value{
+				"name":        tftypes.NewValue(tftypes.String, "api_key"),
+				"value":       tftypes.NewValue(tftypes.String, "secret"),
+				"description": tftypes.NewValue(tftypes.String, "API key"),
+			},
+		},
+		"write-only value": {
+			config: map[string]tftypes.Value{
+				"name":             tftypes.NewValue(tftypes.String, "api_key"),
+				"value_wo":         tftypes.NewValue(tftypes.String, "secret"),
+				"value_wo_version": tftypes.NewValue(tftypes.Number, 1),
+			},
+		},
+		"value and write-only value": {
+			config: map[string]tftypes.Value{
+				"name":     tftypes.NewValue(tftypes.String, "api_key"),
+				"value":    tftypes.NewValue(tftypes.String, "secret"),
+				"value_wo": tftypes.NewValue(tftypes.String, "secret"),
+			},
+			expectErr: true,
+		},
+		// Only required on create, which ModifyPlan checks
+		"no value": {
+			config: map[string]tftypes.Value{
+				"name": tftypes.NewValue(tftypes.String, "api_key"),
+			},
+		},
+		"connection override": {
+			config: map[string]tftypes.Value{
+				"name":  tftypes.NewValue(tftypes.String, "api_key"),
+				"value": tftypes.NewValue(tftypes.String, "secret"),
+				"connection": testConnectionOverrideValue(map[string]tftypes.Value{
+					"host":     tftypes.NewValue(tftypes.String, "db.other.supabase.co"),
+					"database": tftypes.NewValue(tftypes.String, "postgres"),
+				}),
+			},
+		},
+		"empty connection override": {
+			config: map[string]tftypes.Value{
+				"name":       tftypes.NewValue(tftypes.String, "api_key"),
+				"value":      tftypes.NewValue(tftypes.String, "secret"),
+				"connection": testConnectionOverrideValue(map[string]tftypes.Value{}),
+			},
+			expectErr: true,
+		},
+		"connection override with invalid port": {
+			config: map[string]tftypes.Value{
+				"name":  tftypes.NewValue(tftypes.String, "api_key"),
+				"value": tftypes.NewValue(tftypes.String, "secret"),
+				"connection": testConnectionOverrideValue(map[string]tftypes.Value{
+					"port": tftypes.NewValue(tftypes.Number, 0),
+				}),
+			},
+			expectErr: true,
+		},
+		"connection override with invalid database": {
+			config: map[string]tftypes.Value{
+				"name":  tftypes.NewValue(tftypes.String, "api_key"),
+				"value": tftypes.NewValue(tftypes.String, "secret"),
+				"connection": testConnectionOverrideValue(map[string]tftypes.Value{
+					"database": tftypes.NewValue(tftypes.String, "app?sslmode=disable"),
+				}),
+			},
+			expectErr: true,
+		},
+		"unknown name": {
+			config: map[string]tftypes.Value{
+				"name":  tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+				"value": tftypes.NewValue(tftypes.String, "secret"),
+			},
+		},
+		"empty name": {
+			config: map[string]tftypes.Value{
+				"name":  tftypes.NewValue(tftypes.String, ""),
+				"value": tftypes.NewValue(tftypes.String, "secret"),
+			},
+			expectErr: true,
+		},
+		"name too long": {
+			config: map[string]tftypes.Value{
+				"name":  tftypes.NewValue(tftypes.String, strings.Repeat("a", maxSecretNameLength+1)),
+				"value": tftypes.NewValue(tftypes.String, "secret"),
+			},
+			expectErr: true,
+		},
+		"name with NUL": {
+			config: map[string]tftypes.Value{
+				"name":  tftypes.NewValue(tftypes.String, "api\x00key"),
+				"value": tftypes.NewValue(tftypes.String, "secret"),
+			},
+			expectErr: true,
+		},
+		"description with footer": {
+			config: map[string]tftypes.Value{
+				"name":        tftypes.NewValue(tftypes.String, "api_key"),
+				"value":       tftypes.NewValue(tftypes.String, "secret"),
+				"description": tftypes.NewValue(tftypes.String, appendManagedByFooter("API key", defaultFooterSeparator, "1.0.0")),
+			},
+			expectErr: true,
+		},
+		"description with labels marker": {
+			config: map[string]tftypes.Value{
+				"name":        tftypes.NewValue(tftypes.String, "api_key"),
+				"value":       tftypes.NewValue(tftypes.String, "secret"),
+				"description": tftypes.NewValue(tftypes.String, "API key\n\n---\nLabels: {}"),
+			},
+			expectErr: true,
+		},
+		"nonce": {
+			config: map[string]tftypes.Value{
+				"name":  tftypes.NewValue(tftypes.String, "api_key"),
+				"value": tftypes.NewValue(tftypes.String, "secret"),
+				"nonce": tftypes.NewValue(tftypes.String, "00112233445566778899aabbccddeeff"),
+			},
+		},
+		"invalid nonce": {
+			config: map[string]tftypes.Value{
+				"name":  tftypes.NewValue(tftypes.String, "api_key"),
+				"value": tftypes.NewValue(tftypes.String, "secret"),
+				"nonce": tftypes.NewValue(tftypes.String, "not-hex"),
+			},
+			expectErr: true,
+		},
+		"nonce with adopt_existing": {
+			config: map[string]tftypes.Value{
+				"name":           tftypes.NewValue(tftypes.String, "api_key"),
+				"value":          tftypes.NewValue(tftypes.String, "secret"),
+				"nonce":          tftypes.NewValue(tftypes.String, "00112233445566778899aabbccddeeff"),
+				"adopt_existing": tftypes.NewValue(tftypes.Bool, true),
+			},
+			expectErr: true,
+		},
+		"validate_only with adopt_existing": {
+			config: map[string]tftypes.Value{
+				"name":           tftypes.NewValue(tftypes.String, "api_key"),
+				"value":          tftypes.NewValue(tftypes.String, "secret"),
+				"validate_only":  tftypes.NewValue(tftypes.Bool, true),
+				"adopt_existing": tftypes.NewValue(tftypes.Bool, true),
+			},
+			expectErr: true,
+		},
+		"validate_only with nonce": {
+			config: map[string]tftypes.Value{
+				"name":          tftypes.NewValue(tftypes.String, "api_key"),
+				"value":         tftypes.NewValue(tftypes.String, "secret"),
+				"validate_only": tftypes.NewValue(tftypes.Bool, true),
+				"nonce":         tftypes.NewValue(tftypes.String, "00112233445566778899aabbccddeeff"),
+			},
+			expectErr: true,
+		},
+		"content_addressed": {
+			config: map[string]tftypes.Value{
+				"name":              tftypes.NewValue(tftypes.String, "api_key"),
+				"value":             tftypes.NewValue(tftypes.String, "secret"),
+				"content_addressed": tftypes.NewValue(tftypes.Bool, true),
+			},
+		},
+		"content_addressed with value_wo": {
+			config: map[string]tftypes.Value{
+				"name":              tftypes.NewValue(tftypes.String, "api_key"),
+				"value_wo":          tftypes.NewValue(tftypes.String, "secret"),
+				"content_addressed": tftypes.NewValue(tftypes.Bool, true),
+			},
+			expectErr: true,
+		},
+		"content_addressed with adopt_existing": {
+			config: map[string]tftypes.Value{
+				"name":              tftypes.NewValue(tftypes.String, "api_key"),
+				"value":             tftypes.NewValue(tftypes.String, "secret"),
+				"content_addressed": tftypes.NewValue(tftypes.Bool, true),
+				"adopt_existing":    tftypes.NewValue(tftypes.Bool, true),
+			},
+			expectErr: true,
+		},
+		"content_addressed with reserved label": {
+			config: map[string]tftypes.Value{
+				"name":              tftypes.NewValue(tftypes.String, "api_key"),
+				"value":             tftypes.NewValue(tftypes.String, "secret"),
+				"content_addressed": tftypes.NewValue(tftypes.Bool, true),
+				"labels": tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, map[string]tftypes.Value{
+					contentHashLabel: tftypes.NewValue(tftypes.String, "abc"),
+				}),
+			},
+			expectErr: true,
+		},
+		"matching value_pattern": {
+			config: map[string]tftypes.Value{
+				"name":          tftypes.NewValue(tftypes.String, "api_key"),
+				"value":         tftypes.NewValue(tftypes.String, "sk_live_abc123"),
+				"value_pattern": tftypes.NewValue(tftypes.String, "^sk_live_[a-z0-9]+$"),
+			},
+		},
+		"value not matching value_pattern": {
+			config: map[string]tftypes.Value{
+				"name":          tftypes.NewValue(tftypes.String, "api_key"),
+				"value":         tftypes.NewValue(tftypes.String, "sk_test_abc123"),
+				"value_pattern": tftypes.NewValue(tftypes.String, "^sk_live_[a-z0-9]+$"),
+			},
+			expectErr: true,
+		},
+		"unknown value with value_pattern": {
+			config: map[string]tftypes.Value{
+				"name":          tftypes.NewValue(tftypes.String, "api_key"),
+				"value":         tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+				"value_pattern": tftypes.NewValue(tftypes.String, "^sk_live_[a-z0-9]+$"),
+			},
+		},
+		"invalid value_pattern": {
+			config: map[string]tftypes.Value{
+				"name":          tftypes.NewValue(tftypes.String, "api_key"),
+				"value":         tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+				"value_pattern": tftypes.NewValue(tftypes.String, "^sk_live_[a-z0-9+$"),
+			},
+			expectErr: true,
+		},
+		"expires_at": {
+			config: map[string]tftypes.Value{
+				"name":       tftypes.NewValue(tftypes.String, "api_key"),
+				"value":      tftypes.NewValue(tftypes.String, "secret"),
+				"expires_at": tftypes.NewValue(tftypes.String, "2030-01-31T00:00:00Z"),
+			},
+		},
+		"invalid expires_at": {
+			config: map[string]tftypes.Value{
+				"name":       tftypes.NewValue(tftypes.String, "api_key"),
+				"value":      tftypes.NewValue(tftypes.String, "secret"),
+				"expires_at": tftypes.NewValue(tftypes.String, "2030-01-31"),
+			},
+			expectErr: true,
+		},
+		"expires_at with reserved label": {
+			config: map[string]tftypes.Value{
+				"name":       tftypes.NewValue(tftypes.String, "api_key"),
+				"value":      tftypes.NewValue(tftypes.String, "secret"),
+				"expires_at": tftypes.NewValue(tftypes.String, "2030-01-31T00:00:00Z"),
+				"labels": tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, map[string]tftypes.Value{
+					expiryLabel: tftypes.NewValue(tftypes.String, "2031-01-31T00:00:00Z"),
+				}),
+			},
+			expectErr: true,
+		},
+		"environment": {
+			config: map[string]tftypes.Value{
+				"name":        tftypes.NewValue(tftypes.String, "api_key"),
+				"value":       tftypes.NewValue(tftypes.String, "secret"),
+				"environment": tftypes.NewValue(tftypes.String, "prod"),
+			},
+		},
+		"invalid environment": {
+			config: map[string]tftypes.Value{
+				"name":        tftypes.NewValue(tftypes.String, "api_key"),
+				"value":       tftypes.NewValue(tftypes.String, "secret"),
+				"environment": tftypes.NewValue(tftypes.String, "prod_eu"),
+			},
+			expectErr: true,
+		},
+		"environment with reserved label": {
+			config: map[string]tftypes.Value{
+				"name":        tftypes.NewValue(tftypes.String, "api_key"),
+				"value":       tftypes.NewValue(tftypes.String, "secret"),
+				"environment": tftypes.NewValue(tftypes.String, "prod"),
+				"labels": tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, map[string]tftypes.Value{
+					environmentLabel: tftypes.NewValue(tftypes.String, "staging"),
+				}),
+			},
+			expectErr: true,
+		},
+		"invalid template": {
+			config: map[string]tftypes.Value{
+				"name":           tftypes.NewValue(tftypes.String, "api_key"),
+				"value":          tftypes.NewValue(tftypes.String, "{{.missing}}"),
+				"value_template": tftypes.NewValue(tftypes.Bool, true),
+			},
+			expectErr: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			r := &VaultSecretResource{}
+
+			schemaResp := &fwresource.SchemaResponse{}
+			r.Schema(ctx, fwresource.SchemaRequest{}, schemaResp)
+
+			req := fwresource.ValidateConfigRequest{
+				Config: tfsdk.Config{
+					Schema: schemaResp.Schema,
+					Raw:    testConfigValue(t, schemaResp.Schema.Type(), testCase.config),
+				},
+			}
+			resp := &fwresource.ValidateConfigResponse{}
+			r.ValidateConfig(ctx, req, resp)
+
+			if testCase.expectErr && !resp.Diagnostics.HasError() {
+				t.Fatal("expected an error, got none")
+			}
+			if !testCase.expectErr && resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected error: %v", resp.Diagnostics)
+			}
+		})
+	}
+}
+
+// testConnectionOverrideValue builds a connection override object from
+// values, leaving every attribute not in values null.
+func testConnectionOverrideValue(values map[string]tftypes.Value) tftypes.Value {
+	objectType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"host":     tftypes.String,
+		"port":     tftypes.Number,
+		"database": tftypes.String,
+		"user":     tftypes.String,
+		"password": tftypes.String,
+	}}
+
+	attributes := make(map[string]tftypes.Value, len(objectType.AttributeTypes))
+	for name, attributeType := range objectType.AttributeTypes {
+		attributes[name] = tftypes.NewValue(attributeType, nil)
+		if value, ok := values[name]; ok {
+			attributes[name] = value
+		}
+	}
+
+	return tftypes.NewValue(objectType, attributes)
+}
+
+func TestVaultSecretResourceUpdateImmutable(t *testing.T) {
+	state := map[string]tftypes.Value{
+		"id":         tftypes.NewValue(tftypes.String, "00000000-0000-0000-0000-000000000001"),
+		"name":       tftypes.NewValue(tftypes.String, "bootstrap_token"),
+		"value":      tftypes.NewValue(tftypes.String, "first"),
+		"value_hash": tftypes.NewValue(tftypes.String, hashSecretValue("first")),
+		"immutable":  tftypes.NewValue(tftypes.Bool, true),
+	}
+
+	testCases := map[string]struct {
+		state map[string]tftypes.Value
+		plan  map[string]tftypes.Value
+	}{
+		"value": {
+			state: state,
+			plan: map[string]tftypes.Value{
+				"id":         tftypes.NewValue(tftypes.String, "00000000-0000-0000-0000-000000000001"),
+				"name":       tftypes.NewValue(tftypes.String, "bootstrap_token"),
+				"value":      tftypes.NewValue(tftypes.String, "second"),
+				"value_hash": tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+				"immutable":  tftypes.NewValue(tftypes.Bool, true),
+			},
+		},
+		"write-only value version": {
+			state: map[string]tftypes.Value{
+				"id":               tftypes.NewValue(tftypes.String, "00000000-0000-0000-0000-000000000001"),
+				"name":             tftypes.NewValue(tftypes.String, "bootstrap_token"),
+				"value_wo_version": tftypes.NewValue(tftypes.Number, 1),
+				"immutable":        tftypes.NewValue(tftypes.Bool, true),
+			},
+			plan: map[string]tftypes.Value{
+				"id":               tftypes.NewValue(tftypes.String, "00000000-0000-0000-0000-000000000001"),
+				"name":             tftypes.NewValue(tftypes.String, "bootstrap_token"),
+				"value_wo":         tftypes.NewValue(tftypes.String, "second"),
+				"value_wo_version": tftypes.NewValue(tftypes.Number, 2),
+				"immutable":        tftypes.NewValue(tftypes.Bool, true),
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			// No pool: the update must be refused before any query runs
+			r := &VaultSecretResource{providerData: &ProviderData{}}
+
+			schemaResp := &fwresource.SchemaResponse{}
+			r.Schema(ctx, fwresource.SchemaRequest{}, schemaResp)
+			schema := schemaResp.Schema
+
+			// Write-only values are only present in the configuration
+			plan := make(map[string]tftypes.Value, len(testCase.plan))
+			for attribute, value := range testCase.plan {
+				if attribute != "value_wo" {
+					plan[attribute] = value
+				}
+			}
+
+			req := fwresource.UpdateRequest{
+				Config: tfsdk.Config{Schema: schema, Raw: testConfigValue(t, schema.Type(), testCase.plan)},
+				Plan:   tfsdk.Plan{Schema: schema, Raw: testConfigValue(t, schema.Type(), plan)},
+				State:  tfsdk.State{Schema: schema, Raw: testConfigValue(t, schema.Type(), testCase.state)},
+			}
+			resp := &fwresource.UpdateResponse{
+				State: tfsdk.State{Schema: schema, Raw: testConfigValue(t, schema.Type(), testCase.state)},
+			}
+			r.Update(ctx, req, resp)
+
+			if !resp.Diagnostics.HasError() {
+				t.Fatal("expected the update to be refused, got no error")
+			}
+			if summary := resp.Diagnostics.Errors()[0].Summary(); summary != "Immutable vault secret" {
+				t.Errorf("expected an immutable secret error, got: %s", summary)
+			}
+		})
+	}
+}
+
+func TestVaultSecretResourceDeleteValidateOnlyReadOnly(t *testing.T) {
+	ctx := context.Background()
+	// No pool: nothing was stored, so no query may run
+	r := &VaultSecretResource{providerData: &ProviderData{ReadOnly: true}}
+
+	schemaResp := &fwresource.SchemaResponse{}
+	r.Schema(ctx, fwresource.SchemaRequest{}, schemaResp)
+	schema := schemaResp.Schema
+
+	state := testConfigValue(t, schema.Type(), map[string]tftypes.Value{
+		"name":          tftypes.NewValue(tftypes.String, "api_key"),
+		"value":         tftypes.NewValue(tftypes.String, "secret"),
+		"validate_only": tftypes.NewValue(tftypes.Bool, true),
+	})
+	resp := &fwresource.DeleteResponse{
+		State: tfsdk.State{Schema: schema, Raw: state},
+	}
+	r.Delete(ctx, fwresource.DeleteRequest{State: tfsdk.State{Schema: schema, Raw: state}}, resp)
+
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("expected a validate_only secret to be destroyed by a read-only provider, got: %v", resp.Diagnostics)
+	}
+}
+
+func TestVaultSecretResourceModifyPlanRequiresValueOnCreate(t *testing.T) {
+	testCases := map[string]struct {
+		config    map[string]tftypes.Value
+		expectErr bool
+	}{
+		"value": {
+			config: map[string]tftypes.Value{
+				"name":  tftypes.NewValue(tftypes.String, "api_key"),
+				"value": tftypes.NewValue(tftypes.String, "secret"),
+			},
+		},
+		"write-only value": {
+			config: map[string]tftypes.Value{
+				"name":             tftypes.NewValue(tftypes.String, "api_key"),
+				"value_wo":         tftypes.NewValue(tftypes.String, "secret"),
+				"value_wo_version": tftypes.NewValue(tftypes.Number, 1),
+			},
+		},
+		"no value": {
+			config: map[string]tftypes.Value{
+				"name": tftypes.NewValue(tftypes.String, "api_key"),
+			},
+			expectErr: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			r := &VaultSecretResource{}
+
+			schemaResp := &fwresource.SchemaResponse{}
+			r.Schema(ctx, fwresource.SchemaRequest{}, schemaResp)
+			schema := schemaResp.Schema
+
+			// Write-only values are only present in the configuration
+			plan := make(map[string]tftypes.Value, len(testCase.config))
+			for attribute, value := range testCase.config {
+				if attribute != "value_wo" {
+					plan[attribute] = value
+				}
+			}
+
+			req := fwresource.ModifyPlanRequest{
+				Config: tfsdk.Config{Schema: schema, Raw: testConfigValue(t, schema.Type(), testCase.config)},
+				Plan:   tfsdk.Plan{Schema: schema, Raw: testConfigValue(t, schema.Type(), plan)},
+				State:  tfsdk.State{Schema: schema, Raw: tftypes.NewValue(schema.Type().TerraformType(ctx), nil)},
+			}
+			resp := &fwresource.ModifyPlanResponse{Plan: req.Plan}
+			r.ModifyPlan(ctx, req, resp)
+
+			if testCase.expectErr && !resp.Diagnostics.HasError() {
+				t.Fatal("expected an error, got none")
+			}
+			if !testCase.expectErr && resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected error: %v", resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestVaultSecretResourceModifyPlanWarnsValueInState(t *testing.T) {
+	testCases := map[string]struct {
+		config      map[string]tftypes.Value
+		plan        map[string]tftypes.Value
+		state       map[string]tftypes.Value
+		suppress    bool
+		expectWarns bool
+	}{
+		"create with value": {
+			plan: map[string]tftypes.Value{
+				"name":  tftypes.NewValue(tftypes.String, "api_key"),
+				"value": tftypes.NewValue(tftypes.String, "secret"),
+			},
+			expectWarns: true,
+		},
+		"create with value suppressed": {
+			plan: map[string]tftypes.Value{
+				"name":  tftypes.NewValue(tftypes.String, "api_key"),
+				"value": tftypes.NewValue(tftypes.String, "secret"),
+			},
+			suppress: true,
+		},
+		"create with write-only value": {
+			config: map[string]tftypes.Value{
+				"name":             tftypes.NewValue(tftypes.String, "api_key"),
+				"value_wo":         tftypes.NewValue(tftypes.String, "secret"),
+				"value_wo_version": tftypes.NewValue(tftypes.Number, 1),
+			},
+			plan: map[string]tftypes.Value{
+				"name":             tftypes.NewValue(tftypes.String, "api_key"),
+				"value_wo_version": tftypes.NewValue(tftypes.Number, 1),
+			},
+		},
+		"changed value": {
+			plan: map[string]tftypes.Value{
+				"name":  tftypes.NewValue(tftypes.String, "api_key"),
+				"value": tftypes.NewValue(tftypes.String, "new-secret"),
+			},
+			state: map[string]tftypes.Value{
+				"name":  tftypes.NewValue(tftypes.String, "api_key"),
+				"value": tftypes.NewValue(tftypes.String, "secret"),
+			},
+			expectWarns: true,
+		},
+		"unchanged value": {
+			plan: map[string]tftypes.Value{
+				"name":        tftypes.NewValue(tftypes.String, "api_key"),
+				"value":       tftypes.NewValue(tftypes.String, "secret"),
+				"description": tftypes.NewValue(tftypes.String, "API key"),
+			},
+			state: map[string]tftypes.Value{
+				"name":  tftypes.NewValue(tftypes.String, "api_key"),
+				"value": tftypes.NewValue(tftypes.String, "secret"),
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			r := &VaultSecretResource{providerData: &ProviderData{SuppressStateWarnings: testCase.suppress}}
+
+			schemaResp := &fwresource.SchemaResponse{}
+			r.Schema(ctx, fwresource.SchemaRequest{}, schemaResp)
+			schema := schemaResp.Schema
+
+			state := tftypes.NewValue(schema.Type().TerraformType(ctx), nil)
+			if testCase.state != nil {
+				state = testConfigValue(t, schema.Type(), testCase.state)
+			}
+
+			// Write-only values are only present in the configuration
+			config := testCase.config
+			if config == nil {
+				config = testCase.plan
+			}
+
+			req := fwresource.ModifyPlanRequest{
+				Config: tfsdk.Config{Schema: schema, Raw: testConfigValue(t, schema.Type(), config)},
+				Plan:   tfsdk.Plan{Schema: schema, Raw: testConfigValue(t, schema.Type(), testCase.plan)},
+				State:  tfsdk.State{Schema: schema, Raw: state},
+			}
+			resp := &fwresource.ModifyPlanResponse{Plan: req.Plan}
+			r.ModifyPlan(ctx, req, resp)
+
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected error: %v", resp.Diagnostics)
+			}
+			if warns := resp.Diagnostics.WarningsCount() > 0; warns != testCase.expectWarns {
+				t.Errorf("expected warning %t, got: %v", testCase.expectWarns, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestImportedValue(t *testing.T) {
+	value, err := importedValue("api_key", sql.NullString{String: "hunter2", Valid: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if value != "hunter2" {
+		t.Errorf("expected %q, got %q", "hunter2", value)
+	}
+
+	// A secret whose key is missing or invalid decrypts to NULL
+	if _, err := importedValue("api_key", sql.NullString{}); err == nil || !strings.Contains(err.Error(), "could not be decrypted") {
+		t.Errorf("expected a decryption error, got: %v", err)
+	}
+}
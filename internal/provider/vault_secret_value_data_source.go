@@ -0,0 +1,210 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/jackc/pgx/v5"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &VaultSecretValueDataSource{}
+
+func NewVaultSecretValueDataSource() datasource.DataSource {
+	return &VaultSecretValueDataSource{}
+}
+
+// VaultSecretValueDataSource defines the data source implementation.
+type VaultSecretValueDataSource struct {
+	providerData *ProviderData
+}
+
+// VaultSecretValueDataSourceModel describes the data source data model.
+type VaultSecretValueDataSourceModel struct {
+	Name        types.String `tfsdk:"name"`
+	Environment types.String `tfsdk:"environment"`
+	KeyID       types.String `tfsdk:"key_id"`
+	Default     types.String `tfsdk:"default"`
+	Value       types.String `tfsdk:"value"`
+}
+
+// decryptedValue returns the value vault.decrypted_secrets returned for the
+// secret named secretName, which is NULL when it can't be decrypted.
+func decryptedValue(secretName string, decrypted sql.NullString) (string, error) {
+	if !decrypted.Valid {
+		return "", fmt.Errorf("secret %q could not be decrypted: vault.decrypted_secrets returned no value, which happens when its key is missing or invalid. "+
+			"Check the secret's key_id, or store its value again", secretName)
+	}
+
+	return decrypted.String, nil
+}
+
+func (d *VaultSecretValueDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_secret_value"
+}
+
+func (d *VaultSecretValueDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Reads the decrypted value of a secret by name, falling back to `default` when the secret doesn't exist. " +
+			"The value is sensitive and is stored in Terraform state; the connecting role must be able to read `vault.decrypted_secrets`.",
+
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Name of the secret to read, as configured on its `supabase-vault_secret`",
+				Required:            true,
+			},
+			"environment": schema.StringAttribute{
+				MarkdownDescription: "Environment the secret belongs to, as set on its `supabase-vault_secret`. Unless the provider's `environment_naming` is `label`, the secret is looked up under the `<environment>_` prefixed name.",
+				Optional:            true,
+			},
+			"key_id": schema.StringAttribute{
+				MarkdownDescription: "UUID of the key the secret is expected to be encrypted with. When multiple keys are in use, reading fails unless the secret's `key_id` matches, rather than returning a value decrypted with another key.",
+				Optional:            true,
+			},
+			"default": schema.StringAttribute{
+				MarkdownDescription: "Value returned when no secret with this name exists. If not specified, a missing secret is an error.",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"value": schema.StringAttribute{
+				MarkdownDescription: "Decrypted secret value, or `default` if the secret doesn't exist",
+				Computed:            true,
+				Sensitive:           true,
+			},
+		},
+	}
+}
+
+func (d *VaultSecretValueDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.providerData = providerData
+}
+
+func (d *VaultSecretValueDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data VaultSecretValueDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.KeyID.IsNull() {
+		if err := validateSecretID(data.KeyID.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("key_id"),
+				"Invalid key_id",
+				err.Error(),
+			)
+			return
+		}
+	}
+
+	if !data.Environment.IsNull() {
+		if err := validateEnvironment(data.Environment.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("environment"),
+				"Invalid environment attribute",
+				err.Error(),
+			)
+			return
+		}
+	}
+
+	// Look the secret up under the name supabase-vault_secret stores it as
+	secretName, err := encodeSecretName(d.providerData.environmentName(data.Name.ValueString(), data.Environment), d.providerData.AllowInvalidUTF8Names)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("name"), "Invalid secret name", err.Error())
+		return
+	}
+
+	query := `
+		SELECT decrypted_secret, key_id
+		FROM vault.decrypted_secrets
+		WHERE name = $1
+	`
+
+	var decrypted, keyID sql.NullString
+	err = d.providerData.queryRow(ctx, query, secretName).Scan(&decrypted, &keyID)
+
+	if err == pgx.ErrNoRows {
+		if data.Default.IsNull() {
+			resp.Diagnostics.AddError(
+				"Secret not found",
+				fmt.Sprintf("No secret found with name: %s. Set default to tolerate a missing secret.", data.Name.ValueString()),
+			)
+			return
+		}
+
+		tflog.Debug(ctx, "secret not found, using default value", map[string]interface{}{
+			"name": data.Name.ValueString(),
+		})
+		data.Value = data.Default
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		return
+	}
+
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to read vault secret value",
+			withRemediation(fmt.Sprintf("Error reading decrypted secret: %s", err), err),
+		)
+		return
+	}
+
+	if !data.KeyID.IsNull() {
+		if err := checkSecretKeyID(keyID, data.KeyID.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("key_id"),
+				"Secret key mismatch",
+				fmt.Sprintf("Secret %q can't be read with the expected key: %s.", data.Name.ValueString(), err),
+			)
+			return
+		}
+	}
+
+	// A secret that exists but can't be decrypted is an error, not a reason
+	// to fall back to default
+	value, err := decryptedValue(data.Name.ValueString(), decrypted)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Secret could not be decrypted",
+			err.Error(),
+		)
+		return
+	}
+
+	data.Value = types.StringValue(value)
+
+	tflog.Trace(ctx, "read a vault secret value", map[string]interface{}{
+		"name": data.Name.ValueString(),
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
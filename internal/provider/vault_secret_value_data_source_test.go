@@ -0,0 +1,90 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestAccVaultSecretValueDataSource(t *testing.T) {
+	// Skip if TF_ACC is not set
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests skipped unless env 'TF_ACC' set")
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccVaultSecretValueDataSourceConfig("test-secret-value", "the-value"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.supabase-vault_secret_value.existing",
+						tfjsonpath.New("value"),
+						knownvalue.StringExact("the-value"),
+					),
+					statecheck.ExpectKnownValue(
+						"data.supabase-vault_secret_value.keyed",
+						tfjsonpath.New("value"),
+						knownvalue.StringExact("the-value"),
+					),
+					statecheck.ExpectKnownValue(
+						"data.supabase-vault_secret_value.missing",
+						tfjsonpath.New("value"),
+						knownvalue.StringExact("fallback"),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestDecryptedValue(t *testing.T) {
+	value, err := decryptedValue("api_key", sql.NullString{String: "hunter2", Valid: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if value != "hunter2" {
+		t.Errorf("expected %q, got %q", "hunter2", value)
+	}
+
+	// A secret that can't be decrypted reads as NULL, and must not be
+	// mistaken for a missing one
+	if _, err := decryptedValue("api_key", sql.NullString{}); err == nil || !strings.Contains(err.Error(), "could not be decrypted") {
+		t.Errorf("expected a decryption error, got: %v", err)
+	}
+}
+
+func testAccVaultSecretValueDataSourceConfig(name, value string) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "supabase-vault_secret" "test" {
+  name  = %[1]q
+  value = %[2]q
+}
+
+data "supabase-vault_secret_value" "existing" {
+  name = supabase-vault_secret.test.name
+}
+
+data "supabase-vault_secret_value" "keyed" {
+  name   = supabase-vault_secret.test.name
+  key_id = supabase-vault_secret.test.key_id
+}
+
+data "supabase-vault_secret_value" "missing" {
+  name    = "%[1]s-does-not-exist"
+  default = "fallback"
+}
+`, name, value)
+}
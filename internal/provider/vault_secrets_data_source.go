@@ -0,0 +1,337 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/jackc/pgx/v5"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &VaultSecretsDataSource{}
+
+func NewVaultSecretsDataSource() datasource.DataSource {
+	return &VaultSecretsDataSource{}
+}
+
+// VaultSecretsDataSource defines the data source implementation.
+type VaultSecretsDataSource struct {
+	providerData *ProviderData
+}
+
+// VaultSecretsDataSourceModel describes the data source data model.
+type VaultSecretsDataSourceModel struct {
+	Limit      types.Int64                         `tfsdk:"limit"`
+	Offset     types.Int64                         `tfsdk:"offset"`
+	Names      types.List                          `tfsdk:"names"`
+	Secrets    []VaultSecretMetadataModel          `tfsdk:"secrets"`
+	ByName     map[string]VaultSecretMetadataModel `tfsdk:"by_name"`
+	Missing    types.List                          `tfsdk:"missing"`
+	JSON       types.String                        `tfsdk:"json"`
+	TotalCount types.Int64                         `tfsdk:"total_count"`
+}
+
+// VaultSecretMetadataModel describes the non-sensitive metadata of a secret.
+type VaultSecretMetadataModel struct {
+	ID          types.String `tfsdk:"id"`
+	Name        types.String `tfsdk:"name"`
+	Description types.String `tfsdk:"description"`
+	Labels      types.Map    `tfsdk:"labels"`
+	KeyID       types.String `tfsdk:"key_id"`
+}
+
+// secretMetadataRow is a row of secret metadata read from vault.secrets.
+type secretMetadataRow struct {
+	ID          string  `db:"id" json:"id"`
+	Name        *string `db:"name" json:"name"`
+	Description string  `db:"description" json:"description"`
+	KeyID       *string `db:"key_id" json:"key_id"`
+
+	// Labels are parsed from the description rather than read from a column.
+	Labels map[string]string `db:"-" json:"labels"`
+
+	// TotalCount is the number of secrets before limit and offset applied.
+	TotalCount int64 `db:"total_count" json:"-"`
+}
+
+// listSecretMetadataQuery lists the metadata of every secret in name order,
+// paginated by a LIMIT of $1 (NULL for all rows) and an OFFSET of $2, and
+// restricted to the names in $3 unless it is NULL. Metadata is stored in
+// plaintext in vault.secrets, so no decryption is needed. The window count
+// reports the unpaginated total in the same round trip.
+const listSecretMetadataQuery = `
+	SELECT id, name, description, key_id, count(*) OVER () AS total_count
+	FROM vault.secrets
+	WHERE $3::text[] IS NULL OR name = ANY($3)
+	ORDER BY name, id
+	LIMIT $1 OFFSET $2
+`
+
+// countSecretsQuery counts the secrets listSecretMetadataQuery pages through.
+const countSecretsQuery = "SELECT count(*) FROM vault.secrets WHERE $1::text[] IS NULL OR name = ANY($1)"
+
+// missingNames returns the requested names no secret in rows has, in the
+// order they were requested.
+func missingNames(requested []string, rows []secretMetadataRow) []string {
+	found := make(map[string]bool, len(rows))
+	for _, row := range rows {
+		if row.Name != nil {
+			found[*row.Name] = true
+		}
+	}
+
+	missing := []string{}
+	for _, name := range requested {
+		if !found[name] {
+			missing = append(missing, name)
+			// Report a name requested twice only once
+			found[name] = true
+		}
+	}
+
+	return missing
+}
+
+// secretMetadataAttributes are the attributes of each secret the data
+// source returns.
+func secretMetadataAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"id": schema.StringAttribute{
+			MarkdownDescription: "Secret UUID",
+			Computed:            true,
+		},
+		"name": schema.StringAttribute{
+			MarkdownDescription: "Secret name",
+			Computed:            true,
+		},
+		"description": schema.StringAttribute{
+			MarkdownDescription: "Secret description, without the labels block and managed-by footer unless the provider sets `show_footer_on_read`",
+			Computed:            true,
+		},
+		"labels": schema.MapAttribute{
+			MarkdownDescription: "Labels set through the `labels` attribute of `supabase-vault_secret`",
+			ElementType:         types.StringType,
+			Computed:            true,
+		},
+		"key_id": schema.StringAttribute{
+			MarkdownDescription: "Encryption key ID",
+			Computed:            true,
+		},
+	}
+}
+
+func (d *VaultSecretsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_secrets"
+}
+
+func (d *VaultSecretsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists the metadata of all secrets in Supabase Vault. Secret values are never read.",
+
+		Attributes: map[string]schema.Attribute{
+			"limit": schema.Int64Attribute{
+				MarkdownDescription: "Maximum number of secrets to return. If not specified, all secrets are returned.",
+				Optional:            true,
+			},
+			"offset": schema.Int64Attribute{
+				MarkdownDescription: "Number of secrets to skip, in name order, before returning any. Combine with `limit` to page through large vaults. Defaults to `0`.",
+				Optional:            true,
+			},
+			"names": schema.ListAttribute{
+				MarkdownDescription: "Only return the secrets with these names, fetched in a single query. Names without a secret are left out and listed in `missing`.",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"secrets": schema.ListNestedAttribute{
+				MarkdownDescription: "Secrets ordered by name",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: secretMetadataAttributes(),
+				},
+			},
+			"by_name": schema.MapNestedAttribute{
+				MarkdownDescription: "The same secrets keyed by name. Secrets without a name are only listed in `secrets`.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: secretMetadataAttributes(),
+				},
+			},
+			"missing": schema.ListAttribute{
+				MarkdownDescription: "Names from `names` no secret has, in the order given. Null when `names` isn't set.",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
+			"json": schema.StringAttribute{
+				MarkdownDescription: "The same secrets as a JSON array, for use with `jsondecode` or external tools",
+				Computed:            true,
+			},
+			"total_count": schema.Int64Attribute{
+				MarkdownDescription: "Total number of secrets in the vault, regardless of `limit` and `offset`",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *VaultSecretsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.providerData = providerData
+}
+
+func (d *VaultSecretsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data VaultSecretsDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// A NULL limit returns every row
+	var limit *int64
+	if !data.Limit.IsNull() {
+		if data.Limit.ValueInt64() < 1 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("limit"),
+				"Invalid limit",
+				fmt.Sprintf("limit must be at least 1, got: %d", data.Limit.ValueInt64()),
+			)
+		}
+		limit = data.Limit.ValueInt64Pointer()
+	}
+
+	offset := data.Offset.ValueInt64()
+	if offset < 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("offset"),
+			"Invalid offset",
+			fmt.Sprintf("offset must not be negative, got: %d", offset),
+		)
+	}
+
+	// A NULL array lists every secret, while an empty one matches none
+	var names []string
+	var namesArg any
+	if !data.Names.IsNull() {
+		resp.Diagnostics.Append(data.Names.ElementsAs(ctx, &names, false)...)
+		if names == nil {
+			names = []string{}
+		}
+		namesArg = names
+
+		// Paging would leave names out that aren't missing
+		if !data.Limit.IsNull() || !data.Offset.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("names"),
+				"Conflicting names attribute",
+				"names fetches exactly the named secrets, so it can't be combined with limit or offset.",
+			)
+		}
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	rows, err := collectRows(ctx, d.providerData, pgx.RowToStructByName[secretMetadataRow], listSecretMetadataQuery, limit, offset, namesArg)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to list vault secrets",
+			withRemediation(fmt.Sprintf("Error reading secret metadata: %s", err), err),
+		)
+		return
+	}
+
+	// A page past the end has no rows to carry the total, so count separately
+	var totalCount int64
+	if len(rows) > 0 {
+		totalCount = rows[0].TotalCount
+	} else if offset > 0 {
+		if err := d.providerData.queryRow(ctx, countSecretsQuery, namesArg).Scan(&totalCount); err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to list vault secrets",
+				withRemediation(fmt.Sprintf("Error counting secrets: %s", err), err),
+			)
+			return
+		}
+	}
+	data.TotalCount = types.Int64Value(totalCount)
+
+	data.Secrets = make([]VaultSecretMetadataModel, 0, len(rows))
+	data.ByName = make(map[string]VaultSecretMetadataModel, len(rows))
+	for i := range rows {
+		description, labels := splitLabels(stripManagedByFooter(rows[i].Description, d.providerData.footerSeparator()))
+		if !d.providerData.ShowFooterOnRead {
+			rows[i].Description = description
+		}
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		rows[i].Labels = labels
+
+		labelsValue, diags := types.MapValueFrom(ctx, types.StringType, labels)
+		resp.Diagnostics.Append(diags...)
+
+		secret := VaultSecretMetadataModel{
+			ID:          types.StringValue(rows[i].ID),
+			Name:        types.StringPointerValue(rows[i].Name),
+			Description: types.StringValue(rows[i].Description),
+			Labels:      labelsValue,
+			KeyID:       types.StringPointerValue(rows[i].KeyID),
+		}
+		data.Secrets = append(data.Secrets, secret)
+		if rows[i].Name != nil {
+			data.ByName[*rows[i].Name] = secret
+		}
+	}
+
+	data.Missing = types.ListNull(types.StringType)
+	if !data.Names.IsNull() {
+		missing, diags := types.ListValueFrom(ctx, types.StringType, missingNames(names, rows))
+		resp.Diagnostics.Append(diags...)
+		data.Missing = missing
+	}
+
+	encoded, err := json.Marshal(rows)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to encode vault secrets",
+			fmt.Sprintf("Error encoding secret metadata as JSON: %s", err),
+		)
+		return
+	}
+	data.JSON = types.StringValue(string(encoded))
+
+	tflog.Trace(ctx, "listed vault secrets", map[string]interface{}{
+		"count":       len(rows),
+		"total_count": totalCount,
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,131 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestAccVaultSecretsDataSource(t *testing.T) {
+	// Skip if TF_ACC is not set
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests skipped unless env 'TF_ACC' set")
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccVaultSecretsDataSourceConfig(),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.supabase-vault_secrets.all",
+						tfjsonpath.New("secrets"),
+						knownvalue.ListPartial(map[int]knownvalue.Check{}),
+					),
+					statecheck.ExpectKnownValue(
+						"data.supabase-vault_secrets.all",
+						tfjsonpath.New("json"),
+						knownvalue.NotNull(),
+					),
+					statecheck.ExpectKnownValue(
+						"data.supabase-vault_secrets.first",
+						tfjsonpath.New("secrets"),
+						knownvalue.ListSizeExact(1),
+					),
+					statecheck.ExpectKnownValue(
+						"data.supabase-vault_secrets.first",
+						tfjsonpath.New("total_count"),
+						knownvalue.NotNull(),
+					),
+					statecheck.ExpectKnownValue(
+						"data.supabase-vault_secrets.named",
+						tfjsonpath.New("by_name").AtMapKey("test-secrets-data-source").AtMapKey("description"),
+						knownvalue.StringExact("Listed by the data source"),
+					),
+					statecheck.ExpectKnownValue(
+						"data.supabase-vault_secrets.named",
+						tfjsonpath.New("missing"),
+						knownvalue.ListExact([]knownvalue.Check{knownvalue.StringExact("test-secrets-data-source-missing")}),
+					),
+					statecheck.ExpectKnownValue(
+						"data.supabase-vault_secrets.named",
+						tfjsonpath.New("total_count"),
+						knownvalue.Int64Exact(1),
+					),
+				},
+			},
+		},
+	})
+}
+
+func testAccVaultSecretsDataSourceConfig() string {
+	return testAccProviderConfig() + `
+resource "supabase-vault_secret" "test" {
+  name        = "test-secrets-data-source"
+  value       = "listed-value"
+  description = "Listed by the data source"
+}
+
+data "supabase-vault_secrets" "all" {
+  depends_on = [supabase-vault_secret.test]
+}
+
+data "supabase-vault_secrets" "first" {
+  limit = 1
+
+  depends_on = [supabase-vault_secret.test]
+}
+
+data "supabase-vault_secrets" "named" {
+  names = [supabase-vault_secret.test.name, "test-secrets-data-source-missing"]
+}
+`
+}
+
+func TestMissingNames(t *testing.T) {
+	named := func(name string) secretMetadataRow {
+		return secretMetadataRow{Name: &name}
+	}
+
+	testCases := map[string]struct {
+		requested []string
+		rows      []secretMetadataRow
+		expected  []string
+	}{
+		"all found": {
+			requested: []string{"a", "b"},
+			rows:      []secretMetadataRow{named("a"), named("b")},
+			expected:  []string{},
+		},
+		"in requested order": {
+			requested: []string{"c", "a", "b"},
+			rows:      []secretMetadataRow{named("a")},
+			expected:  []string{"c", "b"},
+		},
+		"requested twice": {
+			requested: []string{"b", "b"},
+			rows:      []secretMetadataRow{named("a"), {}},
+			expected:  []string{"b"},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			missing := missingNames(testCase.requested, testCase.rows)
+
+			if !reflect.DeepEqual(missing, testCase.expected) {
+				t.Errorf("expected %v, got %v", testCase.expected, missing)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"flag"
 	"log"
+	"time"
 
 	"github.com/TheCodedCloud/terraform-provider-supabase-vault/internal/provider"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
@@ -32,7 +33,20 @@ func main() {
 		Debug:   debug,
 	}
 
-	err := providerserver.Serve(context.Background(), provider.New(version), opts)
+	// Tracing stays off unless an OTLP exporter is configured via OTEL_* env vars
+	shutdownTracing, err := provider.InitTracing(context.Background(), version)
+	if err != nil {
+		log.Printf("[WARN] OpenTelemetry tracing disabled: %s", err)
+	}
+
+	err = providerserver.Serve(context.Background(), provider.New(version), opts)
+
+	// Flush buffered spans once Terraform stops the provider
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	if shutdownErr := shutdownTracing(shutdownCtx); shutdownErr != nil {
+		log.Printf("[WARN] Unable to flush OpenTelemetry spans: %s", shutdownErr)
+	}
+	cancel()
 
 	if err != nil {
 		log.Fatal(err.Error())